@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	liburl "net/url"
+	"os"
+	"strconv"
+
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Global options.
+type Options struct {
+	// Path to the kubeconfig.
+	Kubeconfig string
+	// Namespace.
+	Namespace string
+	// Inventory service URL.
+	InventoryURL string
+	// Inventory service CA certificate path.
+	InventoryCA string
+	// Print the resources instead of creating them.
+	DryRun bool
+	// k8s client.
+	client client.Client
+}
+
+// Add the global flags.
+func (r *Options) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig.")
+	fs.StringVar(&r.Namespace, "n", "", "Namespace (default: the kubeconfig context namespace).")
+	fs.StringVar(&r.InventoryURL, "inventory", os.Getenv("FORKLIFT_INVENTORY"), "Inventory service URL.")
+	fs.StringVar(&r.InventoryCA, "inventory-ca", "", "Inventory service CA certificate path.")
+	fs.BoolVar(&r.DryRun, "dry-run", false, "Print the resources (YAML) instead of creating them.")
+}
+
+// Complete the options.
+func (r *Options) Complete() (err error) {
+	if r.Kubeconfig != "" {
+		err = os.Setenv(clientcmd.RecommendedConfigPathEnvVar, r.Kubeconfig)
+		if err != nil {
+			return
+		}
+	}
+	if r.Namespace == "" {
+		var nErr error
+		r.Namespace, _, nErr = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{}).Namespace()
+		if nErr != nil {
+			r.Namespace = core.NamespaceDefault
+		}
+	}
+	err = apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		return
+	}
+	err = Settings.Inventory.Load()
+	if err != nil {
+		return
+	}
+	if r.InventoryURL != "" {
+		url, pErr := liburl.Parse(r.InventoryURL)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+		Settings.Inventory.Host = url.Hostname()
+		Settings.Inventory.TLS.Enabled = url.Scheme == "https"
+		Settings.Inventory.Port = 80
+		if Settings.Inventory.TLS.Enabled {
+			Settings.Inventory.Port = 443
+		}
+		if port := url.Port(); port != "" {
+			Settings.Inventory.Port, err = strconv.Atoi(port)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if r.InventoryCA != "" {
+		Settings.Inventory.TLS.CA = r.InventoryCA
+	}
+
+	return
+}
+
+// The k8s client.
+func (r *Options) Client() (cl client.Client, err error) {
+	if r.client != nil {
+		cl = r.client
+		return
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return
+	}
+	r.client, err = client.New(
+		cfg,
+		client.Options{
+			Scheme: scheme.Scheme,
+		})
+	cl = r.client
+	return
+}
+
+// Get a resource in the namespace.
+func (r *Options) Get(name string, object client.Object) (err error) {
+	cl, err := r.Client()
+	if err != nil {
+		return
+	}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Namespace,
+			Name:      name,
+		},
+		object)
+	return
+}
+
+// Create a resource.
+func (r *Options) Create(kind string, object client.Object) (err error) {
+	if r.DryRun {
+		gvk, gErr := apiutil.GVKForObject(object, scheme.Scheme)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		object.GetObjectKind().SetGroupVersionKind(gvk)
+		var b []byte
+		b, err = yaml.Marshal(object)
+		if err == nil {
+			fmt.Printf("---\n%s", b)
+		}
+		return
+	}
+	cl, err := r.Client()
+	if err != nil {
+		return
+	}
+	err = cl.Create(context.TODO(), object)
+	if err == nil {
+		fmt.Printf("%s/%s created.\n", kind, object.GetName())
+	}
+	return
+}
+
+// Delete a resource.
+func (r *Options) Delete(kind string, object client.Object) (err error) {
+	if r.DryRun {
+		return
+	}
+	cl, err := r.Client()
+	if err != nil {
+		return
+	}
+	err = cl.Delete(context.TODO(), object)
+	if err == nil {
+		fmt.Printf("%s/%s deleted.\n", kind, object.GetName())
+	}
+	return
+}
+
+// Find a provider.
+func (r *Options) Provider(name string) (provider *api.Provider, err error) {
+	provider = &api.Provider{}
+	err = r.Get(name, provider)
+	return
+}
+
+// Build the inventory client for a provider.
+func (r *Options) Inventory(provider *api.Provider) (inventory web.Client, err error) {
+	if r.InventoryURL == "" && os.Getenv(settings.Host) == "" {
+		err = fmt.Errorf("the inventory service URL must be specified using --inventory")
+		return
+	}
+	inventory, err = web.NewClient(provider)
+	return
+}
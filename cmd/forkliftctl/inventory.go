@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+)
+
+// Inventory collections.
+const (
+	VMs      = "vms"
+	Networks = "networks"
+	Storage  = "storage"
+	Hosts    = "hosts"
+)
+
+// Inventory resource.
+type Resource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+// List provider inventory.
+type Inventory struct {
+}
+
+func (r *Inventory) Usage() string {
+	return "<provider> vms|networks|storage|hosts"
+}
+
+func (r *Inventory) Help() string {
+	return "List the provider inventory."
+}
+
+func (r *Inventory) Flags(fs *flag.FlagSet) {
+}
+
+func (r *Inventory) Run(options *Options, args []string) (err error) {
+	err = required(args, "<provider>", "<collection>")
+	if err != nil {
+		return
+	}
+	provider, err := options.Provider(args[0])
+	if err != nil {
+		return
+	}
+	resources, err := list(options, provider, args[1])
+	if err != nil {
+		return
+	}
+	rows := [][]string{}
+	for _, resource := range resources {
+		rows = append(rows, []string{resource.ID, resource.Name, resource.Path})
+	}
+	table([]string{"ID", "NAME", "PATH"}, rows)
+	return
+}
+
+// List an inventory collection.
+func list(options *Options, provider *api.Provider, collection string) (resources []Resource, err error) {
+	var list interface{}
+	switch provider.Type() {
+	case api.VSphere:
+		switch collection {
+		case VMs:
+			list = &[]vsphere.VM{}
+		case Networks:
+			list = &[]vsphere.Network{}
+		case Storage:
+			list = &[]vsphere.Datastore{}
+		case Hosts:
+			list = &[]vsphere.Host{}
+		}
+	case api.OVirt:
+		switch collection {
+		case VMs:
+			list = &[]ovirt.VM{}
+		case Networks:
+			list = &[]ovirt.Network{}
+		case Storage:
+			list = &[]ovirt.StorageDomain{}
+		case Hosts:
+			list = &[]ovirt.Host{}
+		}
+	case api.OpenStack:
+		switch collection {
+		case VMs:
+			list = &[]openstack.VM{}
+		case Networks:
+			list = &[]openstack.Network{}
+		case Storage:
+			list = &[]openstack.VolumeType{}
+		}
+	}
+	if list == nil {
+		err = fmt.Errorf(
+			"collection '%s' not supported for %s providers",
+			collection,
+			provider.Type())
+		return
+	}
+	inventory, err := options.Inventory(provider)
+	if err != nil {
+		return
+	}
+	err = inventory.List(
+		list,
+		web.Param{
+			Key:   base.DetailParam,
+			Value: "1",
+		})
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &resources)
+	return
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Command.
+type Command interface {
+	// Usage (arguments).
+	Usage() string
+	// Short description.
+	Help() string
+	// Add the command flags.
+	Flags(fs *flag.FlagSet)
+	// Run the command.
+	Run(options *Options, args []string) error
+}
+
+// Commands keyed by name.
+var commands = map[string]Command{
+	"provider create":   &ProviderCreate{},
+	"inventory":         &Inventory{},
+	"map network":       &NetworkMapCreate{},
+	"map storage":       &StorageMapCreate{},
+	"plan create":       &PlanCreate{},
+	"migration start":   &MigrationStart{},
+	"migration cancel":  &MigrationCancel{},
+	"migration cutover": &MigrationCutover{},
+	"progress":          &Progress{},
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// Find and run the command.
+func run(args []string) (err error) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		command, found := commands[name]
+		if !found {
+			continue
+		}
+		options := &Options{}
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		options.Flags(fs)
+		command.Flags(fs)
+		fs.Usage = func() {
+			fmt.Fprintf(
+				fs.Output(),
+				"Usage: forkliftctl %s [flags] %s\n\n%s\n\nFlags:\n",
+				name,
+				command.Usage(),
+				command.Help())
+			fs.PrintDefaults()
+		}
+		err = fs.Parse(args[n:])
+		if err != nil {
+			return
+		}
+		err = options.Complete()
+		if err != nil {
+			return
+		}
+		err = command.Run(options, fs.Args())
+		return
+	}
+	usage()
+	err = fmt.Errorf("unknown command: %s", strings.Join(args, " "))
+	return
+}
+
+// Print the usage.
+func usage() {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: forkliftctl <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", name, commands[name].Help())
+	}
+}
+
+// Require the positional arguments.
+func required(args []string, names ...string) (err error) {
+	if len(args) != len(names) {
+		err = fmt.Errorf("expected arguments: %s", strings.Join(names, " "))
+	}
+	return
+}
+
+// Split a comma separated list.
+func split(s string) (list []string) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
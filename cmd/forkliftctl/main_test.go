@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestSplit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	for _, tc := range []struct {
+		in  string
+		out []string
+	}{
+		{in: "", out: nil},
+		{in: "a", out: []string{"a"}},
+		{in: "a,b", out: []string{"a", "b"}},
+		{in: " a , b ", out: []string{"a", "b"}},
+		{in: "a,,b,", out: []string{"a", "b"}},
+		{in: " , ", out: nil},
+	} {
+		g.Expect(split(tc.in)).To(gomega.Equal(tc.out), tc.in)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Source and destination providers.
+type Providers struct {
+	Source      string
+	Destination string
+}
+
+// Add the provider flags.
+func (r *Providers) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Source, "source", "", "Source provider.")
+	fs.StringVar(&r.Destination, "destination", "host", "Destination provider.")
+}
+
+// Find the source provider and build the provider pair.
+func (r *Providers) Pair(options *Options) (source *api.Provider, pair provider.Pair, err error) {
+	if r.Source == "" {
+		err = fmt.Errorf("--source required")
+		return
+	}
+	source, err = options.Provider(r.Source)
+	if err != nil {
+		return
+	}
+	pair = provider.Pair{
+		Source: core.ObjectReference{
+			Namespace: options.Namespace,
+			Name:      r.Source,
+		},
+		Destination: core.ObjectReference{
+			Namespace: options.Namespace,
+			Name:      r.Destination,
+		},
+	}
+	return
+}
+
+// Generate a network map.
+// All source networks are mapped to the same destination.
+type NetworkMapCreate struct {
+	Providers
+	Multus string
+	Type   string
+}
+
+func (r *NetworkMapCreate) Usage() string {
+	return "<name>"
+}
+
+func (r *NetworkMapCreate) Help() string {
+	return "Generate a network map of all source networks."
+}
+
+func (r *NetworkMapCreate) Flags(fs *flag.FlagSet) {
+	r.Providers.Flags(fs)
+	fs.StringVar(&r.Multus, "multus", "", "Destination network attachment definition (<namespace>/<name>). Default: the pod network.")
+	fs.StringVar(&r.Type, "type", "", "Destination network type (multus|sriov|ovn|ignored). Default: multus when --multus is specified.")
+}
+
+func (r *NetworkMapCreate) Run(options *Options, args []string) (err error) {
+	err = required(args, "<name>")
+	if err != nil {
+		return
+	}
+	source, pair, err := r.Pair(options)
+	if err != nil {
+		return
+	}
+	destination := api.DestinationNetwork{Type: api.PodNetwork}
+	switch r.Type {
+	case "", api.PodNetwork:
+	case api.MultusNetwork, api.SriovNetwork, api.OvnNetwork:
+		if r.Multus == "" {
+			err = fmt.Errorf("--type %s requires --multus", r.Type)
+			return
+		}
+	case api.IgnoredNetwork:
+		destination.Type = r.Type
+	default:
+		err = fmt.Errorf("--type must be multus, sriov, ovn or ignored")
+		return
+	}
+	if r.Multus != "" && destination.Type != api.IgnoredNetwork {
+		parts := strings.SplitN(r.Multus, "/", 2)
+		if len(parts) != 2 {
+			err = fmt.Errorf("--multus must be <namespace>/<name>")
+			return
+		}
+		destination = api.DestinationNetwork{
+			Type:      api.MultusNetwork,
+			Namespace: parts[0],
+			Name:      parts[1],
+		}
+		if r.Type != "" && r.Type != api.PodNetwork {
+			destination.Type = r.Type
+		}
+	}
+	networks, err := list(options, source, Networks)
+	if err != nil {
+		return
+	}
+	mp := &api.NetworkMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: options.Namespace,
+			Name:      args[0],
+		},
+		Spec: api.NetworkMapSpec{
+			Provider: pair,
+			Map:      []api.NetworkPair{},
+		},
+	}
+	for _, network := range networks {
+		mp.Spec.Map = append(
+			mp.Spec.Map,
+			api.NetworkPair{
+				Source:      ref.Ref{ID: network.ID},
+				Destination: destination,
+			})
+	}
+	err = options.Create("networkmap", mp)
+	return
+}
+
+// Generate a storage map.
+// All source storage is mapped to the same storage class.
+type StorageMapCreate struct {
+	Providers
+	StorageClass string
+	VolumeMode   string
+	AccessMode   string
+}
+
+func (r *StorageMapCreate) Usage() string {
+	return "<name>"
+}
+
+func (r *StorageMapCreate) Help() string {
+	return "Generate a storage map of all source storage."
+}
+
+func (r *StorageMapCreate) Flags(fs *flag.FlagSet) {
+	r.Providers.Flags(fs)
+	fs.StringVar(&r.StorageClass, "storage-class", "", "Destination storage class.")
+	fs.StringVar(&r.VolumeMode, "volume-mode", "", "Volume mode (Filesystem|Block).")
+	fs.StringVar(&r.AccessMode, "access-mode", "", "Access mode (ReadWriteOnce|ReadWriteMany|ReadOnlyMany).")
+}
+
+func (r *StorageMapCreate) Run(options *Options, args []string) (err error) {
+	err = required(args, "<name>")
+	if err != nil {
+		return
+	}
+	if r.StorageClass == "" {
+		err = fmt.Errorf("--storage-class required")
+		return
+	}
+	source, pair, err := r.Pair(options)
+	if err != nil {
+		return
+	}
+	storage, err := list(options, source, Storage)
+	if err != nil {
+		return
+	}
+	mp := &api.StorageMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: options.Namespace,
+			Name:      args[0],
+		},
+		Spec: api.StorageMapSpec{
+			Provider: pair,
+			Map:      []api.StoragePair{},
+		},
+	}
+	for _, ds := range storage {
+		mp.Spec.Map = append(
+			mp.Spec.Map,
+			api.StoragePair{
+				Source: ref.Ref{ID: ds.ID},
+				Destination: api.DestinationStorage{
+					StorageClass: r.StorageClass,
+					VolumeMode:   core.PersistentVolumeMode(r.VolumeMode),
+					AccessMode:   core.PersistentVolumeAccessMode(r.AccessMode),
+				},
+			})
+	}
+	err = options.Create("storagemap", mp)
+	return
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VM reference prefixes.
+const (
+	IDPrefix   = "id:"
+	NamePrefix = "name:"
+)
+
+// Create a migration plan.
+type PlanCreate struct {
+	Providers
+	NetworkMap      string
+	StorageMap      string
+	TargetNamespace string
+	VMs             string
+	Name            string
+	Folder          string
+	Cluster         string
+	Tags            string
+	ExcludeCritical bool
+	Warm            bool
+}
+
+func (r *PlanCreate) Usage() string {
+	return "<name>"
+}
+
+func (r *PlanCreate) Help() string {
+	return "Create a migration plan from a VM list or query."
+}
+
+func (r *PlanCreate) Flags(fs *flag.FlagSet) {
+	r.Providers.Flags(fs)
+	fs.StringVar(&r.NetworkMap, "network-map", "", "Network map.")
+	fs.StringVar(&r.StorageMap, "storage-map", "", "Storage map.")
+	fs.StringVar(&r.TargetNamespace, "target-namespace", "", "Target namespace. Default: the namespace.")
+	fs.StringVar(&r.VMs, "vms", "", "Comma separated VM names or IDs (prefixed by 'id:' or 'name:').")
+	fs.StringVar(&r.Name, "name", "", "Select VMs by name (regular expression).")
+	fs.StringVar(&r.Folder, "folder", "", "Select VMs by folder (vsphere).")
+	fs.StringVar(&r.Cluster, "cluster", "", "Select VMs by cluster.")
+	fs.StringVar(&r.Tags, "tags", "", "Select VMs having all (comma separated) tags.")
+	fs.BoolVar(&r.ExcludeCritical, "exclude-critical", false, "Exclude selected VMs with critical concerns.")
+	fs.BoolVar(&r.Warm, "warm", false, "Warm migration.")
+}
+
+func (r *PlanCreate) Run(options *Options, args []string) (err error) {
+	err = required(args, "<name>")
+	if err != nil {
+		return
+	}
+	if r.NetworkMap == "" || r.StorageMap == "" {
+		err = fmt.Errorf("--network-map and --storage-map required")
+		return
+	}
+	_, pair, err := r.Pair(options)
+	if err != nil {
+		return
+	}
+	target := r.TargetNamespace
+	if target == "" {
+		target = options.Namespace
+	}
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: options.Namespace,
+			Name:      args[0],
+		},
+		Spec: api.PlanSpec{
+			TargetNamespace: target,
+			Provider:        pair,
+			Warm:            r.Warm,
+			Map: plan.Map{
+				Network: core.ObjectReference{
+					Namespace: options.Namespace,
+					Name:      r.NetworkMap,
+				},
+				Storage: core.ObjectReference{
+					Namespace: options.Namespace,
+					Name:      r.StorageMap,
+				},
+			},
+			VMs: []plan.VM{},
+		},
+	}
+	for _, vm := range split(r.VMs) {
+		p.Spec.VMs = append(p.Spec.VMs, plan.VM{Ref: vmRef(vm)})
+	}
+	if r.Name != "" || r.Folder != "" || r.Cluster != "" || r.Tags != "" {
+		p.Spec.Selector = &plan.VMSelector{
+			Name:            r.Name,
+			Folder:          r.Folder,
+			Cluster:         r.Cluster,
+			Tags:            split(r.Tags),
+			ExcludeCritical: r.ExcludeCritical,
+		}
+	}
+	if len(p.Spec.VMs) == 0 && p.Spec.Selector == nil {
+		err = fmt.Errorf("--vms or a VM query required")
+		return
+	}
+	err = options.Create("plan", p)
+	return
+}
+
+// Build a VM reference.
+// The reference is explicit using the `id:` and `name:`
+// prefixes. Otherwise, UUIDs (oVirt, OpenStack) are referenced
+// by ID and everything else by name.
+func vmRef(s string) ref.Ref {
+	switch {
+	case strings.HasPrefix(s, IDPrefix):
+		return ref.Ref{ID: strings.TrimPrefix(s, IDPrefix)}
+	case strings.HasPrefix(s, NamePrefix):
+		return ref.Ref{Name: strings.TrimPrefix(s, NamePrefix)}
+	case isUUID(s):
+		return ref.Ref{ID: s}
+	default:
+		return ref.Ref{Name: s}
+	}
+}
+
+// Determine whether the string is a UUID.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Start migrating a plan.
+type MigrationStart struct {
+	Cutover string
+}
+
+func (r *MigrationStart) Usage() string {
+	return "<plan>"
+}
+
+func (r *MigrationStart) Help() string {
+	return "Start migrating a plan."
+}
+
+func (r *MigrationStart) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Cutover, "cutover", "", "Warm migration cutover (RFC3339 or 'now').")
+}
+
+func (r *MigrationStart) Run(options *Options, args []string) (err error) {
+	err = required(args, "<plan>")
+	if err != nil {
+		return
+	}
+	p := &api.Plan{}
+	err = options.Get(args[0], p)
+	if err != nil {
+		return
+	}
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    options.Namespace,
+			GenerateName: p.Name + "-",
+		},
+		Spec: api.MigrationSpec{
+			Plan: core.ObjectReference{
+				Namespace: p.Namespace,
+				Name:      p.Name,
+			},
+		},
+	}
+	if r.Cutover != "" {
+		migration.Spec.Cutover, err = parseTime(r.Cutover)
+		if err != nil {
+			return
+		}
+	}
+	err = options.Create("migration", migration)
+	return
+}
+
+// Cancel the running migration of a plan.
+type MigrationCancel struct {
+	VMs string
+}
+
+func (r *MigrationCancel) Usage() string {
+	return "<plan>"
+}
+
+func (r *MigrationCancel) Help() string {
+	return "Cancel the running migration of a plan."
+}
+
+func (r *MigrationCancel) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.VMs, "vms", "", "Comma separated VM names or IDs (prefixed by 'id:' or 'name:'). Default: all VMs.")
+}
+
+func (r *MigrationCancel) Run(options *Options, args []string) (err error) {
+	err = required(args, "<plan>")
+	if err != nil {
+		return
+	}
+	migration, p, err := activeMigration(options, args[0])
+	if err != nil {
+		return
+	}
+	refs := []ref.Ref{}
+	for _, vm := range split(r.VMs) {
+		refs = append(refs, vmRef(vm))
+	}
+	for _, vm := range p.Status.Migration.VMs {
+		if len(refs) > 0 && !listed(refs, vm.Ref) {
+			continue
+		}
+		if migration.Spec.Canceled(vm.Ref) {
+			continue
+		}
+		migration.Spec.Cancel = append(migration.Spec.Cancel, ref.Ref{ID: vm.ID, Name: vm.Name})
+	}
+	err = update(options, migration)
+	if err == nil {
+		fmt.Printf("migration/%s canceled.\n", migration.Name)
+	}
+	return
+}
+
+// Set the cutover of a warm migration.
+type MigrationCutover struct {
+	At string
+}
+
+func (r *MigrationCutover) Usage() string {
+	return "<plan>"
+}
+
+func (r *MigrationCutover) Help() string {
+	return "Set the cutover of the running warm migration of a plan."
+}
+
+func (r *MigrationCutover) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.At, "at", "now", "Cutover (RFC3339 or 'now').")
+}
+
+func (r *MigrationCutover) Run(options *Options, args []string) (err error) {
+	err = required(args, "<plan>")
+	if err != nil {
+		return
+	}
+	migration, _, err := activeMigration(options, args[0])
+	if err != nil {
+		return
+	}
+	migration.Spec.Cutover, err = parseTime(r.At)
+	if err != nil {
+		return
+	}
+	err = update(options, migration)
+	if err == nil {
+		fmt.Printf(
+			"migration/%s cutover set: %s.\n",
+			migration.Name,
+			migration.Spec.Cutover.Format(time.RFC3339))
+	}
+	return
+}
+
+// Find the running migration of a plan.
+func activeMigration(options *Options, name string) (migration *api.Migration, p *api.Plan, err error) {
+	p = &api.Plan{}
+	err = options.Get(name, p)
+	if err != nil {
+		return
+	}
+	snapshot := p.Status.Migration.ActiveSnapshot()
+	if snapshot == nil || !snapshot.HasCondition("Executing") {
+		err = fmt.Errorf("plan/%s is not executing", name)
+		return
+	}
+	migration = &api.Migration{}
+	err = options.Get(snapshot.Migration.Name, migration)
+	return
+}
+
+// Update a resource.
+func update(options *Options, object client.Object) (err error) {
+	cl, err := options.Client()
+	if err != nil {
+		return
+	}
+	err = cl.Update(context.TODO(), object)
+	return
+}
+
+// Parse a timestamp (RFC3339 or 'now').
+func parseTime(s string) (t *meta.Time, err error) {
+	if s == "now" {
+		now := meta.Now()
+		t = &now
+		return
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return
+	}
+	t = &meta.Time{Time: parsed}
+	return
+}
+
+// Determine whether the VM is listed.
+func listed(refs []ref.Ref, vmRef ref.Ref) bool {
+	for _, r := range refs {
+		if r.Same(vmRef) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestVMRef(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	for _, tc := range []struct {
+		in  string
+		out ref.Ref
+	}{
+		{in: "db", out: ref.Ref{Name: "db"}},
+		{in: "vm-42", out: ref.Ref{Name: "vm-42"}},
+		{in: "id:vm-42", out: ref.Ref{ID: "vm-42"}},
+		{in: "name:vm-42", out: ref.Ref{Name: "vm-42"}},
+		{in: "name:id:x", out: ref.Ref{Name: "id:x"}},
+		{
+			in:  "0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f",
+			out: ref.Ref{ID: "0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f"},
+		},
+		{
+			in:  "name:0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f",
+			out: ref.Ref{Name: "0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f"},
+		},
+	} {
+		g.Expect(vmRef(tc.in)).To(gomega.Equal(tc.out), tc.in)
+	}
+}
+
+func TestIsUUID(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	for _, tc := range []struct {
+		in  string
+		out bool
+	}{
+		{in: "0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f", out: true},
+		{in: "0F3D9A4E-8C1B-4D2A-9E5F-6A7B8C9D0E1F", out: true},
+		{in: "0f3d9a4e8c1b4d2a9e5f6a7b8c9d0e1f", out: false},
+		{in: "0f3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1", out: false},
+		{in: "0f3d9a4e-8c1b-4d2a-9e5f_6a7b8c9d0e1f", out: false},
+		{in: "zf3d9a4e-8c1b-4d2a-9e5f-6a7b8c9d0e1f", out: false},
+		{in: "", out: false},
+	} {
+		g.Expect(isUUID(tc.in)).To(gomega.Equal(tc.out), tc.in)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	for _, tc := range []struct {
+		in    string
+		out   time.Time
+		valid bool
+	}{
+		{in: "2026-10-18T21:00:00Z", out: time.Date(2026, 10, 18, 21, 0, 0, 0, time.UTC), valid: true},
+		{in: "2026-10-18T23:00:00+02:00", out: time.Date(2026, 10, 18, 21, 0, 0, 0, time.UTC), valid: true},
+		{in: "2026-10-18", valid: false},
+		{in: "tomorrow", valid: false},
+		{in: "", valid: false},
+	} {
+		parsed, err := parseTime(tc.in)
+		if !tc.valid {
+			g.Expect(err).ToNot(gomega.BeNil(), tc.in)
+			continue
+		}
+		g.Expect(err).To(gomega.BeNil(), tc.in)
+		g.Expect(parsed.Time.Equal(tc.out)).To(gomega.BeTrue(), tc.in)
+	}
+	before := time.Now()
+	now, err := parseTime("now")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(now.Time.Before(before.Add(-time.Second))).To(gomega.BeFalse())
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+)
+
+// Show the per-VM migration progress of a plan.
+type Progress struct {
+	Watch    bool
+	Interval time.Duration
+}
+
+func (r *Progress) Usage() string {
+	return "<plan>"
+}
+
+func (r *Progress) Help() string {
+	return "Show the per-VM migration progress of a plan."
+}
+
+func (r *Progress) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&r.Watch, "watch", false, "Refresh until the plan is no longer executing.")
+	fs.DurationVar(&r.Interval, "interval", 5*time.Second, "Refresh interval.")
+}
+
+func (r *Progress) Run(options *Options, args []string) (err error) {
+	err = required(args, "<plan>")
+	if err != nil {
+		return
+	}
+	for {
+		p := &api.Plan{}
+		err = options.Get(args[0], p)
+		if err != nil {
+			return
+		}
+		if r.Watch {
+			// Clear the terminal.
+			fmt.Print("\033[H\033[2J")
+		}
+		r.show(p)
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		executing := snapshot != nil && snapshot.HasCondition("Executing")
+		if !r.Watch || !executing {
+			break
+		}
+		time.Sleep(r.Interval)
+	}
+	return
+}
+
+// Print the plan progress.
+func (r *Progress) show(p *api.Plan) {
+	status := "Ready"
+	if snapshot := p.Status.Migration.ActiveSnapshot(); snapshot != nil {
+		for _, t := range []string{"Executing", "Succeeded", "Failed", "Canceled"} {
+			if snapshot.HasCondition(t) {
+				status = t
+				break
+			}
+		}
+	}
+	fmt.Printf("Plan: %s/%s (%s)\n\n", p.Namespace, p.Name, status)
+	rows := [][]string{}
+	for _, vm := range p.Status.Migration.VMs {
+		rows = append(rows, progressRow(vm))
+	}
+	table([]string{"VM", "PHASE", "STEP", "PROGRESS", "PIPELINE", "STARTED", "ERROR"}, rows)
+}
+
+// Build the progress table row for a VM.
+func progressRow(vm *plan.VMStatus) []string {
+	name := vm.Name
+	if name == "" {
+		name = vm.ID
+	}
+	var current *plan.Step
+	completed := 0
+	for _, step := range vm.Pipeline {
+		if step.MarkedCompleted() {
+			completed++
+			continue
+		}
+		if current == nil && step.MarkedStarted() {
+			current = step
+		}
+	}
+	stepName, progress := "", ""
+	if current != nil {
+		stepName = current.Name
+		progress = percent(current.Progress.Completed, current.Progress.Total)
+	}
+	errors := []string{}
+	if vm.Error != nil {
+		errors = append(errors, vm.Error.Reasons...)
+	}
+	return []string{
+		name,
+		vm.Phase,
+		stepName,
+		progress,
+		fmt.Sprintf("%d/%d", completed, len(vm.Pipeline)),
+		timestamp(vm.Started),
+		strings.Join(errors, "; "),
+	}
+}
+
+// Format the progress percentage.
+func percent(completed, total int64) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%%", completed*100/total)
+}
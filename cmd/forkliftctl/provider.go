@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	liburl "net/url"
+	"os"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Read from stdin.
+const Stdin = "-"
+
+// Create a provider and the secret containing its credentials.
+type ProviderCreate struct {
+	Type         string
+	URL          string
+	Username     string
+	PasswordFile string
+	TokenFile    string
+	CACert       string
+	Thumbprint   string
+	Insecure     bool
+	Project      string
+	Domain       string
+	Region       string
+}
+
+func (r *ProviderCreate) Usage() string {
+	return "<name>"
+}
+
+func (r *ProviderCreate) Help() string {
+	return "Create a provider and the secret containing its credentials."
+}
+
+func (r *ProviderCreate) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Type, "type", "", "Provider type (vsphere|ovirt|openstack|openshift).")
+	fs.StringVar(&r.URL, "url", "", "Provider API URL.")
+	fs.StringVar(&r.Username, "username", "", "User name.")
+	fs.StringVar(&r.PasswordFile, "password-file", "", "Password file path or '-' to read from stdin.")
+	fs.StringVar(&r.TokenFile, "token-file", "", "Service account token (openshift) file path or '-' to read from stdin.")
+	fs.StringVar(&r.CACert, "cacert", "", "CA certificate (PEM) file path.")
+	fs.StringVar(&r.Thumbprint, "thumbprint", "", "Certificate SHA-1 thumbprint (vsphere). Fetched and confirmed when not specified.")
+	fs.BoolVar(&r.Insecure, "insecure", false, "Skip TLS verification.")
+	fs.StringVar(&r.Project, "project", "", "Project name (openstack).")
+	fs.StringVar(&r.Domain, "domain", "", "Domain name (openstack).")
+	fs.StringVar(&r.Region, "region", "", "Region name (openstack).")
+}
+
+func (r *ProviderCreate) Run(options *Options, args []string) (err error) {
+	err = required(args, "<name>")
+	if err != nil {
+		return
+	}
+	name := args[0]
+	pType := api.ProviderType(r.Type)
+	data, err := r.secretData(pType)
+	if err != nil {
+		return
+	}
+	secret := &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    options.Namespace,
+			GenerateName: name + "-",
+			Labels: map[string]string{
+				"createdForProviderType": r.Type,
+				"createdForResourceType": "providers",
+			},
+		},
+		Data: data,
+	}
+	err = options.Create("secret", secret)
+	if err != nil {
+		return
+	}
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: options.Namespace,
+			Name:      name,
+		},
+		Spec: api.ProviderSpec{
+			Type: &pType,
+			URL:  r.URL,
+			Secret: core.ObjectReference{
+				Namespace: secret.Namespace,
+				Name:      secret.Name,
+			},
+		},
+	}
+	err = options.Create("provider", provider)
+	if err != nil {
+		// Don't leave the credentials behind.
+		_ = options.Delete("secret", secret)
+		return
+	}
+	if options.DryRun {
+		return
+	}
+	// The secret is deleted with the provider.
+	secret.OwnerReferences = append(
+		secret.OwnerReferences,
+		meta.OwnerReference{
+			APIVersion: api.SchemeGroupVersion.String(),
+			Kind:       "Provider",
+			Name:       provider.Name,
+			UID:        provider.UID,
+		})
+	err = update(options, secret)
+	return
+}
+
+// Build the secret content for the provider type.
+func (r *ProviderCreate) secretData(pType api.ProviderType) (data map[string][]byte, err error) {
+	data = map[string][]byte{}
+	if r.CACert != "" {
+		var cert []byte
+		cert, err = ioutil.ReadFile(r.CACert)
+		if err != nil {
+			return
+		}
+		data["cacert"] = cert
+	}
+	if pType != api.OpenShift {
+		data["insecureSkipVerify"] = []byte(fmt.Sprint(r.Insecure))
+	}
+	switch pType {
+	case api.VSphere:
+		data["user"] = []byte(r.Username)
+		data["password"], err = readSecret(r.PasswordFile)
+		if err != nil {
+			return
+		}
+		thumbprint := r.Thumbprint
+		if thumbprint == "" {
+			thumbprint, err = r.confirmThumbprint()
+			if err != nil {
+				return
+			}
+		}
+		data["thumbprint"] = []byte(thumbprint)
+	case api.OVirt:
+		if r.CACert == "" && !r.Insecure {
+			err = fmt.Errorf("--cacert or --insecure required")
+			return
+		}
+		data["user"] = []byte(r.Username)
+		data["password"], err = readSecret(r.PasswordFile)
+	case api.OpenStack:
+		data["username"] = []byte(r.Username)
+		data["password"], err = readSecret(r.PasswordFile)
+		data["projectName"] = []byte(r.Project)
+		data["domainName"] = []byte(r.Domain)
+		data["regionName"] = []byte(r.Region)
+	case api.OpenShift:
+		if r.URL != "" {
+			data["token"], err = readSecret(r.TokenFile)
+		}
+	default:
+		err = fmt.Errorf("provider type not supported: %s", r.Type)
+	}
+
+	return
+}
+
+// Fetch the thumbprint of the server certificate and have
+// the user confirm it. The certificate is not verified when
+// fetched so the thumbprint is accepted without confirmation
+// only when --insecure is specified.
+func (r *ProviderCreate) confirmThumbprint() (thumbprint string, err error) {
+	thumbprint, err = fetchThumbprint(r.URL)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Server certificate SHA-1 fingerprint: %s\n", thumbprint)
+	if r.Insecure {
+		return
+	}
+	if r.PasswordFile == Stdin {
+		err = fmt.Errorf("--thumbprint or --insecure required when the password is read from stdin")
+		return
+	}
+	fmt.Fprint(os.Stderr, "Trust this certificate? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+	default:
+		err = fmt.Errorf("certificate not trusted, specify --thumbprint")
+	}
+
+	return
+}
+
+// Read a secret from a file or stdin.
+// The trailing newline is removed.
+func readSecret(path string) (secret []byte, err error) {
+	switch path {
+	case "":
+		err = fmt.Errorf("--password-file or --token-file required")
+		return
+	case Stdin:
+		secret, err = ioutil.ReadAll(os.Stdin)
+	default:
+		secret, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return
+	}
+	secret = bytes.TrimRight(secret, "\r\n")
+	return
+}
+
+// Fetch the SHA-1 thumbprint of the server certificate.
+func fetchThumbprint(url string) (thumbprint string, err error) {
+	parsed, err := liburl.Parse(url)
+	if err != nil {
+		return
+	}
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	conn, err := tls.Dial(
+		"tcp",
+		host,
+		&tls.Config{
+			InsecureSkipVerify: true,
+		})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		err = fmt.Errorf("no certificate presented by: %s", host)
+		return
+	}
+	sum := sha1.Sum(certs[0].Raw)
+	hex := []string{}
+	for _, b := range sum {
+		hex = append(hex, fmt.Sprintf("%02X", b))
+	}
+	thumbprint = strings.Join(hex, ":")
+	return
+}
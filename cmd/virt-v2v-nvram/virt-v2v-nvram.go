@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"regexp"
+
+	"github.com/konveyor/forklift-controller/pkg/lib/efi"
+	"k8s.io/klog/v2"
+)
+
+// Global variables copied from the source NVRAM.
+var GLOBAL_RE = regexp.MustCompile(`^(Boot[0-9A-Fa-f]{4}|BootOrder|Driver[0-9A-Fa-f]{4}|DriverOrder|Timeout|PK|KEK)$`)
+
+// Secure boot keys and databases kept from the
+// template when the keys are re-enrolled.
+var KEYS = map[efi.GUID]map[string]bool{
+	efi.GlobalVariable: {
+		"PK":  true,
+		"KEK": true,
+	},
+	efi.ImageSecurityDatabase: {
+		"db":  true,
+		"dbx": true,
+		"dbt": true,
+		"dbr": true,
+	},
+}
+
+// Determine whether a source variable is copied.
+// The boot entries, the secure boot keys and databases,
+// the shim (MOK) and the Windows boot manager variables
+// are copied. Other variables are firmware specific.
+func copied(v efi.Variable, reEnrollKeys bool) bool {
+	if reEnrollKeys && KEYS[v.GUID][v.Name] {
+		return false
+	}
+	switch v.GUID {
+	case efi.GlobalVariable:
+		return GLOBAL_RE.MatchString(v.Name)
+	case efi.ImageSecurityDatabase, efi.ShimLock, efi.Microsoft:
+		return true
+	default:
+		return false
+	}
+}
+
+// Merge the source variables into the template.
+func merge(source, template []byte, reEnrollKeys bool) (image []byte, err error) {
+	sourceStore, err := efi.Parse(source)
+	if err != nil {
+		return
+	}
+	store, err := efi.Parse(template)
+	if err != nil {
+		return
+	}
+	for _, v := range sourceStore.Variables {
+		if copied(v, reEnrollKeys) {
+			klog.Infof("Copying variable %s-%s", v.GUID, v.Name)
+			store.Set(v)
+		} else {
+			klog.V(1).Infof("Ignoring variable %s-%s", v.GUID, v.Name)
+		}
+	}
+	image, err = store.Image()
+	return
+}
+
+// Copy the EFI variables of the source VM NVRAM into
+// the (OVMF) variable store of the target VM.
+func main() {
+	klog.InitFlags(nil)
+	defer klog.Flush()
+	source := flag.String("source", "", "Source VM NVRAM.")
+	template := flag.String("template", "/usr/share/edk2/ovmf/OVMF_VARS.secboot.fd", "Target variable store template.")
+	output := flag.String("output", "", "Target variable store.")
+	reEnrollKeys := flag.Bool("reenroll-keys", false, "Keep the secure boot keys of the template.")
+	flag.Parse()
+
+	sourceImage, err := os.ReadFile(*source)
+	if err != nil {
+		klog.Fatal("Source NVRAM not read: ", err)
+	}
+	templateImage, err := os.ReadFile(*template)
+	if err != nil {
+		klog.Fatal("Template not read: ", err)
+	}
+	image, err := merge(sourceImage, templateImage, *reEnrollKeys)
+	if err != nil {
+		klog.Fatal("Variables not copied: ", err)
+	}
+	err = os.WriteFile(*output, image, 0644)
+	if err != nil {
+		klog.Fatal("Variable store not written: ", err)
+	}
+	klog.Info("Variable store written: ", *output)
+}
@@ -39,6 +39,7 @@ require (
 	kubevirt.io/containerized-data-importer-api v1.44.0
 	libvirt.org/libvirt-go-xml v6.6.0+incompatible
 	sigs.k8s.io/controller-runtime v0.8.3
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -100,7 +101,6 @@ require (
 	kubevirt.io/containerized-data-importer v1.34.0 // indirect
 	kubevirt.io/controller-lifecycle-operator-sdk v0.2.3 // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.3 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
 
 // CVE-2021-41190
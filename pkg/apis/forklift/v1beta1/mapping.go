@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
@@ -24,17 +26,84 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Network types.
+const (
+	PodNetwork     = "pod"
+	MultusNetwork  = "multus"
+	SriovNetwork   = "sriov"
+	OvnNetwork     = "ovn"
+	IgnoredNetwork = "ignored"
+)
+
 // Mapped network destination.
 type DestinationNetwork struct {
-	// The network type.
-	// +kubebuilder:validation:Enum=pod;multus
+	// The network type: pod, multus (NAD), sriov (SR-IOV NAD),
+	// ovn (OVN-Kubernetes layer2 or localnet NAD) or ignored
+	// (NICs on the source network are not migrated).
+	// +kubebuilder:validation:Enum=pod;multus;sriov;ovn;ignored
 	Type string `json:"type"`
-	// The namespace (multus only).
+	// The namespace (multus, sriov and ovn only).
 	Namespace string `json:"namespace,omitempty"`
 	// The name.
 	Name string `json:"name,omitempty"`
 }
 
+// NAD (CNI) plugin types.
+const (
+	SriovPlugin = "sriov"
+	OvnPlugin   = "ovn-k8s-cni-overlay"
+)
+
+// OVN-Kubernetes topologies.
+const (
+	OvnLayer2   = "layer2"
+	OvnLocalnet = "localnet"
+)
+
+// OVN-Kubernetes topologies supported for secondary networks.
+var OvnTopologies = map[string]bool{
+	OvnLayer2:   true,
+	OvnLocalnet: true,
+}
+
+// NAD (CNI) configuration.
+// +k8s:deepcopy-gen=false
+type NadConfig struct {
+	// Plugin type.
+	Type string `json:"type"`
+	// OVN-Kubernetes topology.
+	Topology string `json:"topology"`
+	// VLAN ID (bridge).
+	Vlan int `json:"vlan"`
+	// VLAN ID (OVN-Kubernetes localnet).
+	VlanID int `json:"vlanID"`
+	// Plugins (configuration list).
+	Plugins []NadConfig `json:"plugins"`
+}
+
+// Parse the NAD (CNI) configuration into the plugins.
+// The configuration is followed by the plugins of a
+// configuration list. Not valid configuration is empty.
+func NadPlugins(config string) []NadConfig {
+	parsed := NadConfig{}
+	_ = json.Unmarshal([]byte(config), &parsed)
+	return append([]NadConfig{parsed}, parsed.Plugins...)
+}
+
+// The destination network type backed by the plugin.
+// SR-IOV for the SR-IOV plugin, OVN for the OVN-Kubernetes
+// plugin with a supported topology, otherwise multus.
+func (r *NadConfig) NetworkType() string {
+	switch {
+	case r.Type == SriovPlugin:
+		return SriovNetwork
+	case r.Type == OvnPlugin && OvnTopologies[r.Topology]:
+		return OvnNetwork
+	default:
+		return MultusNetwork
+	}
+}
+
 // Mapped network.
 type NetworkPair struct {
 	// Source network.
@@ -69,6 +138,23 @@ type NetworkMapSpec struct {
 	Provider provider.Pair `json:"provider"`
 	// Map.
 	Map []NetworkPair `json:"map"`
+	// Generate missing destination NADs.
+	// +optional
+	Generate *NadGeneration `json:"generate,omitempty"`
+}
+
+// NAD generation.
+// Missing multus (bridge) or ovn (localnet) destination NADs
+// are created using the VLAN of the mapped source network.
+// NADs are only created in the map namespace or the target
+// namespace of a plan using the map, and only for source
+// networks with a known VLAN.
+type NadGeneration struct {
+	// The NAD type: bridge (linux bridge) or localnet (OVN-Kubernetes).
+	// +kubebuilder:validation:Enum=bridge;localnet
+	Type string `json:"type,omitempty"`
+	// The node bridge (bridge) or OVN bridge mapping (localnet) name.
+	Bridge string `json:"bridge"`
 }
 
 // Storage map spec.
@@ -90,6 +176,14 @@ type MapStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// Network map status.
+type NetworkMapStatus struct {
+	MapStatus `json:",inline"`
+	// NADs generated by the controller.
+	// Deleted with the map.
+	Generated []core.ObjectReference `json:"generated,omitempty"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
@@ -99,8 +193,8 @@ type MapStatus struct {
 type NetworkMap struct {
 	meta.TypeMeta   `json:",inline"`
 	meta.ObjectMeta `json:"metadata,omitempty"`
-	Spec            NetworkMapSpec `json:"spec,omitempty"`
-	Status          MapStatus      `json:"status,omitempty"`
+	Spec            NetworkMapSpec   `json:"spec,omitempty"`
+	Status          NetworkMapStatus `json:"status,omitempty"`
 	// Referenced resources populated
 	// during validation.
 	Referenced `json:"-"`
@@ -24,6 +24,12 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Annotations.
+const (
+	// The user that created the migration.
+	AnnCreatedBy = "forklift.konveyor.io/created-by"
+)
+
 // MigrationSpec defines the desired state of Migration
 type MigrationSpec struct {
 	// Reference to the associated Plan.
@@ -33,6 +39,45 @@ type MigrationSpec struct {
 	// Date and time to finalize a warm migration.
 	// If present, this will override the value set on the Plan.
 	Cutover *meta.Time `json:"cutover,omitempty"`
+	// Per-VM date and time to finalize a warm migration.
+	// If present, this will override the value set on the
+	// Migration for the listed VMs.
+	Cutovers []VMCutover `json:"cutovers,omitempty"`
+}
+
+// VM cutover.
+type VMCutover struct {
+	// The VM.
+	ref.Ref `json:",inline"`
+	// Date and time to finalize the warm migration of the VM.
+	// When not set, the VM cutover is not scheduled.
+	Cutover *meta.Time `json:"cutover,omitempty"`
+}
+
+// FindCutover finds the cutover for a VM ref.
+// Returns the per-VM cutover when the VM is listed,
+// otherwise the migration cutover. The cutover of a
+// VM listed without a cutover is not scheduled.
+func (r *MigrationSpec) FindCutover(ref ref.Ref) (cutover *meta.Time) {
+	cutover = r.Cutover
+	if ref.ID == "" {
+		return
+	}
+	for i := range r.Cutovers {
+		vm := &r.Cutovers[i]
+		// the refs in the Cutovers array might not have
+		// all been resolved successfully, so skip
+		// over any VMs that don't have an ID set.
+		if vm.ID == "" {
+			continue
+		}
+		if vm.ID == ref.ID {
+			cutover = vm.Cutover
+			return
+		}
+	}
+
+	return
 }
 
 // Canceled indicates whether a VM ref is present
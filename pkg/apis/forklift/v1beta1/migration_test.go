@@ -0,0 +1,31 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindCutover(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	planned := meta.NewTime(time.Now().Add(time.Hour))
+	early := meta.NewTime(time.Now())
+	spec := MigrationSpec{
+		Cutover: &planned,
+		Cutovers: []VMCutover{
+			{Ref: ref.Ref{ID: "vm-1"}, Cutover: &early},
+			{Ref: ref.Ref{ID: "vm-2"}},
+			{Ref: ref.Ref{Name: "unresolved"}, Cutover: &early},
+		},
+	}
+	// Listed VM.
+	g.Expect(spec.FindCutover(ref.Ref{ID: "vm-1"})).To(gomega.Equal(&early))
+	// Listed VM without a cutover is not scheduled.
+	g.Expect(spec.FindCutover(ref.Ref{ID: "vm-2"})).To(gomega.BeNil())
+	// Not listed, the migration cutover.
+	g.Expect(spec.FindCutover(ref.Ref{ID: "vm-3"})).To(gomega.Equal(&planned))
+	g.Expect(spec.FindCutover(ref.Ref{})).To(gomega.Equal(&planned))
+}
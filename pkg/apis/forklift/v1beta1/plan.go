@@ -17,14 +17,24 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	auth "k8s.io/api/authentication/v1"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Annotations.
+const (
+	// The user (JSON) that created or last updated
+	// the plan spec.
+	AnnRequester = "forklift.konveyor.io/requester"
+)
+
 // PlanSpec defines the desired state of Plan.
 type PlanSpec struct {
 	// Description
@@ -37,12 +47,40 @@ type PlanSpec struct {
 	Map plan.Map `json:"map"`
 	// List of VMs.
 	VMs []plan.VM `json:"vms"`
+	// Selects VMs from the provider inventory. The selected
+	// VMs are migrated in addition to the listed VMs.
+	Selector *plan.VMSelector `json:"selector,omitempty"`
 	// Whether this is a warm migration.
 	Warm bool `json:"warm,omitempty"`
+	// Warm precopy scheduling.
+	Precopy *plan.PrecopyPolicy `json:"precopy,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
 	// Whether this plan should be archived.
 	Archived bool `json:"archived,omitempty"`
+	// Source VM metadata copied to the target
+	// VirtualMachine as labels or annotations.
+	MetadataMapping []plan.MetadataMapping `json:"metadataMapping,omitempty"`
+	// Post-migration verification.
+	Verification *plan.Verification `json:"verification,omitempty"`
+	// Source VM decommission.
+	Decommission *plan.Decommission `json:"decommission,omitempty"`
+	// Source device (disk bus, boot order, CD-ROM) preservation.
+	Devices *plan.Devices `json:"devices,omitempty"`
+	// Raw device mapping (RDM) and direct LUN disk handling.
+	LUNs *plan.LUNs `json:"luns,omitempty"`
+	// Secret (in the target namespace) containing the LUKS
+	// passphrases or key files used to open the encrypted
+	// guest disks during conversion. Each key names the
+	// device (e.g. `sda`), the LUKS UUID or `all`.
+	LUKS *core.LocalObjectReference `json:"luks,omitempty"`
+	// Guest customization applied to the converted
+	// disks of each VM.
+	Customization []plan.Customization `json:"customization,omitempty"`
+	// Guest conversion (virt-v2v) configuration.
+	Conversion *plan.Conversion `json:"conversion,omitempty"`
+	// Source VM EFI variable store (NVRAM) handling.
+	NVRAM *plan.NVRAM `json:"nvram,omitempty"`
 }
 
 // Find a planned VM.
@@ -58,6 +96,39 @@ func (r *PlanSpec) FindVM(ref ref.Ref) (v *plan.VM, found bool) {
 	return
 }
 
+// Find the LUKS secret for a planned VM.
+// The VM secret overrides the plan secret.
+func (r *PlanSpec) FindLUKS(ref ref.Ref) (secret *core.LocalObjectReference) {
+	secret = r.LUKS
+	if vm, found := r.FindVM(ref); found && vm.LUKS != nil {
+		secret = vm.LUKS
+	}
+
+	return
+}
+
+// Find the guest customization for a planned VM.
+// The plan customization is followed by the VM customization.
+func (r *PlanSpec) FindCustomization(ref ref.Ref) (customization []plan.Customization) {
+	customization = append(customization, r.Customization...)
+	if vm, found := r.FindVM(ref); found {
+		customization = append(customization, vm.Customization...)
+	}
+
+	return
+}
+
+// Find the guest conversion configuration for a planned VM.
+// The VM configuration overrides the plan configuration.
+func (r *PlanSpec) FindConversion(ref ref.Ref) (conversion plan.Conversion) {
+	conversion.Override(r.Conversion)
+	if vm, found := r.FindVM(ref); found {
+		conversion.Override(vm.Conversion)
+	}
+
+	return
+}
+
 // PlanStatus defines the observed state of Plan.
 type PlanStatus struct {
 	// Conditions.
@@ -67,6 +138,11 @@ type PlanStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Migration
 	Migration plan.MigrationStatus `json:"migration,omitempty"`
+	// VMs selected by the selector.
+	SelectedVMs []ref.Ref `json:"selectedVMs,omitempty"`
+	// Estimated migration duration based on
+	// the observed transfer throughput.
+	Estimate *plan.PlanEstimate `json:"estimate,omitempty"`
 }
 
 // +genclient
@@ -88,6 +164,43 @@ type Plan struct {
 	Referenced `json:"-"`
 }
 
+// The user that created or last updated the plan
+// spec. Nil when not recorded. A malformed annotation
+// is an anonymous user.
+func (r *Plan) Requester() (user *auth.UserInfo) {
+	s, found := r.Annotations[AnnRequester]
+	if !found {
+		return
+	}
+	user = &auth.UserInfo{}
+	err := json.Unmarshal([]byte(s), user)
+	if err != nil {
+		user = &auth.UserInfo{}
+	}
+
+	return
+}
+
+// The listed VMs followed by the VMs selected by the
+// selector (recorded in the status) that are not listed.
+func (r *Plan) VMs() (list []plan.VM) {
+	list = append(list, r.Spec.VMs...)
+	for _, selected := range r.Status.SelectedVMs {
+		listed := false
+		for _, vm := range r.Spec.VMs {
+			if vm.Ref.Same(selected) {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			list = append(list, plan.VM{Ref: selected})
+		}
+	}
+
+	return
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type PlanList struct {
 	meta.TypeMeta `json:",inline"`
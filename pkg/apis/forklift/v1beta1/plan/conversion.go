@@ -0,0 +1,58 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Guest conversion (virt-v2v) configuration.
+type Conversion struct {
+	// Extra virt-v2v options (e.g. `--root=/dev/sda2`).
+	// Options taking a value may be specified either as
+	// `--option=value` or as separate items.
+	Options []string `json:"options,omitempty"`
+	// virt-v2v image.
+	// Overrides the VIRT_V2V_IMAGE setting. The image must be
+	// permitted by the VIRT_V2V_PERMITTED_IMAGES setting.
+	Image string `json:"image,omitempty"`
+	// Conversion pod resources.
+	Resources *core.ResourceRequirements `json:"resources,omitempty"`
+	// Request /dev/kvm for the conversion pod.
+	// Overrides the VIRT_V2V_DONT_REQUEST_KVM setting.
+	RequestKVM *bool `json:"requestKVM,omitempty"`
+	// Scratch volume (/var/tmp) used by virt-v2v.
+	Scratch *ScratchVolume `json:"scratch,omitempty"`
+}
+
+// Conversion scratch volume.
+// An emptyDir limited to the size unless the storage
+// class is specified.
+type ScratchVolume struct {
+	// Size.
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Storage class of the (ephemeral) PVC.
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// Override the conversion configuration.
+// Set fields replace the configuration fields.
+func (r *Conversion) Override(c *Conversion) {
+	if c == nil {
+		return
+	}
+	if len(c.Options) > 0 {
+		r.Options = c.Options
+	}
+	if c.Image != "" {
+		r.Image = c.Image
+	}
+	if c.Resources != nil {
+		r.Resources = c.Resources
+	}
+	if c.RequestKVM != nil {
+		r.RequestKVM = c.RequestKVM
+	}
+	if c.Scratch != nil {
+		r.Scratch = c.Scratch
+	}
+}
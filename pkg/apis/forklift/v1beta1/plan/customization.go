@@ -0,0 +1,71 @@
+package plan
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+)
+
+// Guest customization applied to the converted disks
+// (virt-customize) before the VM is created.
+// Files are uploaded, packages installed and scripts
+// run in that order.
+type Customization struct {
+	// ConfigMap (in the target namespace) containing the
+	// scripts and files referenced by key.
+	ConfigMap core.LocalObjectReference `json:"configMap"`
+	// Scripts (keys) run in the guest, in order.
+	Run []string `json:"run,omitempty"`
+	// Scripts (keys) run when the guest first boots, in order.
+	Firstboot []string `json:"firstboot,omitempty"`
+	// Packages installed in the guest.
+	Install []string `json:"install,omitempty"`
+	// Files uploaded into the guest.
+	Upload []Upload `json:"upload,omitempty"`
+}
+
+// File uploaded into the guest.
+type Upload struct {
+	// ConfigMap key.
+	Key string `json:"key"`
+	// Absolute path in the guest.
+	Path string `json:"path"`
+}
+
+// ConfigMap keys referenced by the customization.
+func (r *Customization) Keys() (keys []string) {
+	for _, upload := range r.Upload {
+		keys = append(keys, upload.Key)
+	}
+	keys = append(keys, r.Run...)
+	keys = append(keys, r.Firstboot...)
+	return
+}
+
+// virt-customize commands (--commands-from-file).
+// The ConfigMap is expected to be mounted at `dir`.
+func (r *Customization) Commands(dir string) (commands []string) {
+	for _, upload := range r.Upload {
+		commands = append(
+			commands,
+			fmt.Sprintf("upload %s:%s", path.Join(dir, upload.Key), upload.Path))
+	}
+	if len(r.Install) > 0 {
+		commands = append(
+			commands,
+			"install "+strings.Join(r.Install, ","))
+	}
+	for _, key := range r.Run {
+		commands = append(
+			commands,
+			"run "+path.Join(dir, key))
+	}
+	for _, key := range r.Firstboot {
+		commands = append(
+			commands,
+			"firstboot "+path.Join(dir, key))
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package plan
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Decommission actions.
+const (
+	DecommissionRename           = "Rename"
+	DecommissionTag              = "Tag"
+	DecommissionAnnotate         = "Annotate"
+	DecommissionDisableAutostart = "DisableAutostart"
+	DecommissionMove             = "Move"
+	DecommissionDelete           = "Delete"
+)
+
+// Source VM decommission.
+// The actions are performed on the source VM after
+// it has been successfully migrated.
+type Decommission struct {
+	// Suffix appended to the VM name.
+	RenameSuffix string `json:"renameSuffix,omitempty"`
+	// Tag (vSphere: `category:name` or `name`) added to the VM.
+	Tag string `json:"tag,omitempty"`
+	// Text appended to the VM annotation (description).
+	Annotation string `json:"annotation,omitempty"`
+	// Disable autostart and high availability.
+	DisableAutostart bool `json:"disableAutostart,omitempty"`
+	// Inventory path of the folder the VM is moved into.
+	Folder string `json:"folder,omitempty"`
+	// Delete the VM. Cannot be combined with other actions
+	// and cannot be undone.
+	Delete bool `json:"delete,omitempty"`
+	// Undo the actions performed on the VMs. The actions
+	// are not undone while a migration is active.
+	Undo bool `json:"undo,omitempty"`
+}
+
+// List the requested actions.
+func (r *Decommission) Actions() (list []DecommissionAction) {
+	if r.Delete {
+		list = append(list, DecommissionAction{Action: DecommissionDelete})
+		return
+	}
+	if r.RenameSuffix != "" {
+		list = append(list, DecommissionAction{Action: DecommissionRename, Value: r.RenameSuffix})
+	}
+	if r.Tag != "" {
+		list = append(list, DecommissionAction{Action: DecommissionTag, Value: r.Tag})
+	}
+	if r.Annotation != "" {
+		list = append(list, DecommissionAction{Action: DecommissionAnnotate, Value: r.Annotation})
+	}
+	if r.DisableAutostart {
+		list = append(list, DecommissionAction{Action: DecommissionDisableAutostart})
+	}
+	if r.Folder != "" {
+		list = append(list, DecommissionAction{Action: DecommissionMove, Value: r.Folder})
+	}
+
+	return
+}
+
+// Decommission action performed on the source VM.
+type DecommissionAction struct {
+	// Action.
+	Action string `json:"action"`
+	// Requested value.
+	Value string `json:"value,omitempty"`
+	// Provider state needed to undo the action.
+	Undo string `json:"undo,omitempty"`
+	// Applied timestamp.
+	Applied *meta.Time `json:"applied,omitempty"`
+	// Error.
+	Error string `json:"error,omitempty"`
+}
+
+// The action has been applied.
+func (r *DecommissionAction) Active() bool {
+	return r.Applied != nil
+}
@@ -0,0 +1,50 @@
+package plan
+
+import (
+	"path"
+	"strings"
+)
+
+// Source device preservation.
+// By default, disks are attached using virtio without
+// a boot order and CD-ROM devices are not created.
+type Devices struct {
+	// Attach disks using the bus (controller type) of the source disk.
+	DiskBus bool `json:"diskBus,omitempty"`
+	// Set an explicit boot order from the source boot configuration.
+	BootOrder bool `json:"bootOrder,omitempty"`
+	// Create the source CD-ROM devices.
+	// CD-ROMs without a mapped ISO are not created.
+	CDROMs bool `json:"cdroms,omitempty"`
+	// ISO (PVC) mapping.
+	ISOs []ISOMapping `json:"isos,omitempty"`
+}
+
+// Source ISO mapped to a PVC.
+type ISOMapping struct {
+	// Source ISO.
+	// vsphere: The image file name or path.
+	// ovirt: The image (disk) ID.
+	Source string `json:"source"`
+	// PVC in the target namespace.
+	ClaimName string `json:"claimName"`
+}
+
+// Find the PVC mapped to the source ISO.
+// The file name (base) is also matched for vsphere ISO paths
+// such as: [datastore] iso/image.iso.
+func (r *Devices) FindISO(source string) (claimName string, found bool) {
+	if source == "" {
+		return
+	}
+	base := path.Base(strings.TrimSpace(source[strings.LastIndex(source, "]")+1:]))
+	for _, iso := range r.ISOs {
+		if iso.Source == source || iso.Source == base {
+			claimName = iso.ClaimName
+			found = true
+			return
+		}
+	}
+
+	return
+}
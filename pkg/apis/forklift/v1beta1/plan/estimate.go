@@ -0,0 +1,28 @@
+package plan
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Estimated (remaining) migration duration.
+type Estimate struct {
+	// Duration (seconds).
+	Duration int64 `json:"duration"`
+	// Estimated time of completion.
+	ETA *meta.Time `json:"eta,omitempty"`
+}
+
+// Estimated plan migration duration.
+type PlanEstimate struct {
+	Estimate `json:",inline"`
+	// Estimated VM migration durations.
+	VMs []VMEstimate `json:"vms,omitempty"`
+}
+
+// Estimated VM migration duration.
+type VMEstimate struct {
+	// Source VM.
+	ref.Ref  `json:",inline"`
+	Estimate `json:",inline"`
+}
@@ -0,0 +1,75 @@
+package plan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Label permitting a LUN persistent volume to be claimed
+// in the namespace (value).
+const LUNNamespaceLabel = "forklift.konveyor.io/lun-namespace"
+
+// Raw device mapping (RDM) and direct LUN disk handling.
+// By default, VMs with LUN disks cannot be migrated.
+// A LUN mapped to a persistent volume is not copied.
+type LUNs struct {
+	// Copy the LUN contents into block PVCs.
+	Copy bool `json:"copy,omitempty"`
+	// Storage class of the copied LUN PVCs.
+	StorageClass string `json:"storageClass,omitempty"`
+	// LUNs mapped to pre-provisioned persistent volumes.
+	Volumes []LUNVolume `json:"volumes,omitempty"`
+}
+
+// LUN mapped to a pre-provisioned persistent volume.
+type LUNVolume struct {
+	// LUN WWN or serial.
+	LUN string `json:"lun"`
+	// Persistent volume (block) on the target.
+	// The volume must be available and either labeled with
+	// forklift.konveyor.io/lun-namespace=<target namespace>
+	// or pre-bound to a claim in the target namespace.
+	PersistentVolume string `json:"persistentVolume"`
+}
+
+// Find the persistent volume mapped to the LUN.
+// The mapped and source LUN identifiers are normalized
+// to the device identifier and must be equal.
+func (r *LUNs) FindVolume(ids ...string) (volume string, found bool) {
+	for _, mapped := range r.Volumes {
+		lun := NormalizedLUN(mapped.LUN)
+		if lun == "" {
+			continue
+		}
+		for _, id := range ids {
+			if NormalizedLUN(id) == lun {
+				volume = mapped.PersistentVolume
+				found = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// vSphere SCSI LUN UUID wrapping the VPD page 83 device
+// identifier: 0200<lun>0000<identifier><product>.
+var page83Wrapper = regexp.MustCompile("^0200[0-9a-f]{2}0000([0-9a-f]+)[0-9a-f]{12}$")
+
+// Multipath WWID of an NAA identified device (oVirt).
+var naaWWID = regexp.MustCompile("^3([0-9a-f]{16}|[0-9a-f]{32})$")
+
+// Normalize the LUN identifier (lower case) to the device
+// identifier. The `naa.` prefix, the page 83 wrapper of the
+// vSphere LUN UUID and the multipath WWID type are stripped.
+func NormalizedLUN(id string) (lun string) {
+	lun = strings.ToLower(strings.TrimSpace(id))
+	lun = strings.TrimPrefix(lun, "naa.")
+	if m := page83Wrapper.FindStringSubmatch(lun); m != nil {
+		lun = m[1]
+	} else if m := naaWWID.FindStringSubmatch(lun); m != nil {
+		lun = m[1]
+	}
+	return
+}
@@ -9,3 +9,29 @@ type Map struct {
 	// Storage.
 	Storage core.ObjectReference `json:"storage" ref:"StorageMap"`
 }
+
+// Source VM metadata kinds.
+const (
+	// vSphere tags in a category.
+	MetadataTag = "Tag"
+	// vSphere custom attribute.
+	MetadataAttribute = "Attribute"
+	// vSphere annotation (notes).
+	MetadataAnnotation = "Annotation"
+)
+
+// Maps source VM metadata to a label or annotation
+// on the target VirtualMachine.
+type MetadataMapping struct {
+	// Source metadata kind: Tag, Attribute or Annotation.
+	Source string `json:"source"`
+	// The tag category or the custom attribute name.
+	// Not used by the Annotation kind.
+	Name string `json:"name,omitempty"`
+	// Target label key.
+	// Tags in the category are sorted and joined by `_`.
+	Label string `json:"label,omitempty"`
+	// Target annotation key.
+	// Tags in the category are sorted and joined by `,`.
+	Annotation string `json:"annotation,omitempty"`
+}
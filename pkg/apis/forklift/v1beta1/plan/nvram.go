@@ -0,0 +1,13 @@
+package plan
+
+// EFI variable store (NVRAM) handling.
+// The NVRAM of a (vSphere) source VM with secure boot is
+// copied to the persistent EFI variable store of the target
+// VM. The boot entries and the secure boot keys and databases
+// (PK, KEK, db, dbx) are copied.
+type NVRAM struct {
+	// Re-enroll the secure boot keys and databases.
+	// The default keys of the target firmware are enrolled
+	// instead of the keys copied from the source VM.
+	ReEnrollKeys bool `json:"reEnrollKeys,omitempty"`
+}
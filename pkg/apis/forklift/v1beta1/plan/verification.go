@@ -0,0 +1,36 @@
+package plan
+
+// Post-migration verification.
+// The target VM is started and checked before the
+// requested power state is restored. At most one of
+// the TCP, HTTP and command checks may be specified.
+type Verification struct {
+	// Timeout (minutes) for the VM to boot and pass the checks.
+	// Default: 10.
+	Timeout int `json:"timeout,omitempty"`
+	// Wait for the guest agent to connect.
+	GuestAgent bool `json:"guestAgent,omitempty"`
+	// TCP port to be connected.
+	TCPPort int32 `json:"tcpPort,omitempty"`
+	// HTTP endpoint to be checked.
+	HTTPGet *HTTPCheck `json:"httpGet,omitempty"`
+	// Command run in the guest through the guest agent.
+	// The check passes when the command exits zero.
+	Command []string `json:"command,omitempty"`
+}
+
+// Determine whether a health check is specified.
+func (r *Verification) HasCheck() bool {
+	return r.TCPPort > 0 || r.HTTPGet != nil || len(r.Command) > 0
+}
+
+// HTTP health check.
+// The check passes on a 2xx or 3xx response.
+type HTTPCheck struct {
+	// Port.
+	Port int32 `json:"port"`
+	// URL path.
+	Path string `json:"path,omitempty"`
+	// Scheme: HTTP or HTTPS.
+	Scheme string `json:"scheme,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"path"
+	"regexp"
 )
 
 // Plan hook.
@@ -29,6 +30,78 @@ type VM struct {
 	ref.Ref `json:",inline"`
 	// Enable hooks.
 	Hooks []HookRef `json:"hooks,omitempty"`
+	// Warm precopy scheduling.
+	// Overrides the plan precopy policy.
+	Precopy *PrecopyPolicy `json:"precopy,omitempty"`
+	// Secret (in the target namespace) containing the LUKS
+	// passphrases or key files used to open the encrypted
+	// guest disks during conversion.
+	// Overrides the plan LUKS secret.
+	LUKS *core.LocalObjectReference `json:"luks,omitempty"`
+	// Guest customization.
+	// Applied after the plan customization.
+	Customization []Customization `json:"customization,omitempty"`
+	// Guest conversion (virt-v2v) configuration.
+	// Overrides the plan conversion configuration.
+	Conversion *Conversion `json:"conversion,omitempty"`
+}
+
+// Warm precopy scheduling policy.
+type PrecopyPolicy struct {
+	// Interval (minutes) between precopies.
+	// Defaults to the PRECOPY_INTERVAL setting.
+	Interval int `json:"interval,omitempty"`
+	// Adapt the interval to the rate at which the source
+	// disks change as measured by previous precopies.
+	// Supported by vSphere sources only.
+	Adaptive bool `json:"adaptive,omitempty"`
+	// Minimum adaptive interval (minutes).
+	// Defaults to the PRECOPY_INTERVAL_MIN setting.
+	MinInterval int `json:"minInterval,omitempty"`
+	// Maximum adaptive interval (minutes).
+	// Defaults to the PRECOPY_INTERVAL_MAX setting.
+	MaxInterval int `json:"maxInterval,omitempty"`
+	// Precopy delta (MB) at or below which the VM is
+	// ready for cutover. Also the delta targeted by the
+	// adaptive interval.
+	// Defaults to the PRECOPY_READY_THRESHOLD setting.
+	ReadyThreshold int64 `json:"readyThreshold,omitempty"`
+}
+
+// VM selector.
+// Selects VMs from the provider inventory.
+// All of the specified criteria must match.
+type VMSelector struct {
+	// vSphere folder (inventory path). VMs contained
+	// in the folder and its sub-folders are selected.
+	Folder string `json:"folder,omitempty"`
+	// vSphere or oVirt cluster name or ID.
+	Cluster string `json:"cluster,omitempty"`
+	// OpenStack project name or ID.
+	Project string `json:"project,omitempty"`
+	// vSphere or OpenStack tags. VMs having all of the tags
+	// are selected. vSphere tags may be qualified by
+	// category as `category:name`.
+	Tags []string `json:"tags,omitempty"`
+	// vSphere custom attributes or OpenStack metadata. VMs
+	// having all of the (key, value) pairs are selected.
+	Labels map[string]string `json:"labels,omitempty"`
+	// VM name regular expression.
+	Name string `json:"name,omitempty"`
+	// Exclude VMs with critical concerns.
+	ExcludeCritical bool `json:"excludeCritical,omitempty"`
+	// Freeze the selected VMs while a migration is active.
+	Freeze bool `json:"freeze,omitempty"`
+}
+
+// Match the VM name.
+func (r *VMSelector) MatchName(name string) (matched bool, err error) {
+	if r.Name == "" {
+		matched = true
+		return
+	}
+	matched, err = regexp.MatchString(r.Name, name)
+	return
 }
 
 // Find a Hook for the specified step.
@@ -58,6 +131,16 @@ type VMStatus struct {
 	Warm *Warm `json:"warm,omitempty"`
 	// Source VM power state before migration.
 	RestorePowerState string `json:"restorePowerState,omitempty"`
+	// Source VM name when the target VM is renamed
+	// to a valid (DNS1123) name.
+	OriginalName string `json:"originalName,omitempty"`
+	// Actions performed to decommission the source VM.
+	// Undone actions are removed.
+	Decommission []DecommissionAction `json:"decommission,omitempty"`
+	// Concerns reported by the inventory when the migration started.
+	Concerns []Concern `json:"concerns,omitempty"`
+	// Disks transferred, captured when the migration started.
+	Disks []Disk `json:"disks,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
@@ -70,6 +153,10 @@ type Warm struct {
 	ConsecutiveFailures int        `json:"consecutiveFailures"`
 	NextPrecopyAt       *meta.Time `json:"nextPrecopyAt,omitempty"`
 	Precopies           []Precopy  `json:"precopies,omitempty"`
+	// Scheduled cutover.
+	Cutover *meta.Time `json:"cutover,omitempty"`
+	// Current precopy interval (minutes).
+	Interval int `json:"interval,omitempty"`
 }
 
 // Precopy durations
@@ -77,6 +164,8 @@ type Precopy struct {
 	Start    *meta.Time `json:"start,omitempty"`
 	End      *meta.Time `json:"end,omitempty"`
 	Snapshot string     `json:"snapshot,omitempty"`
+	// Data (MB) changed since the previous precopy.
+	Delta *int64 `json:"delta,omitempty"`
 }
 
 // Find a step by name.
@@ -92,6 +181,18 @@ func (r *VMStatus) FindStep(name string) (step *Step, found bool) {
 	return
 }
 
+// Find the decommission action.
+func (r *VMStatus) FindDecommission(action string) (found *DecommissionAction) {
+	for i := range r.Decommission {
+		if r.Decommission[i].Action == action {
+			found = &r.Decommission[i]
+			break
+		}
+	}
+
+	return
+}
+
 // Add an error.
 func (r *VMStatus) AddError(reason ...string) {
 	if r.Error == nil {
@@ -122,3 +223,23 @@ func (r *VMStatus) ReflectPipeline() {
 		r.MarkCompleted()
 	}
 }
+
+// Source VM concern.
+type Concern struct {
+	// Label.
+	Label string `json:"label"`
+	// Category.
+	Category string `json:"category"`
+	// Assessment.
+	Assessment string `json:"assessment,omitempty"`
+}
+
+// Disk transferred to the destination.
+type Disk struct {
+	// Source storage ID.
+	Storage string `json:"storage,omitempty"`
+	// Destination storage class.
+	StorageClass string `json:"storageClass,omitempty"`
+	// Size (bytes).
+	Size int64 `json:"size"`
+}
@@ -21,7 +21,183 @@ limitations under the License.
 
 package plan
 
-import ()
+import (
+	"k8s.io/api/core/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Concern) DeepCopyInto(out *Concern) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Concern.
+func (in *Concern) DeepCopy() *Concern {
+	if in == nil {
+		return nil
+	}
+	out := new(Concern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conversion) DeepCopyInto(out *Conversion) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestKVM != nil {
+		in, out := &in.RequestKVM, &out.RequestKVM
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Scratch != nil {
+		in, out := &in.Scratch, &out.Scratch
+		*out = new(ScratchVolume)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conversion.
+func (in *Conversion) DeepCopy() *Conversion {
+	if in == nil {
+		return nil
+	}
+	out := new(Conversion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Customization) DeepCopyInto(out *Customization) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Firstboot != nil {
+		in, out := &in.Firstboot, &out.Firstboot
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upload != nil {
+		in, out := &in.Upload, &out.Upload
+		*out = make([]Upload, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Customization.
+func (in *Customization) DeepCopy() *Customization {
+	if in == nil {
+		return nil
+	}
+	out := new(Customization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Decommission) DeepCopyInto(out *Decommission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Decommission.
+func (in *Decommission) DeepCopy() *Decommission {
+	if in == nil {
+		return nil
+	}
+	out := new(Decommission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionAction) DeepCopyInto(out *DecommissionAction) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionAction.
+func (in *DecommissionAction) DeepCopy() *DecommissionAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Devices) DeepCopyInto(out *Devices) {
+	*out = *in
+	if in.ISOs != nil {
+		in, out := &in.ISOs, &out.ISOs
+		*out = make([]ISOMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Devices.
+func (in *Devices) DeepCopy() *Devices {
+	if in == nil {
+		return nil
+	}
+	out := new(Devices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Disk) DeepCopyInto(out *Disk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Disk.
+func (in *Disk) DeepCopy() *Disk {
+	if in == nil {
+		return nil
+	}
+	out := new(Disk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Estimate) DeepCopyInto(out *Estimate) {
+	*out = *in
+	if in.ETA != nil {
+		in, out := &in.ETA, &out.ETA
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Estimate.
+func (in *Estimate) DeepCopy() *Estimate {
+	if in == nil {
+		return nil
+	}
+	out := new(Estimate)
+	in.DeepCopyInto(out)
+	return out
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Error) DeepCopyInto(out *Error) {
@@ -43,6 +219,21 @@ func (in *Error) DeepCopy() *Error {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
+func (in *HTTPCheck) DeepCopy() *HTTPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HookRef) DeepCopyInto(out *HookRef) {
 	*out = *in
@@ -59,6 +250,56 @@ func (in *HookRef) DeepCopy() *HookRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ISOMapping) DeepCopyInto(out *ISOMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISOMapping.
+func (in *ISOMapping) DeepCopy() *ISOMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ISOMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LUNVolume) DeepCopyInto(out *LUNVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LUNVolume.
+func (in *LUNVolume) DeepCopy() *LUNVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(LUNVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LUNs) DeepCopyInto(out *LUNs) {
+	*out = *in
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]LUNVolume, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LUNs.
+func (in *LUNs) DeepCopy() *LUNs {
+	if in == nil {
+		return nil
+	}
+	out := new(LUNs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Map) DeepCopyInto(out *Map) {
 	*out = *in
@@ -76,6 +317,21 @@ func (in *Map) DeepCopy() *Map {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataMapping) DeepCopyInto(out *MetadataMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataMapping.
+func (in *MetadataMapping) DeepCopy() *MetadataMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 	*out = *in
@@ -110,6 +366,44 @@ func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NVRAM) DeepCopyInto(out *NVRAM) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NVRAM.
+func (in *NVRAM) DeepCopy() *NVRAM {
+	if in == nil {
+		return nil
+	}
+	out := new(NVRAM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanEstimate) DeepCopyInto(out *PlanEstimate) {
+	*out = *in
+	in.Estimate.DeepCopyInto(&out.Estimate)
+	if in.VMs != nil {
+		in, out := &in.VMs, &out.VMs
+		*out = make([]VMEstimate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanEstimate.
+func (in *PlanEstimate) DeepCopy() *PlanEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Precopy) DeepCopyInto(out *Precopy) {
 	*out = *in
@@ -121,6 +415,11 @@ func (in *Precopy) DeepCopyInto(out *Precopy) {
 		in, out := &in.End, &out.End
 		*out = (*in).DeepCopy()
 	}
+	if in.Delta != nil {
+		in, out := &in.Delta, &out.Delta
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Precopy.
@@ -133,6 +432,41 @@ func (in *Precopy) DeepCopy() *Precopy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrecopyPolicy) DeepCopyInto(out *PrecopyPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrecopyPolicy.
+func (in *PrecopyPolicy) DeepCopy() *PrecopyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PrecopyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScratchVolume) DeepCopyInto(out *ScratchVolume) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScratchVolume.
+func (in *ScratchVolume) DeepCopy() *ScratchVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(ScratchVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Snapshot) DeepCopyInto(out *Snapshot) {
 	*out = *in
@@ -281,6 +615,46 @@ func (in *Timed) DeepCopy() *Timed {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Verification) DeepCopyInto(out *Verification) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPCheck)
+		**out = **in
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verification.
+func (in *Verification) DeepCopy() *Verification {
+	if in == nil {
+		return nil
+	}
+	out := new(Verification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upload) DeepCopyInto(out *Upload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upload.
+func (in *Upload) DeepCopy() *Upload {
+	if in == nil {
+		return nil
+	}
+	out := new(Upload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VM) DeepCopyInto(out *VM) {
 	*out = *in
@@ -290,6 +664,28 @@ func (in *VM) DeepCopyInto(out *VM) {
 		*out = make([]HookRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.Precopy != nil {
+		in, out := &in.Precopy, &out.Precopy
+		*out = new(PrecopyPolicy)
+		**out = **in
+	}
+	if in.LUKS != nil {
+		in, out := &in.LUKS, &out.LUKS
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Customization != nil {
+		in, out := &in.Customization, &out.Customization
+		*out = make([]Customization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(Conversion)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VM.
@@ -302,6 +698,50 @@ func (in *VM) DeepCopy() *VM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSelector) DeepCopyInto(out *VMSelector) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSelector.
+func (in *VMSelector) DeepCopy() *VMSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMEstimate) DeepCopyInto(out *VMEstimate) {
+	*out = *in
+	out.Ref = in.Ref
+	in.Estimate.DeepCopyInto(&out.Estimate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMEstimate.
+func (in *VMEstimate) DeepCopy() *VMEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(VMEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 	*out = *in
@@ -328,6 +768,23 @@ func (in *VMStatus) DeepCopyInto(out *VMStatus) {
 		*out = new(Warm)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = make([]DecommissionAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Concerns != nil {
+		in, out := &in.Concerns, &out.Concerns
+		*out = make([]Concern, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]Disk, len(*in))
+		copy(*out, *in)
+	}
 	in.Conditions.DeepCopyInto(&out.Conditions)
 }
 
@@ -355,6 +812,10 @@ func (in *Warm) DeepCopyInto(out *Warm) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Warm.
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	core "k8s.io/api/core/v1"
@@ -70,6 +72,100 @@ type ProviderSpec struct {
 	Secret core.ObjectReference `json:"secret" ref:"Secret"`
 	// Provider settings.
 	Settings map[string]string `json:"settings,omitempty"`
+	// Inventory scopes.
+	// Users and groups matched by a scope may access only
+	// the inventory VMs within the scope. When scopes are
+	// defined, unmatched users may access the inventory only
+	// when permitted to update the provider.
+	Scopes []ProviderScope `json:"scopes,omitempty"`
+}
+
+// Inventory scope.
+// A VM is within the scope when contained in any of the
+// listed folders, clusters or projects.
+type ProviderScope struct {
+	// Users (names).
+	Users []string `json:"users,omitempty"`
+	// Groups (names).
+	Groups []string `json:"groups,omitempty"`
+	// vSphere folders (inventory path).
+	Folders []string `json:"folders,omitempty"`
+	// vSphere or oVirt clusters (name or ID).
+	Clusters []string `json:"clusters,omitempty"`
+	// OpenStack projects (name or ID).
+	Projects []string `json:"projects,omitempty"`
+}
+
+// Determine whether the folder path is within the scope.
+func (r *ProviderScope) HasFolder(path string) bool {
+	path = strings.TrimRight(path, "/")
+	for _, folder := range r.Folders {
+		folder = strings.TrimRight(folder, "/")
+		if folder == "" {
+			continue
+		}
+		if path == folder || strings.HasPrefix(path, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Determine whether the cluster is within the scope.
+func (r *ProviderScope) HasCluster(id, name string) bool {
+	return r.has(r.Clusters, id, name)
+}
+
+// Determine whether the project is within the scope.
+func (r *ProviderScope) HasProject(id, name string) bool {
+	return r.has(r.Projects, id, name)
+}
+
+// Determine whether the list contains the ID or name.
+func (r *ProviderScope) has(list []string, id, name string) bool {
+	for _, s := range list {
+		if s != "" && (s == id || s == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Determine whether the scope matches the user.
+func (r *ProviderScope) matched(user string, groups []string) bool {
+	for _, u := range r.Users {
+		if u == user {
+			return true
+		}
+	}
+	for _, g := range r.Groups {
+		for _, group := range groups {
+			if g == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The inventory scope of a user.
+// Returns the union of the scopes matching the user
+// and groups. Returns nil when no scope is matched.
+func (r *ProviderSpec) Scope(user string, groups []string) (scope *ProviderScope) {
+	for i := range r.Scopes {
+		matched := &r.Scopes[i]
+		if !matched.matched(user, groups) {
+			continue
+		}
+		if scope == nil {
+			scope = &ProviderScope{}
+		}
+		scope.Folders = append(scope.Folders, matched.Folders...)
+		scope.Clusters = append(scope.Clusters, matched.Clusters...)
+		scope.Projects = append(scope.Projects, matched.Projects...)
+	}
+
+	return
 }
 
 // ProviderStatus defines the observed state of Provider
@@ -22,6 +22,15 @@ func (r Ref) NotSet() bool {
 	return r.ID == "" && r.Name == ""
 }
 
+// Determine whether the refs identify the same object.
+// Matched by ID when both are set, otherwise by name.
+func (r Ref) Same(ref Ref) bool {
+	if r.ID != "" && ref.ID != "" {
+		return r.ID == ref.ID
+	}
+	return r.Name != "" && r.Name == ref.Name
+}
+
 // String representation.
 func (r *Ref) String() (s string) {
 	if r.Type != "" {
@@ -331,6 +331,13 @@ func (in *MigrationSpec) DeepCopyInto(out *MigrationSpec) {
 		in, out := &in.Cutover, &out.Cutover
 		*out = (*in).DeepCopy()
 	}
+	if in.Cutovers != nil {
+		in, out := &in.Cutovers, &out.Cutovers
+		*out = make([]VMCutover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSpec.
@@ -371,6 +378,21 @@ func (in *MigrationStatus) DeepCopy() *MigrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NadGeneration) DeepCopyInto(out *NadGeneration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NadGeneration.
+func (in *NadGeneration) DeepCopy() *NadGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(NadGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkMap) DeepCopyInto(out *NetworkMap) {
 	*out = *in
@@ -440,6 +462,11 @@ func (in *NetworkMapSpec) DeepCopyInto(out *NetworkMapSpec) {
 		*out = make([]NetworkPair, len(*in))
 		copy(*out, *in)
 	}
+	if in.Generate != nil {
+		in, out := &in.Generate, &out.Generate
+		*out = new(NadGeneration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMapSpec.
@@ -452,6 +479,27 @@ func (in *NetworkMapSpec) DeepCopy() *NetworkMapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkMapStatus) DeepCopyInto(out *NetworkMapStatus) {
+	*out = *in
+	in.MapStatus.DeepCopyInto(&out.MapStatus)
+	if in.Generated != nil {
+		in, out := &in.Generated, &out.Generated
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkMapStatus.
+func (in *NetworkMapStatus) DeepCopy() *NetworkMapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkMapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPair) DeepCopyInto(out *NetworkPair) {
 	*out = *in
@@ -729,11 +777,68 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(plan.VMSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Precopy != nil {
+		in, out := &in.Precopy, &out.Precopy
+		*out = new(plan.PrecopyPolicy)
+		**out = **in
+	}
 	if in.TransferNetwork != nil {
 		in, out := &in.TransferNetwork, &out.TransferNetwork
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.MetadataMapping != nil {
+		in, out := &in.MetadataMapping, &out.MetadataMapping
+		*out = make([]plan.MetadataMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(plan.Verification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(plan.Decommission)
+		**out = **in
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = new(plan.Devices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LUNs != nil {
+		in, out := &in.LUNs, &out.LUNs
+		*out = new(plan.LUNs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LUKS != nil {
+		in, out := &in.LUKS, &out.LUKS
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Customization != nil {
+		in, out := &in.Customization, &out.Customization
+		*out = make([]plan.Customization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(plan.Conversion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NVRAM != nil {
+		in, out := &in.NVRAM, &out.NVRAM
+		*out = new(plan.NVRAM)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -751,6 +856,16 @@ func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
 	in.Migration.DeepCopyInto(&out.Migration)
+	if in.SelectedVMs != nil {
+		in, out := &in.SelectedVMs, &out.SelectedVMs
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
+	if in.Estimate != nil {
+		in, out := &in.Estimate, &out.Estimate
+		*out = new(plan.PlanEstimate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
@@ -822,6 +937,46 @@ func (in *ProviderList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderScope) DeepCopyInto(out *ProviderScope) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Folders != nil {
+		in, out := &in.Folders, &out.Folders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderScope.
+func (in *ProviderScope) DeepCopy() *ProviderScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
@@ -838,6 +993,13 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]ProviderScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
@@ -963,3 +1125,23 @@ func (in *StoragePair) DeepCopy() *StoragePair {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMCutover) DeepCopyInto(out *VMCutover) {
+	*out = *in
+	out.Ref = in.Ref
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMCutover.
+func (in *VMCutover) DeepCopy() *VMCutover {
+	if in == nil {
+		return nil
+	}
+	out := new(VMCutover)
+	in.DeepCopyInto(out)
+	return out
+}
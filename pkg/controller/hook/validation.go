@@ -47,6 +47,15 @@ func (r *Reconciler) validate(hook *api.Hook) (err error) {
 	return
 }
 
+// Validate the hook.
+// The validation does not require the cluster API which
+// permits it to be performed by the admission webhook.
+func Validate(hook *api.Hook) (err error) {
+	r := Reconciler{}
+	err = r.validate(hook)
+	return
+}
+
 // Validate the hook.
 func (r *Reconciler) validateImage(hook *api.Hook) (err error) {
 	match := ReferenceRegexp.MatchString(hook.Spec.Image)
@@ -98,6 +98,17 @@ func Add(mgr manager.Manager) error {
 		log.Trace(err)
 		return err
 	}
+	// Plans (generated NAD namespaces).
+	err = cnt.Watch(
+		&source.Kind{
+			Type: &api.Plan{},
+		},
+		handler.EnqueueRequestsFromMapFunc(RequestForPlan),
+		&PlanPredicate{})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
 
 	return nil
 }
@@ -139,6 +150,16 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		r.Log.V(2).Info("Conditions.", "all", mp.Status.Conditions)
 	}()
 
+	// Generated NADs.
+	if mp.DeletionTimestamp != nil {
+		err = r.deleteGenerated(mp)
+		return
+	}
+	err = r.ensureFinalizer(mp)
+	if err != nil {
+		return
+	}
+
 	// Begin staging conditions.
 	mp.Status.BeginStagingConditions()
 
@@ -0,0 +1,419 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+
+	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Generated NADs.
+const (
+	// Finalizer ensuring generated NADs are deleted with the map.
+	Finalizer = "forklift.konveyor.io/generated-nads"
+	// Label (map UID) on generated NADs.
+	GeneratedLabel = "forklift.konveyor.io/networkMap"
+)
+
+// NAD generation types.
+const (
+	BridgeNad   = "bridge"
+	LocalnetNad = "localnet"
+)
+
+// NAD (CNI) plugin type for generated bridge NADs.
+const BridgePlugin = "cnv-bridge"
+
+// VLAN ID range.
+const (
+	MinVlan = 1
+	MaxVlan = 4094
+)
+
+// Generated NAD (CNI) configuration.
+type generatedConfig struct {
+	CNIVersion string `json:"cniVersion"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	// Bridge.
+	Bridge        string `json:"bridge,omitempty"`
+	Vlan          int    `json:"vlan,omitempty"`
+	MacSpoofCheck bool   `json:"macspoofchk,omitempty"`
+	// OVN-Kubernetes localnet.
+	Topology string `json:"topology,omitempty"`
+	NadName  string `json:"netAttachDefName,omitempty"`
+	VlanID   int    `json:"vlanID,omitempty"`
+}
+
+// Ensure the finalizer is set when NADs are (or have been) generated.
+// Must be called before conditions are staged because the map
+// is updated.
+func (r *Reconciler) ensureFinalizer(mp *api.NetworkMap) (err error) {
+	if mp.Spec.Generate == nil && len(mp.Status.Generated) == 0 {
+		return
+	}
+	for _, f := range mp.Finalizers {
+		if f == Finalizer {
+			return
+		}
+	}
+	mp.Finalizers = append(mp.Finalizers, Finalizer)
+	err = r.Update(context.TODO(), mp)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Delete the generated NADs and remove the finalizer.
+// NADs no longer labeled with the map UID have been
+// replaced and are not deleted. The NADs are abandoned
+// when the destination provider no longer exists.
+func (r *Reconciler) deleteGenerated(mp *api.NetworkMap) (err error) {
+	kept := []string{}
+	found := false
+	for _, f := range mp.Finalizers {
+		if f == Finalizer {
+			found = true
+			continue
+		}
+		kept = append(kept, f)
+	}
+	if !found {
+		return
+	}
+	pv := validation.ProviderPair{Client: r}
+	_, err = pv.Validate(mp.Spec.Provider)
+	if err != nil {
+		return
+	}
+	mp.Referenced.Provider.Destination = pv.Referenced.Destination
+	if mp.Referenced.Provider.Destination == nil {
+		r.Log.Info(
+			"Destination provider not found, generated NADs abandoned.",
+			"nads",
+			mp.Status.Generated)
+	} else if len(mp.Status.Generated) > 0 {
+		var destination client.Client
+		destination, err = r.destinationClient(mp)
+		if err != nil {
+			return
+		}
+		for _, ref := range mp.Status.Generated {
+			nad := &net.NetworkAttachmentDefinition{}
+			err = destination.Get(
+				context.TODO(),
+				client.ObjectKey{
+					Namespace: ref.Namespace,
+					Name:      ref.Name,
+				},
+				nad)
+			if err != nil {
+				if k8serr.IsNotFound(err) {
+					err = nil
+					continue
+				}
+				err = liberr.Wrap(err)
+				return
+			}
+			if nad.Labels[GeneratedLabel] != string(mp.UID) {
+				continue
+			}
+			err = destination.Delete(context.TODO(), nad)
+			if err != nil && !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+			r.Log.Info(
+				"Generated NAD deleted.",
+				"nad",
+				path.Join(ref.Namespace, ref.Name))
+		}
+	}
+	mp.Finalizers = kept
+	err = r.Update(context.TODO(), mp)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Generate missing destination NADs.
+// Created for mapped source networks with a multus (bridge)
+// or ovn (localnet) destination that does not exist. NADs are
+// only generated in the map namespace or the target namespace
+// of a plan (in the map namespace) using the map and only for
+// source networks with a known VLAN.
+func (r *Reconciler) generate(mp *api.NetworkMap) (err error) {
+	generate := mp.Spec.Generate
+	if generate == nil {
+		return
+	}
+	if mp.Status.HasAnyCondition(
+		SourceNetworkNotValid,
+		DestinationNetworkNotValid) {
+		return
+	}
+	kind := api.MultusNetwork
+	if generate.Type == LocalnetNad {
+		kind = api.OvnNetwork
+	}
+	namespaces, err := r.generateNamespaces(mp)
+	if err != nil {
+		return
+	}
+	destination, err := r.destinationClient(mp)
+	if err != nil {
+		return
+	}
+	inventory, err := web.NewClient(mp.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	notPermitted := []string{}
+	vlanNotFound := []string{}
+	for _, entry := range mp.Spec.Map {
+		if entry.Destination.Type != kind ||
+			entry.Destination.Namespace == "" ||
+			entry.Destination.Name == "" {
+			continue
+		}
+		key := client.ObjectKey{
+			Namespace: entry.Destination.Namespace,
+			Name:      entry.Destination.Name,
+		}
+		nad := &net.NetworkAttachmentDefinition{}
+		err = destination.Get(context.TODO(), key, nad)
+		if err == nil {
+			if nad.Labels[GeneratedLabel] == string(mp.UID) {
+				r.recordGenerated(mp, nad)
+			}
+			continue
+		}
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		if !namespaces[key.Namespace] {
+			notPermitted = append(
+				notPermitted,
+				path.Join(key.Namespace, key.Name))
+			continue
+		}
+		ref := entry.Source
+		network, pErr := inventory.Network(&ref)
+		if pErr != nil {
+			// Reported by source validation.
+			continue
+		}
+		vlan := Vlan(network)
+		if vlan == 0 {
+			vlanNotFound = append(vlanNotFound, ref.String())
+			continue
+		}
+		nad, err = Generated(mp, key, vlan)
+		if err != nil {
+			return
+		}
+		err = destination.Create(context.TODO(), nad)
+		if err != nil {
+			if k8serr.IsAlreadyExists(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		r.recordGenerated(mp, nad)
+		r.Log.Info(
+			"NAD generated.",
+			"nad",
+			path.Join(key.Namespace, key.Name),
+			"source",
+			ref.String())
+	}
+	if len(notPermitted) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     NadNotGenerated,
+			Status:   True,
+			Reason:   NotPermitted,
+			Category: Warn,
+			Message:  "NAD not generated: the namespace is neither the map namespace nor a plan target namespace.",
+			Items:    notPermitted,
+		})
+	}
+	if len(vlanNotFound) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     SourceVlanNotFound,
+			Status:   True,
+			Reason:   NotFound,
+			Category: Warn,
+			Message:  "NAD not generated: the source network VLAN is not known.",
+			Items:    vlanNotFound,
+		})
+	}
+
+	return
+}
+
+// Namespaces in which NADs may be generated.
+// The map namespace and the target namespace of the
+// plans (in the map namespace) using the map.
+func (r *Reconciler) generateNamespaces(mp *api.NetworkMap) (namespaces map[string]bool, err error) {
+	namespaces = map[string]bool{
+		mp.Namespace: true,
+	}
+	list := &api.PlanList{}
+	err = r.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: mp.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		plan := &list.Items[i]
+		ref := plan.Spec.Map.Network
+		if ref.Namespace != mp.Namespace || ref.Name != mp.Name {
+			continue
+		}
+		if plan.Spec.TargetNamespace != "" {
+			namespaces[plan.Spec.TargetNamespace] = true
+		}
+	}
+
+	return
+}
+
+// Record the generated NAD in the map status.
+func (r *Reconciler) recordGenerated(mp *api.NetworkMap, nad *net.NetworkAttachmentDefinition) {
+	for _, ref := range mp.Status.Generated {
+		if ref.Namespace == nad.Namespace && ref.Name == nad.Name {
+			return
+		}
+	}
+	mp.Status.Generated = append(
+		mp.Status.Generated,
+		core.ObjectReference{
+			Kind:       "NetworkAttachmentDefinition",
+			APIVersion: net.SchemeGroupVersion.String(),
+			Namespace:  nad.Namespace,
+			Name:       nad.Name,
+		})
+}
+
+// The NAD (<namespace>/<name>) has been generated.
+// Generated NADs may not yet be in the inventory.
+func generated(mp *api.NetworkMap, id string) bool {
+	for _, ref := range mp.Status.Generated {
+		if path.Join(ref.Namespace, ref.Name) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Build a client for the destination cluster.
+func (r *Reconciler) destinationClient(mp *api.NetworkMap) (destination client.Client, err error) {
+	provider := mp.Referenced.Provider.Destination
+	if provider.IsHost() {
+		destination, err = provider.Client(nil)
+		return
+	}
+	ref := provider.Spec.Secret
+	secret := &core.Secret{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	destination, err = provider.Client(secret)
+
+	return
+}
+
+// Build a generated NAD.
+// The VLAN is omitted when zero (untagged).
+func Generated(mp *api.NetworkMap, key client.ObjectKey, vlan int) (nad *net.NetworkAttachmentDefinition, err error) {
+	generate := mp.Spec.Generate
+	config := generatedConfig{
+		CNIVersion: "0.3.1",
+	}
+	switch generate.Type {
+	case LocalnetNad:
+		config.Name = generate.Bridge
+		config.Type = api.OvnPlugin
+		config.Topology = api.OvnLocalnet
+		config.NadName = path.Join(key.Namespace, key.Name)
+		config.VlanID = vlan
+	default:
+		config.Name = key.Name
+		config.Type = BridgePlugin
+		config.Bridge = generate.Bridge
+		config.Vlan = vlan
+		config.MacSpoofCheck = true
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	nad = &net.NetworkAttachmentDefinition{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				GeneratedLabel: string(mp.UID),
+			},
+		},
+		Spec: net.NetworkAttachmentDefinitionSpec{
+			Config: string(b),
+		},
+	}
+
+	return
+}
+
+// VLAN ID of the source network.
+// Zero when untagged or not known.
+func Vlan(network interface{}) (id int) {
+	tag := ""
+	switch n := network.(type) {
+	case *vsphere.Network:
+		tag = n.Tag
+	case *ovirt.Network:
+		tag = n.VLan
+	}
+	id, err := strconv.Atoi(tag)
+	if err != nil || id < MinVlan || id > MaxVlan {
+		id = 0
+	}
+
+	return
+}
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	key := client.ObjectKey{Namespace: "ns", Name: "vlan-10"}
+	mp := &api.NetworkMap{}
+	mp.UID = "map-uid"
+
+	// Bridge.
+	mp.Spec.Generate = &api.NadGeneration{Bridge: "br1"}
+	nad, err := Generated(mp, key, 10)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(nad.Labels[GeneratedLabel]).To(gomega.Equal("map-uid"))
+	config := map[string]interface{}{}
+	g.Expect(json.Unmarshal([]byte(nad.Spec.Config), &config)).To(gomega.Succeed())
+	g.Expect(config["type"]).To(gomega.Equal(BridgePlugin))
+	g.Expect(config["bridge"]).To(gomega.Equal("br1"))
+	g.Expect(config["vlan"]).To(gomega.Equal(float64(10)))
+
+	// Localnet (untagged).
+	mp.Spec.Generate = &api.NadGeneration{Type: LocalnetNad, Bridge: "physnet"}
+	nad, err = Generated(mp, key, 0)
+	g.Expect(err).To(gomega.BeNil())
+	config = map[string]interface{}{}
+	g.Expect(json.Unmarshal([]byte(nad.Spec.Config), &config)).To(gomega.Succeed())
+	g.Expect(config["name"]).To(gomega.Equal("physnet"))
+	g.Expect(config["netAttachDefName"]).To(gomega.Equal("ns/vlan-10"))
+	g.Expect(config).ToNot(gomega.HaveKey("vlanID"))
+	g.Expect(Compatible(api.OvnNetwork, &ocp.NetworkAttachmentDefinition{Object: *nad})).To(gomega.BeTrue())
+}
+
+func TestVlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(Vlan(&vsphere.Network{Tag: "100"})).To(gomega.Equal(100))
+	g.Expect(Vlan(&ovirt.Network{VLan: "4094"})).To(gomega.Equal(4094))
+	g.Expect(Vlan(&ovirt.Network{VLan: ""})).To(gomega.Equal(0))
+	g.Expect(Vlan(&vsphere.Network{Tag: "5000"})).To(gomega.Equal(0))
+}
+
+func TestGenerateNamespaces(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	s := runtime.NewScheme()
+	g.Expect(apis.AddToScheme(s)).To(gomega.Succeed())
+	plan := func(namespace, name, target string) *api.Plan {
+		p := &api.Plan{}
+		p.Namespace = namespace
+		p.Name = name
+		p.Spec.TargetNamespace = target
+		p.Spec.Map.Network = core.ObjectReference{Namespace: "ns", Name: "map"}
+		return p
+	}
+	other := plan("ns", "other", "other")
+	other.Spec.Map.Network.Name = "other"
+	r := Reconciler{}
+	r.Client = fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(
+			plan("ns", "a", "target"),
+			plan("elsewhere", "b", "elsewhere"),
+			other).
+		Build()
+	mp := &api.NetworkMap{}
+	mp.Namespace = "ns"
+	mp.Name = "map"
+	namespaces, err := r.generateNamespaces(mp)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(namespaces).To(gomega.Equal(map[string]bool{"ns": true, "target": true}))
+}
+
+func TestRequestForPlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Namespace = "ns"
+	p.Spec.Map.Network = core.ObjectReference{Namespace: "ns", Name: "map"}
+	g.Expect(RequestForPlan(p)).To(gomega.HaveLen(1))
+	p.Spec.Map.Network.Namespace = "other"
+	g.Expect(RequestForPlan(p)).To(gomega.BeEmpty())
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/map/network/handler"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	libref "github.com/konveyor/forklift-controller/pkg/lib/ref"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 type MapPredicate struct {
@@ -29,7 +31,8 @@ func (r MapPredicate) Update(e event.UpdateEvent) bool {
 	if !cast {
 		return false
 	}
-	changed := object.Status.ObservedGeneration < object.Generation
+	changed := object.Status.ObservedGeneration < object.Generation ||
+		object.DeletionTimestamp != nil
 	if changed {
 		libref.Mapper.Update(e)
 	}
@@ -121,3 +124,58 @@ func (r *ProviderPredicate) ensureWatch(p *api.Provider) {
 		return
 	}
 }
+
+// Plan watch predicate.
+// NADs may be generated in the target namespace
+// of plans using the map.
+type PlanPredicate struct {
+	predicate.Funcs
+}
+
+// Plan created event.
+func (r PlanPredicate) Create(e event.CreateEvent) bool {
+	_, cast := e.Object.(*api.Plan)
+	return cast
+}
+
+// Plan updated event.
+func (r PlanPredicate) Update(e event.UpdateEvent) bool {
+	old, cast := e.ObjectOld.(*api.Plan)
+	if !cast {
+		return false
+	}
+	plan, cast := e.ObjectNew.(*api.Plan)
+	if !cast {
+		return false
+	}
+	changed := old.Spec.TargetNamespace != plan.Spec.TargetNamespace ||
+		old.Spec.Map.Network != plan.Spec.Map.Network
+
+	return changed
+}
+
+// Plan deleted event.
+func (r PlanPredicate) Delete(e event.DeleteEvent) bool {
+	return false
+}
+
+// Map the plan to the network map.
+// Only maps in the plan namespace are considered.
+func RequestForPlan(a client.Object) (list []reconcile.Request) {
+	if p, cast := a.(*api.Plan); cast {
+		ref := &p.Spec.Map.Network
+		if !libref.RefSet(ref) || ref.Namespace != p.Namespace {
+			return
+		}
+		list = append(
+			list,
+			reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ref.Namespace,
+					Name:      ref.Name,
+				},
+			})
+	}
+
+	return
+}
@@ -5,15 +5,19 @@ import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Types
 const (
 	SourceNetworkNotValid      = "SourceNetworkNotValid"
 	DestinationNetworkNotValid = "DestinationNetworkNotValid"
+	NadNotGenerated            = "NadNotGenerated"
+	SourceVlanNotFound         = "SourceVlanNotFound"
 )
 
 // Categories
@@ -27,9 +31,12 @@ const (
 
 // Reasons
 const (
-	NotSet    = "NotSet"
-	NotFound  = "NotFound"
-	Ambiguous = "Ambiguous"
+	NotSet        = "NotSet"
+	NotFound      = "NotFound"
+	NotUnique     = "NotUnique"
+	Ambiguous     = "Ambiguous"
+	NotCompatible = "NotCompatible"
+	NotPermitted  = "NotPermitted"
 )
 
 // Statuses
@@ -38,12 +45,6 @@ const (
 	False = libcnd.False
 )
 
-// Network types.
-const (
-	Pod    = "pod"
-	Multus = "multus"
-)
-
 // Validate the mp resource.
 func (r *Reconciler) validate(mp *api.NetworkMap) error {
 	pv := validation.ProviderPair{Client: r}
@@ -61,10 +62,15 @@ func (r *Reconciler) validate(mp *api.NetworkMap) error {
 	}
 	mp.Referenced.Provider.Source = pv.Referenced.Source
 	mp.Referenced.Provider.Destination = pv.Referenced.Destination
+	r.validateRefs(mp)
 	err = r.validateSource(mp)
 	if err != nil {
 		return err
 	}
+	err = r.generate(mp)
+	if err != nil {
+		return err
+	}
 	err = r.validateDestination(mp)
 	if err != nil {
 		return err
@@ -73,6 +79,95 @@ func (r *Reconciler) validate(mp *api.NetworkMap) error {
 	return nil
 }
 
+// Validate the map using only the cluster API.
+// The provider inventory is not consulted which permits
+// the validation to be performed by the admission webhook.
+func Validate(client client.Client, mp *api.NetworkMap) (err error) {
+	pv := validation.ProviderPair{Client: client}
+	conditions, err := pv.Validate(mp.Spec.Provider)
+	if err != nil {
+		return
+	}
+	mp.Status.UpdateConditions(conditions)
+	r := Reconciler{}
+	r.validateRefs(mp)
+	return
+}
+
+// Validate the refs (syntax) of the map entries.
+func (r *Reconciler) validateRefs(mp *api.NetworkMap) {
+	notSet := []string{}
+	notUnique := []string{}
+	ambiguous := []string{}
+	setOf := map[string]bool{}
+	for _, entry := range mp.Spec.Map {
+		ref := entry.Source
+		if ref.NotSet() {
+			mp.Status.SetCondition(libcnd.Condition{
+				Type:     SourceNetworkNotValid,
+				Status:   True,
+				Reason:   NotSet,
+				Category: Critical,
+				Message:  "Source network: either `ID` or `Name` required.",
+			})
+		} else {
+			key := ref.ID
+			if key == "" {
+				key = ref.Name
+			}
+			if _, found := setOf[key]; found {
+				notUnique = append(notUnique, ref.String())
+			} else {
+				setOf[key] = true
+			}
+		}
+		switch entry.Destination.Type {
+		case api.MultusNetwork, api.SriovNetwork, api.OvnNetwork:
+			if entry.Destination.Name == "" {
+				notSet = append(notSet, entry.Source.String())
+				continue
+			}
+			if entry.Destination.Namespace == "" {
+				ambiguous = append(
+					ambiguous,
+					path.Join(
+						entry.Destination.Namespace,
+						entry.Destination.Name))
+			}
+		}
+	}
+	if len(notUnique) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     SourceNetworkNotValid,
+			Status:   True,
+			Reason:   NotUnique,
+			Category: Critical,
+			Message:  "Source network mapped more than once.",
+			Items:    notUnique,
+		})
+	}
+	if len(notSet) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     DestinationNetworkNotValid,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "Destination network (NAD) name required.",
+			Items:    notSet,
+		})
+	}
+	if len(ambiguous) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     DestinationNetworkNotValid,
+			Status:   True,
+			Reason:   Ambiguous,
+			Category: Critical,
+			Message:  "Destination network (NAD) namespace required.",
+			Items:    ambiguous,
+		})
+	}
+}
+
 // Validate source refs.
 func (r *Reconciler) validateSource(mp *api.NetworkMap) (err error) {
 	provider := mp.Provider.Source
@@ -87,13 +182,6 @@ func (r *Reconciler) validateSource(mp *api.NetworkMap) (err error) {
 	for i := range list {
 		ref := &list[i].Source
 		if ref.NotSet() {
-			mp.Status.SetCondition(libcnd.Condition{
-				Type:     SourceNetworkNotValid,
-				Status:   True,
-				Reason:   NotSet,
-				Category: Critical,
-				Message:  "Source network: either `ID` or `Name` required.",
-			})
 			continue
 		}
 		_, pErr := inventory.Network(ref)
@@ -145,36 +233,35 @@ func (r *Reconciler) validateDestination(mp *api.NetworkMap) (err error) {
 	}
 	list := mp.Spec.Map
 	notFound := []string{}
-	ambiguous := []string{}
+	notCompatible := []string{}
 next:
 	for _, entry := range list {
 		switch entry.Destination.Type {
-		case Pod:
+		case api.PodNetwork, api.IgnoredNetwork:
 			continue next
-		case Multus:
-			if entry.Destination.Namespace == "" {
-				ambiguous = append(
-					ambiguous,
-					path.Join(
-						entry.Destination.Namespace,
-						entry.Destination.Name))
+		case api.MultusNetwork, api.SriovNetwork, api.OvnNetwork:
+			if entry.Destination.Namespace == "" || entry.Destination.Name == "" {
 				continue
 			}
 			id := path.Join(
 				entry.Destination.Namespace,
 				entry.Destination.Name)
-			_, pErr := inventory.Network(&refapi.Ref{Name: id})
+			object, pErr := inventory.Network(&refapi.Ref{Name: id})
 			if pErr != nil {
 				if errors.As(pErr, &web.NotFoundError{}) {
-					notFound = append(
-						notFound,
-						path.Join(
-							entry.Destination.Namespace,
-							entry.Destination.Name))
+					if !generated(mp, id) {
+						notFound = append(notFound, id)
+					}
 				} else {
 					err = pErr
 					return
 				}
+				continue
+			}
+			if nad, cast := object.(*ocp.NetworkAttachmentDefinition); cast {
+				if !Compatible(entry.Destination.Type, nad) {
+					notCompatible = append(notCompatible, id)
+				}
 			}
 		}
 	}
@@ -188,16 +275,32 @@ next:
 			Items:    notFound,
 		})
 	}
-	if len(ambiguous) > 0 {
+	if len(notCompatible) > 0 {
 		mp.Status.SetCondition(libcnd.Condition{
 			Type:     DestinationNetworkNotValid,
 			Status:   True,
-			Reason:   Ambiguous,
+			Reason:   NotCompatible,
 			Category: Critical,
-			Message:  "Destination network (NAD) namespace required.",
-			Items:    ambiguous,
+			Message:  "Destination network (NAD) not compatible with the network type.",
+			Items:    notCompatible,
 		})
 	}
 
 	return
 }
+
+// Determine whether the NAD is compatible with the network type.
+// SR-IOV networks must be backed by the SR-IOV plugin and OVN
+// networks by the OVN-Kubernetes plugin with a supported topology.
+func Compatible(kind string, nad *ocp.NetworkAttachmentDefinition) bool {
+	if kind != api.SriovNetwork && kind != api.OvnNetwork {
+		return true
+	}
+	for _, plugin := range api.NadPlugins(nad.Object.Spec.Config) {
+		if plugin.NetworkType() == kind {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"testing"
+
+	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	"github.com/onsi/gomega"
+)
+
+func TestValidateRefs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	r := Reconciler{}
+
+	// Valid.
+	mp := &api.NetworkMap{}
+	mp.Spec.Map = []api.NetworkPair{
+		{
+			Source:      ref.Ref{ID: "net-1"},
+			Destination: api.DestinationNetwork{Type: api.PodNetwork},
+		},
+		{
+			Source:      ref.Ref{ID: "net-2"},
+			Destination: api.DestinationNetwork{Type: api.MultusNetwork, Namespace: "ns", Name: "nad"},
+		},
+	}
+	r.validateRefs(mp)
+	g.Expect(mp.Status.HasBlockerCondition()).To(gomega.BeFalse())
+
+	// Duplicate source.
+	mp.Spec.Map[1].Source.ID = "net-1"
+	r.validateRefs(mp)
+	cnd := mp.Status.FindCondition(SourceNetworkNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Reason).To(gomega.Equal(NotUnique))
+
+	// Multus without namespace.
+	mp = &api.NetworkMap{}
+	mp.Spec.Map = []api.NetworkPair{
+		{
+			Source:      ref.Ref{Name: "VM Network"},
+			Destination: api.DestinationNetwork{Type: api.MultusNetwork, Name: "nad"},
+		},
+	}
+	r.validateRefs(mp)
+	cnd = mp.Status.FindCondition(DestinationNetworkNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Reason).To(gomega.Equal(Ambiguous))
+
+	// SR-IOV without name.
+	mp = &api.NetworkMap{}
+	mp.Spec.Map = []api.NetworkPair{
+		{
+			Source:      ref.Ref{Name: "VM Network"},
+			Destination: api.DestinationNetwork{Type: api.SriovNetwork, Namespace: "ns"},
+		},
+		{
+			Source:      ref.Ref{Name: "Management"},
+			Destination: api.DestinationNetwork{Type: api.IgnoredNetwork},
+		},
+	}
+	r.validateRefs(mp)
+	cnd = mp.Status.FindCondition(DestinationNetworkNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Reason).To(gomega.Equal(NotSet))
+	g.Expect(cnd.Items).To(gomega.HaveLen(1))
+}
+
+func TestCompatible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	nad := func(config string) *ocp.NetworkAttachmentDefinition {
+		return &ocp.NetworkAttachmentDefinition{
+			Object: net.NetworkAttachmentDefinition{
+				Spec: net.NetworkAttachmentDefinitionSpec{Config: config},
+			},
+		}
+	}
+	bridge := nad(`{"cniVersion":"0.3.1","type":"cnv-bridge","bridge":"br1"}`)
+	sriov := nad(`{"cniVersion":"0.3.1","plugins":[{"type":"sriov","vlan":10}]}`)
+	localnet := nad(`{"cniVersion":"0.3.1","type":"ovn-k8s-cni-overlay","topology":"localnet"}`)
+	layer3 := nad(`{"cniVersion":"0.3.1","type":"ovn-k8s-cni-overlay","topology":"layer3"}`)
+	g.Expect(Compatible(api.MultusNetwork, bridge)).To(gomega.BeTrue())
+	g.Expect(Compatible(api.MultusNetwork, sriov)).To(gomega.BeTrue())
+	g.Expect(Compatible(api.SriovNetwork, sriov)).To(gomega.BeTrue())
+	g.Expect(Compatible(api.SriovNetwork, bridge)).To(gomega.BeFalse())
+	g.Expect(Compatible(api.OvnNetwork, localnet)).To(gomega.BeTrue())
+	g.Expect(Compatible(api.OvnNetwork, layer3)).To(gomega.BeFalse())
+	g.Expect(Compatible(api.OvnNetwork, nad(""))).To(gomega.BeFalse())
+}
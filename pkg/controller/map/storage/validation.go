@@ -7,6 +7,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Types
@@ -28,6 +29,7 @@ const (
 const (
 	NotSet    = "NotSet"
 	NotFound  = "NotFound"
+	NotUnique = "NotUnique"
 	Ambiguous = "Ambiguous"
 )
 
@@ -54,6 +56,7 @@ func (r *Reconciler) validate(mp *api.StorageMap) error {
 	}
 	mp.Referenced.Provider.Source = pv.Referenced.Source
 	mp.Referenced.Provider.Destination = pv.Referenced.Destination
+	r.validateRefs(mp)
 	err = r.validateSource(mp)
 	if err != nil {
 		return err
@@ -66,6 +69,73 @@ func (r *Reconciler) validate(mp *api.StorageMap) error {
 	return nil
 }
 
+// Validate the map using only the cluster API.
+// The provider inventory is not consulted which permits
+// the validation to be performed by the admission webhook.
+func Validate(client client.Client, mp *api.StorageMap) (err error) {
+	pv := validation.ProviderPair{Client: client}
+	conditions, err := pv.Validate(mp.Spec.Provider)
+	if err != nil {
+		return
+	}
+	mp.Status.UpdateConditions(conditions)
+	r := Reconciler{}
+	r.validateRefs(mp)
+	return
+}
+
+// Validate the refs (syntax) of the map entries.
+func (r *Reconciler) validateRefs(mp *api.StorageMap) {
+	notSet := []string{}
+	notUnique := []string{}
+	setOf := map[string]bool{}
+	for _, entry := range mp.Spec.Map {
+		ref := entry.Source
+		if ref.NotSet() {
+			mp.Status.SetCondition(libcnd.Condition{
+				Type:     SourceStorageNotValid,
+				Status:   True,
+				Reason:   NotSet,
+				Category: Critical,
+				Message:  "Source storage: either `ID` or `Name` required.",
+			})
+		} else {
+			key := ref.ID
+			if key == "" {
+				key = ref.Name
+			}
+			if _, found := setOf[key]; found {
+				notUnique = append(notUnique, ref.String())
+			} else {
+				setOf[key] = true
+			}
+		}
+		if entry.Destination.StorageClass == "" {
+			notSet = append(notSet, ref.String())
+		}
+	}
+	if len(notUnique) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     SourceStorageNotValid,
+			Status:   True,
+			Reason:   NotUnique,
+			Category: Critical,
+			Message:  "Source storage mapped more than once.",
+			Items:    notUnique,
+		})
+	}
+	if len(notSet) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     DestinationStorageNotValid,
+			Status:   True,
+			Reason:   NotSet,
+			Category: Critical,
+			Message:  "Destination storage class required.",
+			Items:    notSet,
+		})
+	}
+}
+
 // Validate source refs.
 func (r *Reconciler) validateSource(mp *api.StorageMap) (err error) {
 	provider := mp.Referenced.Provider.Source
@@ -80,13 +150,6 @@ func (r *Reconciler) validateSource(mp *api.StorageMap) (err error) {
 	for i := range list {
 		ref := &list[i].Source
 		if ref.NotSet() {
-			mp.Status.SetCondition(libcnd.Condition{
-				Type:     SourceStorageNotValid,
-				Status:   True,
-				Reason:   NotSet,
-				Category: Critical,
-				Message:  "Source storage: either `ID` or `Name` required.",
-			})
 			continue
 		}
 		_, pErr := inventory.Storage(ref)
@@ -140,6 +203,9 @@ func (r *Reconciler) validateDestination(mp *api.StorageMap) (err error) {
 	list := mp.Spec.Map
 	for _, entry := range list {
 		name := entry.Destination.StorageClass
+		if name == "" {
+			continue
+		}
 		_, pErr := inventory.Storage(&refapi.Ref{Name: name})
 		if pErr != nil {
 			if errors.As(pErr, &web.NotFoundError{}) {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
 	plancnt "github.com/konveyor/forklift-controller/pkg/controller/plan"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
@@ -15,15 +17,17 @@ import (
 
 // Types
 const (
-	PlanNotValid = "PlanNotValid"
-	PlanNotReady = "PlanNotReady"
-	VMNotFound   = "VMNotFound"
-	VMNotUnique  = "VMNotUnique"
-	Running      = "Running"
-	Executing    = plancnt.Executing
-	Succeeded    = plancnt.Succeeded
-	Failed       = plancnt.Failed
-	Canceled     = plancnt.Canceled
+	PlanNotValid    = "PlanNotValid"
+	PlanNotReady    = "PlanNotReady"
+	CutoverNotValid = "CutoverNotValid"
+	VMNotFound      = "VMNotFound"
+	VMNotUnique     = "VMNotUnique"
+	VMNotPlanned    = "VMNotPlanned"
+	Running         = "Running"
+	Executing       = plancnt.Executing
+	Succeeded       = plancnt.Succeeded
+	Failed          = plancnt.Failed
+	Canceled        = plancnt.Canceled
 )
 
 // Categories
@@ -37,9 +41,11 @@ const (
 
 // Reasons
 const (
-	NotSet    = "NotSet"
-	NotFound  = "NotFound"
-	Ambiguous = "Ambiguous"
+	NotSet       = "NotSet"
+	NotFound     = "NotFound"
+	NotSupported = "NotSupported"
+	Ambiguous    = "Ambiguous"
+	Archived     = "Archived"
 )
 
 // Statuses
@@ -50,6 +56,42 @@ const (
 
 // Validate the migration resource.
 func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err error) {
+	plan, err = r.validatePlan(migration)
+	if err != nil || plan == nil {
+		return
+	}
+	if !plan.Status.HasCondition(libcnd.Ready) {
+		migration.Status.SetCondition(
+			libcnd.Condition{
+				Type:     PlanNotReady,
+				Status:   True,
+				Reason:   NotFound,
+				Category: Critical,
+				Message:  "The `plan` does not have Ready condition.",
+			})
+		return
+	}
+	err = r.validateRefs(migration, plan)
+	return
+}
+
+// Validate the migration using only the cluster API.
+// The provider inventory is not consulted which permits
+// the validation to be performed by the admission webhook.
+func Validate(client client.Client, migration *api.Migration) (err error) {
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: client,
+			Log:    log,
+		},
+	}
+	_, err = r.validatePlan(migration)
+	return
+}
+
+// Validate the referenced plan.
+// Returns: nil when not-found.
+func (r *Reconciler) validatePlan(migration *api.Migration) (plan *api.Plan, err error) {
 	newCnd := libcnd.Condition{
 		Type:     PlanNotValid,
 		Status:   True,
@@ -69,28 +111,39 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 	}
 	err = r.Get(context.TODO(), key, plan)
 	if k8serr.IsNotFound(err) {
+		plan = nil
 		err = nil
 		newCnd.Reason = NotFound
 		migration.Status.SetCondition(newCnd)
 		return
 	}
 	if err != nil {
+		plan = nil
 		err = liberr.Wrap(err)
 		return
 	}
-	if !plan.Status.HasCondition(libcnd.Ready) {
+	if plan.Spec.Archived {
+		newCnd.Reason = Archived
+		newCnd.Message = "The `plan` is archived."
+		migration.Status.SetCondition(newCnd)
+		return
+	}
+	if (migration.Spec.Cutover != nil || len(migration.Spec.Cutovers) > 0) && !plan.Spec.Warm {
 		migration.Status.SetCondition(
 			libcnd.Condition{
-				Type:     PlanNotReady,
+				Type:     CutoverNotValid,
 				Status:   True,
-				Reason:   NotFound,
+				Reason:   NotSupported,
 				Category: Critical,
-				Message:  "The `plan` does not have Ready condition.",
+				Message:  "The `cutover` is only supported by warm migration.",
 			})
-		return
 	}
 
-	// Validate the refs in the Cancel array
+	return
+}
+
+// Validate the refs in the Cancel and Cutovers arrays.
+func (r *Reconciler) validateRefs(migration *api.Migration, plan *api.Plan) (err error) {
 	notFound := libcnd.Condition{
 		Type:     VMNotFound,
 		Status:   True,
@@ -107,9 +160,17 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 		Message:  "VM reference is ambiguous.",
 		Items:    []string{},
 	}
+	notPlanned := libcnd.Condition{
+		Type:     VMNotPlanned,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Warn,
+		Message:  "VM is not listed or selected by the plan.",
+		Items:    []string{},
+	}
 	source := plan.Spec.Provider.Source
 	provider := &api.Provider{}
-	key = client.ObjectKey{
+	key := client.ObjectKey{
 		Namespace: source.Namespace,
 		Name:      source.Name,
 	}
@@ -123,7 +184,11 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 	if err != nil {
 		return
 	}
-	for _, ref := range migration.Spec.Cancel {
+	refs := append([]ref.Ref{}, migration.Spec.Cancel...)
+	for _, cutover := range migration.Spec.Cutovers {
+		refs = append(refs, cutover.Ref)
+	}
+	for _, ref := range refs {
 		_, err = inventory.VM(&ref)
 		if err != nil {
 			if errors.As(err, &web.NotFoundError{}) {
@@ -138,14 +203,31 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 			}
 			return
 		}
+		if !r.planned(plan, ref) {
+			notPlanned.Items = append(notPlanned.Items, ref.String())
+		}
 	}
 
 	if len(notFound.Items) > 0 {
 		migration.Status.SetCondition(notFound)
 	}
+	if len(notPlanned.Items) > 0 {
+		migration.Status.SetCondition(notPlanned)
+	}
 	if len(ambiguous.Items) > 0 {
 		migration.Status.SetCondition(ambiguous)
 	}
 
 	return
 }
+
+// Determine whether the VM is listed or selected by the plan.
+func (r *Reconciler) planned(plan *api.Plan, ref ref.Ref) bool {
+	for _, vm := range plan.VMs() {
+		if vm.Ref.Same(ref) {
+			return true
+		}
+	}
+
+	return false
+}
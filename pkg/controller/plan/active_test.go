@@ -0,0 +1,43 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+)
+
+func TestValidateActivePlans(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	newPlan := func(namespace, name string) *api.Plan {
+		p := &api.Plan{}
+		p.Namespace = namespace
+		p.Name = name
+		p.Spec.Provider.Source = core.ObjectReference{Namespace: "openshift-mtv", Name: "vsphere"}
+		p.Spec.VMs = []plan.VM{{Ref: ref.Ref{ID: "vm-1"}}}
+		return p
+	}
+	// Executing in another namespace.
+	other := newPlan("other", "other")
+	other.Status.SetCondition(libcnd.Condition{Type: Executing, Status: True})
+	// Executing with another provider.
+	unrelated := newPlan("test", "unrelated")
+	unrelated.Spec.Provider.Source.Name = "ovirt"
+	unrelated.Status.SetCondition(libcnd.Condition{Type: Executing, Status: True})
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fakeClient(other, unrelated),
+		},
+	}
+	p := newPlan("test", "plan")
+	g.Expect(r.validateActivePlans(p)).To(gomega.Succeed())
+	cnd := p.Status.FindCondition(VMInActivePlan)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Items).To(gomega.HaveLen(1))
+	g.Expect(cnd.Items[0]).To(gomega.HaveSuffix("plan: other/other"))
+}
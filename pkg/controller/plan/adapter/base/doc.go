@@ -1,15 +1,23 @@
 package base
 
 import (
+	"fmt"
+
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/base"
 	core "k8s.io/api/core/v1"
 	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
+// Concern categories.
+const (
+	Critical = "Critical"
+)
+
 // Annotations
 const (
 	// Used on DataVolume, contains disk source -- e.g. backing file in
@@ -45,6 +53,14 @@ type Builder interface {
 	Tasks(vmRef ref.Ref) ([]*planapi.Task, error)
 	// Build template labels.
 	TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error)
+	// Build the labels and annotations mapped from the source VM metadata.
+	Metadata(vmRef ref.Ref) (labels, annotations map[string]string, err error)
+	// Build the firmware state to be persisted on the target VM.
+	PersistentState(vmRef ref.Ref) (state PersistentState, err error)
+	// Build the disks shared between VMs in the plan.
+	SharedDisks() (disks SharedDisks, err error)
+	// Build the LUNs (disk source) mapped to persistent volumes.
+	MappedLUNs(vmRef ref.Ref) (volumes map[string]string, err error)
 	// Return a stable identifier for a DataVolume.
 	ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string
 	// Return a stable identifier for a PersistentDataVolume
@@ -77,6 +93,13 @@ type Client interface {
 	CheckSnapshotReady(vmRef ref.Ref, snapshot string) (bool, error)
 	// Set DataVolume checkpoints.
 	SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool) (err error)
+	// Measure the data (MB) changed between the previous and the latest precopy.
+	PrecopyDelta(vmRef ref.Ref, precopies []planapi.Precopy) (delta int64, err error)
+	// Perform a decommission action on the source VM.
+	// Returns the provider state needed to undo the action.
+	Decommission(vmRef ref.Ref, action planapi.DecommissionAction) (undo string, err error)
+	// Undo a decommission action performed on the source VM.
+	UndoDecommission(vmRef ref.Ref, action planapi.DecommissionAction) error
 	// Close connections to the provider API.
 	Close()
 	// Finalize migrations
@@ -92,8 +115,106 @@ type Validator interface {
 	NetworksMapped(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's Host isn't in maintenance mode.
 	MaintenanceMode(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's RDM (direct LUN) disks are copied or mapped to persistent volumes.
+	LUNs(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's disks are not encrypted by the provider.
+	EncryptedDisks(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's preserved CD-ROMs have a mapped ISO.
+	CDROMs(vmRef ref.Ref) (bool, error)
 	// Validate whether warm migration is supported from this provider type.
 	WarmMigration() bool
 	// Validate that no more than one of a VM's networks is mapped to the pod network.
 	PodNetwork(vmRef ref.Ref) (bool, error)
+	// Select the VMs matching the selector.
+	SelectVMs(selector *planapi.VMSelector) ([]ref.Ref, error)
+	// Return the destination resources requested by a VM.
+	Requests(vmRef ref.Ref) (Requests, error)
+	// Return the concerns reported for a VM.
+	Concerns(vmRef ref.Ref) ([]planapi.Concern, error)
+	// Validate that a VM is within the inventory scope.
+	Scoped(vmRef ref.Ref, scope *api.ProviderScope) (bool, error)
+	// Return the firmware state to be persisted for a VM.
+	PersistentState(vmRef ref.Ref) (PersistentState, error)
+}
+
+// Destination resources requested by a VM.
+type Requests struct {
+	// Source host.
+	Host string
+	// Disks.
+	Disks []DiskRequest
+	// CPU (cores).
+	CPU int64
+	// Memory (bytes).
+	Memory int64
+}
+
+// Destination disk requested by a VM.
+type DiskRequest struct {
+	// Source storage (datastore, storage domain, volume type) ID.
+	Storage string
+	// Storage class.
+	StorageClass string
+	// Size (bytes).
+	Size int64
+}
+
+// Firmware state persisted by KubeVirt on the target VM.
+// Not modeled by the KubeVirt API (version) used to build
+// the VM spec. Requires the VMPersistentState feature gate.
+type PersistentState struct {
+	// Persistent EFI variable store (NVRAM).
+	EFI bool
+	// Persistent vTPM.
+	TPM bool
+}
+
+// Selector criteria not supported by the provider.
+type SelectorNotSupportedError struct {
+	Criteria string
+}
+
+func (e SelectorNotSupportedError) Error() string {
+	return fmt.Sprintf("VM selector `%s` not supported by the provider.", e.Criteria)
+}
+
+// Decommission action not supported by the provider.
+type DecommissionNotSupportedError struct {
+	Action string
+}
+
+func (e DecommissionNotSupportedError) Error() string {
+	return fmt.Sprintf("Decommission action `%s` not supported by the provider.", e.Action)
+}
+
+// Precopy delta not measured by the provider.
+type PrecopyDeltaNotSupportedError struct {
+}
+
+func (e PrecopyDeltaNotSupportedError) Error() string {
+	return "Precopy delta not measured by the provider."
+}
+
+// Convert inventory concerns.
+func Concerns(concerns []model.Concern) (list []planapi.Concern) {
+	for _, concern := range concerns {
+		list = append(
+			list,
+			planapi.Concern{
+				Label:      concern.Label,
+				Category:   concern.Category,
+				Assessment: concern.Assessment,
+			})
+	}
+	return
+}
+
+// Determine whether any of the concerns is critical.
+func HasCriticalConcern(concerns []model.Concern) bool {
+	for _, concern := range concerns {
+		if concern.Category == Critical {
+			return true
+		}
+	}
+	return false
 }
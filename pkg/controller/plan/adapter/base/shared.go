@@ -0,0 +1,153 @@
+package base
+
+import (
+	"strings"
+
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+)
+
+// Annotations
+const (
+	// Used on shared DataVolumes (PVCs), contains the comma separated
+	// IDs of the VMs the disk is attached to.
+	AnnSharedDisk = "forklift.konveyor.io/shared-with"
+)
+
+// Pipeline step in which the owner migrates the shared disks.
+const DiskTransfer = "DiskTransfer"
+
+// Disks shared between VMs in the plan.
+// Maps the disk identifier to the IDs of the VMs the
+// disk is attached to, in plan order. A shared disk is
+// migrated once, by the first (owner) VM, and attached
+// to each of the VMs.
+type SharedDisks map[string][]string
+
+// Add a shared disk attached to a VM.
+func (r SharedDisks) Add(diskID, vmID string) {
+	for _, id := range r[diskID] {
+		if id == vmID {
+			return
+		}
+	}
+	r[diskID] = append(r[diskID], vmID)
+}
+
+// Prune disks attached to a single VM in the plan.
+// These are migrated as regular disks.
+func (r SharedDisks) Prune() {
+	for diskID, vms := range r {
+		if len(vms) < 2 {
+			delete(r, diskID)
+		}
+	}
+}
+
+// The disk is shared.
+func (r SharedDisks) Shared(diskID string) bool {
+	_, found := r[diskID]
+	return found
+}
+
+// The VM owns (migrates) the shared disk.
+func (r SharedDisks) Owner(diskID, vmID string) bool {
+	vms := r[diskID]
+	return len(vms) > 0 && vms[0] == vmID
+}
+
+// Owners of the shared disks attached to the VM,
+// excluding the VM.
+func (r SharedDisks) Owners(vmID string) (owners []string) {
+	for _, vms := range r {
+		if vms[0] == vmID || !contains(vms, vmID) || contains(owners, vms[0]) {
+			continue
+		}
+		owners = append(owners, vms[0])
+	}
+	return
+}
+
+// VMs sharing disks with the VM.
+func (r SharedDisks) Peers(vmID string) (peers []string) {
+	for _, vms := range r {
+		if !contains(vms, vmID) {
+			continue
+		}
+		for _, id := range vms {
+			if id != vmID && !contains(peers, id) {
+				peers = append(peers, id)
+			}
+		}
+	}
+	return
+}
+
+// The VM is waiting for the owners of the shared disks
+// attached to it to migrate the disks. The shared disks
+// are migrated by the owners in the DiskTransfer step.
+func (r SharedDisks) Waiting(vmID string, vms []*planapi.VMStatus) bool {
+	for _, owner := range r.Owners(vmID) {
+		for _, vm := range vms {
+			if vm.ID != owner || vm.MarkedCompleted() {
+				continue
+			}
+			step, found := vm.FindStep(DiskTransfer)
+			if found && !step.MarkedCompleted() {
+				return true
+			}
+			if !found && !vm.MarkedStarted() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Find a candidate VM sharing disks with a running VM.
+// The candidates are the pending VMs that may be started
+// within the provider capacity. VMs sharing disks are
+// preferred so that they are migrated together.
+func (r SharedDisks) NextPeer(vms []*planapi.VMStatus, candidates []*planapi.VMStatus) (next *planapi.VMStatus, found bool) {
+	running := map[string]bool{}
+	for _, vm := range vms {
+		if vm.Running() {
+			running[vm.ID] = true
+		}
+	}
+	for _, vm := range candidates {
+		if vm.MarkedStarted() || vm.MarkedCompleted() {
+			continue
+		}
+		for _, peer := range r.Peers(vm.ID) {
+			if running[peer] && !r.Waiting(vm.ID, vms) {
+				next = vm
+				found = true
+				return
+			}
+		}
+	}
+	return
+}
+
+// Annotation value for the shared disk.
+func (r SharedDisks) Annotation(diskID string) string {
+	return strings.Join(r[diskID], ",")
+}
+
+// The annotations mark a shared disk attached to the VM.
+func SharedWith(annotations map[string]string, vmID string) bool {
+	value, found := annotations[AnnSharedDisk]
+	if !found {
+		return false
+	}
+	return contains(strings.Split(value, ","), vmID)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package base
+
+import (
+	"testing"
+
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+)
+
+// Build a VM status with a DiskTransfer step.
+func vmStatus(id string) *planapi.VMStatus {
+	vm := &planapi.VMStatus{}
+	vm.ID = id
+	vm.Pipeline = []*planapi.Step{
+		{Task: planapi.Task{Name: DiskTransfer}},
+	}
+	return vm
+}
+
+func TestSharedDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disks := SharedDisks{}
+	disks.Add("d1", "vm1")
+	disks.Add("d1", "vm2")
+	disks.Add("d1", "vm2")
+	disks.Add("d2", "vm2")
+	disks.Add("d2", "vm3")
+	disks.Add("d3", "vm1")
+	disks.Prune()
+	g.Expect(disks.Shared("d1")).To(gomega.BeTrue())
+	g.Expect(disks.Shared("d3")).To(gomega.BeFalse())
+	g.Expect(disks["d1"]).To(gomega.Equal([]string{"vm1", "vm2"}))
+	g.Expect(disks.Owner("d1", "vm1")).To(gomega.BeTrue())
+	g.Expect(disks.Owner("d1", "vm2")).To(gomega.BeFalse())
+	g.Expect(disks.Owners("vm1")).To(gomega.BeEmpty())
+	g.Expect(disks.Owners("vm2")).To(gomega.Equal([]string{"vm1"}))
+	g.Expect(disks.Owners("vm3")).To(gomega.Equal([]string{"vm2"}))
+	g.Expect(disks.Peers("vm2")).To(gomega.ConsistOf("vm1", "vm3"))
+	g.Expect(disks.Annotation("d2")).To(gomega.Equal("vm2,vm3"))
+	g.Expect(SharedWith(map[string]string{AnnSharedDisk: "vm1,vm2"}, "vm2")).To(gomega.BeTrue())
+	g.Expect(SharedWith(map[string]string{AnnSharedDisk: "vm1,vm2"}, "vm3")).To(gomega.BeFalse())
+}
+
+func TestWaiting(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disks := SharedDisks{"d1": {"vm1", "vm2"}}
+	owner := vmStatus("vm1")
+	peer := vmStatus("vm2")
+	vms := []*planapi.VMStatus{owner, peer}
+
+	// Owner not started.
+	g.Expect(disks.Waiting("vm2", vms)).To(gomega.BeTrue())
+	g.Expect(disks.Waiting("vm1", vms)).To(gomega.BeFalse())
+	// Owner transferring the disks.
+	owner.MarkStarted()
+	owner.Pipeline[0].MarkStarted()
+	g.Expect(disks.Waiting("vm2", vms)).To(gomega.BeTrue())
+	// Owner transferred the disks.
+	owner.Pipeline[0].MarkCompleted()
+	g.Expect(disks.Waiting("vm2", vms)).To(gomega.BeFalse())
+	// Owner completed (failed) before the transfer.
+	owner.Pipeline[0].Completed = nil
+	owner.MarkCompleted()
+	g.Expect(disks.Waiting("vm2", vms)).To(gomega.BeFalse())
+}
+
+func TestNextPeer(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disks := SharedDisks{"d1": {"vm1", "vm2"}}
+	owner := vmStatus("vm1")
+	peer := vmStatus("vm2")
+	other := vmStatus("vm3")
+	vms := []*planapi.VMStatus{owner, peer, other}
+
+	// Owner not running.
+	_, found := disks.NextPeer(vms, vms)
+	g.Expect(found).To(gomega.BeFalse())
+	// Owner running, transferring the disks.
+	owner.MarkStarted()
+	_, found = disks.NextPeer(vms, vms)
+	g.Expect(found).To(gomega.BeFalse())
+	// Owner running, disks transferred.
+	owner.Pipeline[0].MarkStarted()
+	owner.Pipeline[0].MarkCompleted()
+	next, found := disks.NextPeer(vms, vms)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(next).To(gomega.BeIdenticalTo(peer))
+	// Peer not a candidate (no capacity).
+	_, found = disks.NextPeer(vms, []*planapi.VMStatus{other})
+	g.Expect(found).To(gomega.BeFalse())
+}
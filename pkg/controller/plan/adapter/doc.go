@@ -13,6 +13,11 @@ type Adapter = base.Adapter
 type Builder = base.Builder
 type Client = base.Client
 type Validator = base.Validator
+type SelectorNotSupportedError = base.SelectorNotSupportedError
+type DecommissionNotSupportedError = base.DecommissionNotSupportedError
+type PrecopyDeltaNotSupportedError = base.PrecopyDeltaNotSupportedError
+type Requests = base.Requests
+type DiskRequest = base.DiskRequest
 
 // Adapter factory.
 func New(provider *api.Provider) (adapter Adapter, err error) {
@@ -217,6 +217,7 @@ const (
 	OsSecureBoot         = "os_secure_boot"
 	HwVideoRam           = "hw_video_ram"
 	HwRngModel           = "hw_rng_model"
+	HwTpmVersion         = "hw_tpm_version"
 	VifMultiQueueEnabled = "hw_vif_multiqueue_enabled"
 )
 
@@ -235,12 +236,7 @@ const (
 	FlavorVifMultiQueueEnabled = "hw:vif_multiqueue_enabled"
 	FlavorHwRng                = "hw_rng:allowed"
 	FlavorHwVideoRam           = "hw_video:ram_max_mb"
-)
-
-// Network types
-const (
-	Pod    = "pod"
-	Multus = "multus"
+	FlavorTpmVersion           = "hw:tpm_version"
 )
 
 // Default properties
@@ -364,39 +360,62 @@ func (r *Builder) mapHardwareRng(vm *model.Workload, object *cnv.VirtualMachineS
 }
 
 func (r *Builder) mapFirmware(vm *model.Workload, object *cnv.VirtualMachineSpec) {
-	var firmwareType string
 	var bootloader *cnv.Bootloader
-	if imageFirmwareType, ok := vm.Image.Properties[FirmwareType]; ok {
-		firmwareType = imageFirmwareType.(string)
-	} else {
-		for _, volume := range vm.Volumes {
-			if volume.Bootable == "true" {
-				if volumeFirmwareType, ok := volume.VolumeImageMetadata[FirmwareType]; ok {
-					firmwareType = volumeFirmwareType
-				}
-			}
-		}
-	}
-	switch firmwareType {
+	features := &cnv.Features{}
+	switch firmwareType(vm) {
 	case EFI:
-		// We disable secure boot even if it was enabled on the source because the guest OS won't
-		// be able to boot without getting the NVRAM data. So we start the VM without secure boot
-		// to ease the procedure users need to do in order to make the guest OS to boot.
-		secureBootEnabled := false
+		// Secure boot is enabled when required on the source. The NVRAM is not
+		// transferred so the guest boots with the default (enrolled) keys.
+		secureBootEnabled := secureBoot(vm)
 		bootloader = &cnv.Bootloader{
 			EFI: &cnv.EFI{
 				SecureBoot: &secureBootEnabled,
 			}}
+		if secureBootEnabled {
+			features.SMM = &cnv.FeatureState{Enabled: &secureBootEnabled}
+		}
 	default:
 		bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
 	}
-	features := &cnv.Features{}
 	firmware := &cnv.Firmware{}
 	firmware.Bootloader = bootloader
 	object.Template.Spec.Domain.Features = features
 	object.Template.Spec.Domain.Firmware = firmware
 }
 
+// Determine the firmware type from the image or the bootable volume.
+func firmwareType(vm *model.Workload) (firmwareType string) {
+	if imageFirmwareType, ok := vm.Image.Properties[FirmwareType]; ok {
+		firmwareType = imageFirmwareType.(string)
+	} else {
+		for _, volume := range vm.Volumes {
+			if volume.Bootable == "true" {
+				if volumeFirmwareType, ok := volume.VolumeImageMetadata[FirmwareType]; ok {
+					firmwareType = volumeFirmwareType
+				}
+			}
+		}
+	}
+	return
+}
+
+// Determine whether secure boot is required by the image or flavor.
+func secureBoot(vm *model.Workload) bool {
+	if imageSecureBoot, ok := vm.Image.Properties[OsSecureBoot]; ok && imageSecureBoot == SecureBootRequired {
+		return true
+	}
+	return vm.Flavor.ExtraSpecs[FlavorSecureBoot] == SecureBootRequired
+}
+
+// Determine whether a vTPM is requested by the image or flavor.
+func tpm(vm *model.Workload) bool {
+	if _, ok := vm.Image.Properties[HwTpmVersion]; ok {
+		return true
+	}
+	_, ok := vm.Flavor.ExtraSpecs[FlavorTpmVersion]
+	return ok
+}
+
 func (r *Builder) mapVideo(vm *model.Workload, object *cnv.VirtualMachineSpec) {
 	videoModel := DefaultProperties[VideoModel]
 	if imageVideoModel, ok := vm.Image.Properties[VideoModel]; ok {
@@ -637,16 +656,26 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 					return
 				}
 				switch networkPair.Destination.Type {
-				case Pod:
+				case v1beta1.IgnoredNetwork:
+					continue
+				case v1beta1.PodNetwork:
 					kNetwork.Pod = &cnv.PodNetwork{}
 					kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-				case Multus:
+				case v1beta1.MultusNetwork, v1beta1.OvnNetwork:
 					kNetwork.Multus = &cnv.MultusNetwork{
 						NetworkName: path.Join(
 							networkPair.Destination.Namespace,
 							networkPair.Destination.Name),
 					}
 					kInterface.Bridge = &cnv.InterfaceBridge{}
+				case v1beta1.SriovNetwork:
+					kNetwork.Multus = &cnv.MultusNetwork{
+						NetworkName: path.Join(
+							networkPair.Destination.Namespace,
+							networkPair.Destination.Name),
+					}
+					kInterface.Model = ""
+					kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 				}
 				kNetworks = append(kNetworks, kNetwork)
 				kInterfaces = append(kInterfaces, kInterface)
@@ -816,6 +845,12 @@ func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err e
 	return
 }
 
+// Build the labels and annotations mapped from the source VM metadata.
+// Metadata mapping is not supported.
+func (r *Builder) Metadata(vmRef ref.Ref) (labels, annotations map[string]string, err error) {
+	return
+}
+
 // Return a stable identifier for a DataVolume.
 func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
 	return ""
@@ -844,9 +879,7 @@ func (r *Builder) PersistentVolumeClaimWithSourceRef(da interface{}, storageName
 	if virtualSize == 0 {
 		virtualSize = image.SizeBytes
 	}
-	if *volumeMode == core.PersistentVolumeFilesystem {
-		virtualSize = int64(float64(virtualSize) * 1.1)
-	}
+	virtualSize = volumeSize(virtualSize, *volumeMode)
 	return &core.PersistentVolumeClaim{
 		ObjectMeta: meta.ObjectMeta{
 			Name:      image.ID,
@@ -873,6 +906,53 @@ func (r *Builder) PersistentVolumeClaimWithSourceRef(da interface{}, storageName
 	}
 }
 
+// Size of the destination PVC for a volume.
+// Filesystem volumes are padded for the filesystem overhead.
+func volumeSize(size int64, volumeMode core.PersistentVolumeMode) int64 {
+	if volumeMode == core.PersistentVolumeFilesystem {
+		size = int64(float64(size) * 1.1)
+	}
+	return size
+}
+
+// Build the firmware state to be persisted on the target VM.
+// The EFI variables are persisted for secure boot.
+func (r *Builder) PersistentState(vmRef ref.Ref) (state planbase.PersistentState, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
+
+// Build the firmware state to be persisted for the VM.
+func persistentState(vm *model.Workload) (state planbase.PersistentState) {
+	state.EFI = firmwareType(vm) == EFI && secureBoot(vm)
+	state.TPM = tpm(vm)
+	return
+}
+
+// Build the LUNs mapped to persistent volumes.
+// Not supported.
+func (r *Builder) MappedLUNs(vmRef ref.Ref) (volumes map[string]string, err error) {
+	volumes = map[string]string{}
+	return
+}
+
+// Build the disks shared between VMs in the plan.
+// Not supported, volumes are migrated for each VM.
+func (r *Builder) SharedDisks() (disks planbase.SharedDisks, err error) {
+	disks = planbase.SharedDisks{}
+	return
+}
+
 func (r *Builder) PreTransferActions(c planbase.Client, vmRef ref.Ref) (ready bool, err error) {
 	// TODO:
 	// 1. Dedup
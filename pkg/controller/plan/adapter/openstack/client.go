@@ -19,6 +19,7 @@ import (
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
 	resource "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
@@ -26,6 +27,9 @@ import (
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
+// Server metadata key containing the decommission annotation.
+const AnnotationKey = "forklift.konveyor.io/annotation"
+
 // Client
 type Client struct {
 	*plancontext.Context
@@ -276,6 +280,80 @@ func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, data
 	return nil
 }
 
+// Measure the data (MB) changed between the previous and the latest precopy.
+func (r *Client) PrecopyDelta(vmRef ref.Ref, precopies []planapi.Precopy) (delta int64, err error) {
+	err = base.PrecopyDeltaNotSupportedError{}
+	return
+}
+
+// Perform a decommission action on the source VM.
+func (r *Client) Decommission(vmRef ref.Ref, action planapi.DecommissionAction) (undo string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	switch action.Action {
+	case planapi.DecommissionRename:
+		undo = vm.Name
+		_, err = servers.Update(
+			r.computeService,
+			vm.ID,
+			servers.UpdateOpts{Name: vm.Name + action.Value}).Extract()
+	case planapi.DecommissionAnnotate:
+		undo = vm.Metadata[AnnotationKey]
+		value := action.Value
+		if undo != "" {
+			value = undo + "\n" + value
+		}
+		_, err = servers.CreateMetadatum(
+			r.computeService,
+			vm.ID,
+			servers.MetadatumOpts{AnnotationKey: value}).Extract()
+	case planapi.DecommissionDelete:
+		err = servers.Delete(r.computeService, vm.ID).ExtractErr()
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Undo a decommission action performed on the source VM.
+func (r *Client) UndoDecommission(vmRef ref.Ref, action planapi.DecommissionAction) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	switch action.Action {
+	case planapi.DecommissionRename:
+		_, err = servers.Update(
+			r.computeService,
+			vm.ID,
+			servers.UpdateOpts{Name: action.Undo}).Extract()
+	case planapi.DecommissionAnnotate:
+		if action.Undo == "" {
+			err = servers.DeleteMetadatum(r.computeService, vm.ID, AnnotationKey).ExtractErr()
+		} else {
+			_, err = servers.CreateMetadatum(
+				r.computeService,
+				vm.ID,
+				servers.MetadatumOpts{AnnotationKey: action.Undo}).Extract()
+		}
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
 // Close connections to the provider API.
 func (r *Client) Close() {
 }
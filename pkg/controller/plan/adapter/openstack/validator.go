@@ -2,10 +2,14 @@ package openstack
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	openstackutil "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
 )
 
 // Validator
@@ -43,6 +47,27 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's LUN disks are copied or mapped.
+// Not applicable, volumes are always copied.
+func (r *Validator) LUNs(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// Validate that a VM's disks are not encrypted by the provider.
+// No-op for OpenStack.
+func (r *Validator) EncryptedDisks(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// Validate that a VM's CD-ROMs have a mapped ISO.
+// CD-ROMs are not preserved for OpenStack.
+func (r *Validator) CDROMs(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
 // Validate that a VM's networks have been mapped.
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -101,7 +126,7 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		mapped := &mapping[i]
 		ref := mapped.Source
 		for _, network := range vm.Networks {
-			if ref.ID == network.ID && mapped.Destination.Type == "Pod" {
+			if ref.ID == network.ID && mapped.Destination.Type == api.PodNetwork {
 				podMapped++
 			}
 		}
@@ -110,3 +135,214 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 	ok = podMapped <= 1
 	return
 }
+
+// Select the VMs matching the selector.
+func (r *Validator) SelectVMs(selector *planapi.VMSelector) (refs []ref.Ref, err error) {
+	switch {
+	case selector.Folder != "":
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "folder"})
+		return
+	case selector.Cluster != "":
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "cluster"})
+		return
+	}
+	project := ""
+	if selector.Project != "" {
+		project, err = r.projectID(selector.Project)
+		if err != nil {
+			return
+		}
+	}
+	list := []model.VM{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+next:
+	for _, vm := range list {
+		if project != "" && vm.TenantID != project {
+			continue
+		}
+		for _, tag := range selector.Tags {
+			found := false
+			if vm.Tags != nil {
+				for _, t := range *vm.Tags {
+					if t == tag {
+						found = true
+						break
+					}
+				}
+			}
+			if !found {
+				continue next
+			}
+		}
+		for k, v := range selector.Labels {
+			if value, found := vm.Metadata[k]; !found || value != v {
+				continue next
+			}
+		}
+		if selector.ExcludeCritical && base.HasCriticalConcern(vm.Concerns) {
+			continue
+		}
+		matched, mErr := selector.MatchName(vm.Name)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		if matched {
+			refs = append(refs, ref.Ref{ID: vm.ID, Name: vm.Name})
+		}
+	}
+
+	return
+}
+
+// Find the ID of the project (name, path or ID).
+func (r *Validator) projectID(project string) (id string, err error) {
+	list := []model.Project{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, p := range list {
+		if p.ID == project || p.Name == project || p.Path == project {
+			id = p.ID
+			return
+		}
+	}
+	err = liberr.Wrap(web.NotFoundError{Ref: ref.Ref{Name: project}})
+	return
+}
+
+// Return the destination resources requested by a VM.
+// The volumes are sized as the builder sizes the PVCs.
+func (r *Validator) Requests(vmRef ref.Ref) (requests base.Requests, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	requests = base.Requests{
+		Host:   vm.HostID,
+		CPU:    int64(vm.Flavor.VCPUs),
+		Memory: int64(vm.Flavor.RAM) * 1024 * 1024,
+	}
+	if r.plan.Referenced.Map.Storage == nil || len(r.plan.Referenced.Map.Storage.Spec.Map) == 0 {
+		return
+	}
+	storageMap := r.plan.Referenced.Map.Storage.Spec.Map
+	for i := range vm.Volumes {
+		volume := &vm.Volumes[i]
+		mapped := openstackutil.OpenstackVolumeStorage(storageMap, vm, volume)
+		requests.Disks = append(
+			requests.Disks,
+			base.DiskRequest{
+				Storage:      volume.VolumeType,
+				StorageClass: mapped.Destination.StorageClass,
+				Size:         r.volumeSize(mapped, int64(volume.Size)*1024*1024*1024),
+			})
+	}
+	if len(vm.Volumes) == 0 && vm.Flavor.Disk > 0 {
+		mapped := &storageMap[0]
+		requests.Disks = append(
+			requests.Disks,
+			base.DiskRequest{
+				StorageClass: mapped.Destination.StorageClass,
+				Size:         r.volumeSize(mapped, int64(vm.Flavor.Disk)*1024*1024*1024),
+			})
+	}
+
+	return
+}
+
+// Size of the destination PVC as built.
+// The volume mode defaults to filesystem when not mapped.
+func (r *Validator) volumeSize(mapped *api.StoragePair, size int64) int64 {
+	volumeMode := core.PersistentVolumeFilesystem
+	if mapped.Destination.VolumeMode != "" {
+		volumeMode = mapped.Destination.VolumeMode
+	}
+	return volumeSize(size, volumeMode)
+}
+
+// Return the concerns reported for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planapi.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	concerns = base.Concerns(vm.Concerns)
+
+	return
+}
+
+// Validate that a VM is within the inventory scope.
+// Contained in a listed project.
+func (r *Validator) Scoped(vmRef ref.Ref, scope *api.ProviderScope) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	project := &model.Project{}
+	projectRef := ref.Ref{ID: vm.TenantID}
+	err = r.inventory.Find(project, projectRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Project not found in inventory.",
+			"vm",
+			vmRef.String(),
+			"project",
+			projectRef.String())
+		return
+	}
+	ok = scope.HasProject(project.ID, project.Name)
+
+	return
+}
+
+// Return the firmware state to be persisted for a VM.
+func (r *Validator) PersistentState(vmRef ref.Ref) (state base.PersistentState, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
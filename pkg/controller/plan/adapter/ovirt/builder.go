@@ -13,6 +13,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
@@ -45,10 +46,10 @@ const (
 	Tablet = "tablet"
 )
 
-// Network types
+// Boot devices
 const (
-	Pod    = "pod"
-	Multus = "multus"
+	BootHD    = "hd"
+	BootCDROM = "cdrom"
 )
 
 // Template labels
@@ -115,6 +116,8 @@ type Builder struct {
 	*plancontext.Context
 	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
 	macConflictsMap map[string]string
+	// Disks shared between VMs in the plan.
+	sharedDisks planbase.SharedDisks
 }
 
 // Get list of destination VMs with mac addresses that would
@@ -185,60 +188,143 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 		return
 	}
 	url := r.Source.Provider.Spec.URL
+	sharedDisks, err := r.SharedDisks()
+	if err != nil {
+		return
+	}
 
-	dsMapIn := r.Context.Map.Storage.Spec.Map
-	for i := range dsMapIn {
-		mapped := &dsMapIn[i]
-		ref := mapped.Source
+	disks, err := mappedDisks(r.Source.Inventory, r.Context.Map.Storage.Spec.Map, r.luns(), sharedDisks, vm)
+	if err != nil {
+		return
+	}
+	for _, md := range disks {
+		da := md.Attachment
+		storageClass := md.StorageClass
+		dvSpec := cdi.DataVolumeSpec{
+			Source: &cdi.DataVolumeSource{
+				Imageio: &cdi.DataVolumeSourceImageIO{
+					URL:           url,
+					DiskID:        da.Disk.ID,
+					SecretRef:     secret.Name,
+					CertConfigMap: configMap.Name,
+				},
+			},
+			Storage: &cdi.StorageSpec{
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: *resource.NewQuantity(md.Size, resource.BinarySI),
+					},
+				},
+				StorageClassName: &storageClass,
+			},
+		}
+		// set the access mode and volume mode if they were specified in the storage map.
+		// otherwise, let the storage profile decide the default values.
+		if md.Mapped != nil {
+			if md.Mapped.Destination.AccessMode != "" {
+				dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{md.Mapped.Destination.AccessMode}
+			}
+			if md.Mapped.Destination.VolumeMode != "" {
+				dvSpec.Storage.VolumeMode = &md.Mapped.Destination.VolumeMode
+			}
+		}
+		// direct LUNs are copied into block PVCs unless the
+		// volume mode is specified in the storage map.
+		if da.Disk.Lun != nil && (md.Mapped == nil || md.Mapped.Destination.VolumeMode == "") {
+			volumeMode := core.PersistentVolumeBlock
+			dvSpec.Storage.VolumeMode = &volumeMode
+		}
+		// shared disks are attached to multiple VMs.
+		if md.Shared {
+			volumeMode := core.PersistentVolumeBlock
+			dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteMany}
+			dvSpec.Storage.VolumeMode = &volumeMode
+		}
+
+		dv := dvTemplate.DeepCopy()
+		dv.Spec = dvSpec
+		if dv.ObjectMeta.Annotations == nil {
+			dv.ObjectMeta.Annotations = make(map[string]string)
+		}
+		dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = da.Disk.ID
+		if md.Shared {
+			dv.ObjectMeta.Annotations[planbase.AnnSharedDisk] = sharedDisks.Annotation(da.Disk.ID)
+		}
+		dvs = append(dvs, *dv)
+	}
+
+	return
+}
+
+// Source disk copied to the destination.
+type mappedDisk struct {
+	// Source disk attachment.
+	Attachment model.XDiskAttachment
+	// Storage map entry (nil for direct LUNs).
+	Mapped *api.StoragePair
+	// Destination storage class.
+	StorageClass string
+	// Destination size (bytes).
+	Size int64
+	// Shared with other VMs in the plan.
+	Shared bool
+}
+
+// Find the disks copied to the destination along with the
+// storage class and size of the destination PVC.
+// Shared disks are only copied by the owner. Direct LUNs are
+// copied only when requested and not mapped to persistent volumes.
+// Shared by the builder and the validator so the validated
+// requests match the DataVolumes.
+func mappedDisks(inventory web.Client, storageMap []api.StoragePair, luns plan.LUNs, sharedDisks planbase.SharedDisks, vm *model.Workload) (disks []mappedDisk, err error) {
+	for i := range storageMap {
+		mapped := &storageMap[i]
 		sd := &model.StorageDomain{}
-		fErr := r.Source.Inventory.Find(sd, ref)
-		if fErr != nil {
-			err = fErr
+		err = inventory.Find(sd, mapped.Source)
+		if err != nil {
 			return
 		}
 		for _, da := range vm.DiskAttachments {
-			if da.Disk.StorageDomain == sd.ID {
-				storageClass := mapped.Destination.StorageClass
-				size := da.Disk.ProvisionedSize
-				if da.Disk.ActualSize > size {
-					size = da.Disk.ActualSize
-				}
-				dvSpec := cdi.DataVolumeSpec{
-					Source: &cdi.DataVolumeSource{
-						Imageio: &cdi.DataVolumeSourceImageIO{
-							URL:           url,
-							DiskID:        da.Disk.ID,
-							SecretRef:     secret.Name,
-							CertConfigMap: configMap.Name,
-						},
-					},
-					Storage: &cdi.StorageSpec{
-						Resources: core.ResourceRequirements{
-							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI),
-							},
-						},
-						StorageClassName: &storageClass,
-					},
-				}
-				// set the access mode and volume mode if they were specified in the storage map.
-				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
-				}
-				if mapped.Destination.VolumeMode != "" {
-					dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
-				}
-
-				dv := dvTemplate.DeepCopy()
-				dv.Spec = dvSpec
-				if dv.ObjectMeta.Annotations == nil {
-					dv.ObjectMeta.Annotations = make(map[string]string)
-				}
-				dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = da.Disk.ID
-				dvs = append(dvs, *dv)
+			if da.Disk.StorageDomain != sd.ID {
+				continue
+			}
+			shared := sharedDisks.Shared(da.Disk.ID)
+			if shared && !sharedDisks.Owner(da.Disk.ID, vm.ID) {
+				continue
 			}
+			size := da.Disk.ProvisionedSize
+			if da.Disk.ActualSize > size {
+				size = da.Disk.ActualSize
+			}
+			disks = append(
+				disks,
+				mappedDisk{
+					Attachment:   da,
+					Mapped:       mapped,
+					StorageClass: mapped.Destination.StorageClass,
+					Size:         size,
+					Shared:       shared,
+				})
+		}
+	}
+	// direct LUNs are not on a storage domain.
+	if !luns.Copy {
+		return
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.Lun == nil {
+			continue
 		}
+		if _, found := luns.FindVolume(da.Disk.Lun.ID, da.Disk.Lun.Serial); found {
+			continue
+		}
+		disks = append(
+			disks,
+			mappedDisk{
+				Attachment:   da,
+				StorageClass: luns.StorageClass,
+				Size:         da.Disk.Lun.Size,
+			})
 	}
 
 	return
@@ -268,10 +354,15 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return
 	}
 
+	sharedDisks, err := r.SharedDisks()
+	if err != nil {
+		return
+	}
+
 	if object.Template == nil {
 		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
 	}
-	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapDisks(vm, persistentVolumeClaims, sharedDisks, object)
 	r.mapFirmware(vm, &vm.Cluster, object)
 	r.mapCPU(vm, object)
 	r.mapMemory(vm, object)
@@ -293,6 +384,9 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	netMapIn := r.Context.Map.Network.Spec.Map
 	for i := range netMapIn {
 		mapped := &netMapIn[i]
+		if mapped.Destination.Type == api.IgnoredNetwork {
+			continue
+		}
 		ref := mapped.Source
 		network := &model.Network{}
 		fErr := r.Source.Inventory.Find(network, ref)
@@ -321,10 +415,10 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 				MacAddress: nic.MAC,
 			}
 			switch mapped.Destination.Type {
-			case Pod:
+			case api.PodNetwork:
 				kNetwork.Pod = &cnv.PodNetwork{}
 				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
+			case api.MultusNetwork:
 				kNetwork.Multus = &cnv.MultusNetwork{
 					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
 				}
@@ -333,6 +427,17 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 				} else {
 					kInterface.Bridge = &cnv.InterfaceBridge{}
 				}
+			case api.OvnNetwork:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Bridge = &cnv.InterfaceBridge{}
+			case api.SriovNetwork:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Model = ""
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 			}
 			kNetworks = append(kNetworks, kNetwork)
 			kInterfaces = append(kInterfaces, kInterface)
@@ -397,14 +502,16 @@ func (r *Builder) mapFirmware(vm *model.Workload, cluster *model.Cluster, object
 	}
 	switch biosType {
 	case Q35Ovmf, Q35SecureBoot:
-		// We disable secure boot even if it was enabled on the source because the guest OS won't
-		// be able to boot without getting the NVRAM data. So we start the VM without secure boot
-		// to ease the procedure users need to do in order to make the guest OS to boot.
-		secureBootEnabled := false
+		// Secure boot is enabled when enabled on the source. The NVRAM is not
+		// transferred so the guest boots with the default (enrolled) keys.
+		secureBootEnabled := biosType == Q35SecureBoot
 		firmware.Bootloader = &cnv.Bootloader{
 			EFI: &cnv.EFI{
 				SecureBoot: &secureBootEnabled,
 			}}
+		if secureBootEnabled {
+			features.SMM = &cnv.FeatureState{Enabled: &secureBootEnabled}
+		}
 	default:
 		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
 	}
@@ -412,10 +519,12 @@ func (r *Builder) mapFirmware(vm *model.Workload, cluster *model.Cluster, object
 	object.Template.Spec.Domain.Firmware = firmware
 }
 
-func (r *Builder) mapDisks(vm *model.Workload, persistentVolumeClaims []core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+func (r *Builder) mapDisks(vm *model.Workload, persistentVolumeClaims []core.PersistentVolumeClaim, sharedDisks planbase.SharedDisks, object *cnv.VirtualMachineSpec) {
 	var kVolumes []cnv.Volume
 	var kDisks []cnv.Disk
 
+	devices := r.devices()
+	bootOrder, cdromOrder := r.bootOrder(vm)
 	pvcMap := make(map[string]*core.PersistentVolumeClaim)
 	for i := range persistentVolumeClaims {
 		pvc := &persistentVolumeClaims[i]
@@ -450,13 +559,126 @@ func (r *Builder) mapDisks(vm *model.Workload, persistentVolumeClaims []core.Per
 				},
 			},
 		}
+		if order, found := bootOrder[da.ID]; found {
+			disk.BootOrder = &order
+		}
+		if sharedDisks.Shared(da.Disk.ID) {
+			disk.Cache = cnv.CacheNone
+			disk.IO = cnv.IONative
+		}
 		kVolumes = append(kVolumes, volume)
 		kDisks = append(kDisks, disk)
 	}
+	// CD-ROMs without a mapped ISO are not created
+	// (reported by the plan validation).
+	if devices.CDROMs {
+		for i, cdrom := range vm.CDROMs {
+			claimName, found := devices.FindISO(cdrom.File)
+			if !found {
+				continue
+			}
+			name := fmt.Sprintf("cdrom-%v", i)
+			disk := cnv.Disk{
+				Name: name,
+				DiskDevice: cnv.DiskDevice{
+					CDRom: &cnv.CDRomTarget{
+						Bus: Sata,
+					},
+				},
+			}
+			kVolumes = append(
+				kVolumes,
+				cnv.Volume{
+					Name: name,
+					VolumeSource: cnv.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: claimName,
+							ReadOnly:  true,
+						},
+					},
+				})
+			if cdromOrder > 0 {
+				order := cdromOrder
+				disk.BootOrder = &order
+				cdromOrder = 0
+			}
+			kDisks = append(kDisks, disk)
+		}
+	}
 	object.Template.Spec.Volumes = kVolumes
 	object.Template.Spec.Domain.Devices.Disks = kDisks
 }
 
+// Source device preservation.
+func (r *Builder) devices() (devices plan.Devices) {
+	if r.Plan.Spec.Devices != nil {
+		devices = *r.Plan.Spec.Devices
+	}
+	return
+}
+
+// Direct LUN handling.
+func (r *Builder) luns() (luns plan.LUNs) {
+	if r.Plan.Spec.LUNs != nil {
+		luns = *r.Plan.Spec.LUNs
+	}
+	return
+}
+
+// Build the direct LUNs mapped to persistent volumes.
+// Keyed by disk ID.
+func (r *Builder) MappedLUNs(vmRef ref.Ref) (volumes map[string]string, err error) {
+	volumes = map[string]string{}
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	luns := r.luns()
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.Lun == nil {
+			continue
+		}
+		if volume, found := luns.FindVolume(da.Disk.Lun.ID, da.Disk.Lun.Serial); found {
+			volumes[da.Disk.ID] = volume
+		}
+	}
+	return
+}
+
+// Boot order of disks (by attachment ID) and the (first) CD-ROM
+// from the source boot devices. The bootable disks are ordered
+// for the `hd` device. Empty when not preserved.
+func (r *Builder) bootOrder(vm *model.Workload) (disks map[string]uint, cdrom uint) {
+	disks = map[string]uint{}
+	if !r.devices().BootOrder {
+		return
+	}
+	order := uint(1)
+	for _, device := range vm.BootDevices {
+		switch device {
+		case BootHD:
+			for _, da := range vm.DiskAttachments {
+				if da.Bootable {
+					disks[da.ID] = order
+					order++
+				}
+			}
+		case BootCDROM:
+			if cdrom == 0 && r.devices().CDROMs {
+				cdrom = order
+				order++
+			}
+		}
+	}
+	return
+}
+
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	vm := &model.Workload{}
@@ -468,8 +690,16 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 			"vm",
 			vmRef.String())
 	}
+	luns := r.luns()
 	for _, da := range vm.DiskAttachments {
 		mB := da.Disk.ProvisionedSize / 0x100000
+		if da.Disk.Lun != nil {
+			// LUNs mapped to persistent volumes are not copied.
+			if _, found := luns.FindVolume(da.Disk.Lun.ID, da.Disk.Lun.Serial); found {
+				continue
+			}
+			mB = da.Disk.Lun.Size / 0x100000
+		}
 		list = append(
 			list,
 			&plan.Task{
@@ -517,14 +747,25 @@ func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err e
 	return
 }
 
+// Build the labels and annotations mapped from the source VM metadata.
+// Metadata mapping is not supported.
+func (r *Builder) Metadata(vmRef ref.Ref) (labels, annotations map[string]string, err error) {
+	return
+}
+
 // Return a stable identifier for a DataVolume.
 func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
 	return dv.ObjectMeta.Annotations[planbase.AnnDiskSource]
 }
 
 // Return a stable identifier for a PersistentDataVolume.
+// PVCs that are not imported (LUNs mapped to persistent volumes)
+// are identified by the disk source.
 func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
-	return pvc.Annotations[AnnImportDiskId]
+	if id, found := pvc.Annotations[AnnImportDiskId]; found {
+		return id
+	}
+	return pvc.Annotations[planbase.AnnDiskSource]
 }
 
 // Build a PersistentVolumeClaim with DataSourceRef for VolumePopulator
@@ -566,6 +807,75 @@ func (r *Builder) PersistentVolumeClaimWithSourceRef(da interface{}, storageName
 	}
 }
 
+// Build the firmware state to be persisted on the target VM.
+// The EFI variables are persisted for secure boot.
+func (r *Builder) PersistentState(vmRef ref.Ref) (state planbase.PersistentState, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
+
+// Build the firmware state to be persisted for the VM.
+func persistentState(vm *model.Workload) (state planbase.PersistentState) {
+	biosType := vm.BIOS
+	if biosType == ClusterDefault {
+		biosType = vm.Cluster.BiosType
+	}
+	state.EFI = biosType == Q35SecureBoot
+	state.TPM = vm.TpmEnabled
+	return
+}
+
+// Build the disks shared between VMs in the plan.
+// Keyed by disk ID. Built once (per reconcile) by
+// the builder.
+func (r *Builder) SharedDisks() (disks planbase.SharedDisks, err error) {
+	if r.sharedDisks != nil {
+		disks = r.sharedDisks
+		return
+	}
+	disks, err = sharedDisks(r.Source.Inventory, r.Plan.Spec.VMs)
+	if err != nil {
+		return
+	}
+	r.sharedDisks = disks
+	return
+}
+
+// Build the disks shared between the VMs.
+// Keyed by disk ID.
+func sharedDisks(inventory web.Client, vms []plan.VM) (disks planbase.SharedDisks, err error) {
+	disks = planbase.SharedDisks{}
+	for _, planVM := range vms {
+		vm := &model.Workload{}
+		err = inventory.Find(vm, planVM.Ref)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"VM lookup failed.",
+				"vm",
+				planVM.Ref.String())
+			return
+		}
+		for _, da := range vm.DiskAttachments {
+			if da.Disk.Shared {
+				disks.Add(da.Disk.ID, vm.ID)
+			}
+		}
+	}
+	disks.Prune()
+	return
+}
+
 func (r *Builder) PreTransferActions(c planbase.Client, vmRef ref.Ref) (ready bool, err error) {
 	return true, nil
 }
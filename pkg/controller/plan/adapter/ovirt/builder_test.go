@@ -0,0 +1,94 @@
+package ovirt
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	attachment := func(id, bus string, bootable bool) (da model.XDiskAttachment) {
+		da.ID = id
+		da.Interface = bus
+		da.Bootable = bootable
+		da.Disk.ID = "disk-" + id
+		return
+	}
+	vm := &model.Workload{}
+	vm.DiskAttachments = []model.XDiskAttachment{
+		attachment("a", VirtioScsi, false),
+		attachment("b", IDE, true),
+	}
+	vm.CDROMs = []ovirt.CDROM{
+		{ID: "c", File: "rhel.iso"},
+		{ID: "d"},
+	}
+	vm.BootDevices = []string{BootCDROM, BootHD}
+	pvc := func(name, id string) (pvc core.PersistentVolumeClaim) {
+		pvc.ObjectMeta = meta.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				AnnImportDiskId: id,
+			},
+		}
+		return
+	}
+	pvcs := []core.PersistentVolumeClaim{
+		pvc("pvc-a", "disk-a"),
+		pvc("pvc-b", "disk-b"),
+	}
+	builder := &Builder{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{},
+		},
+	}
+	order := func(n uint) *uint {
+		return &n
+	}
+
+	// Not preserved.
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, pvcs, planbase.SharedDisks{}, object)
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(disks).To(gomega.HaveLen(2))
+	g.Expect(disks[0].Disk.Bus).To(gomega.Equal(Scsi))
+	g.Expect(disks[1].Disk.Bus).To(gomega.Equal(Sata))
+	g.Expect(disks[0].BootOrder).To(gomega.BeNil())
+	g.Expect(disks[1].BootOrder).To(gomega.BeNil())
+
+	// Preserved.
+	builder.Plan.Spec.Devices = &plan.Devices{
+		BootOrder: true,
+		CDROMs:    true,
+		ISOs: []plan.ISOMapping{
+			{Source: "rhel.iso", ClaimName: "rhel-iso"},
+		},
+	}
+	object = &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, pvcs, planbase.SharedDisks{}, object)
+	disks = object.Template.Spec.Domain.Devices.Disks
+	g.Expect(disks).To(gomega.HaveLen(3))
+	// Boot order.
+	g.Expect(disks[0].BootOrder).To(gomega.BeNil())
+	g.Expect(disks[1].BootOrder).To(gomega.Equal(order(2)))
+	g.Expect(disks[2].BootOrder).To(gomega.Equal(order(1)))
+	// CD-ROM.
+	g.Expect(disks[2].Name).To(gomega.Equal("cdrom-0"))
+	g.Expect(disks[2].CDRom.Bus).To(gomega.Equal(Sata))
+	volumes := object.Template.Spec.Volumes
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-a"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-b"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("rhel-iso"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ReadOnly).To(gomega.BeTrue())
+}
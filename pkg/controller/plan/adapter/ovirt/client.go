@@ -4,9 +4,11 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/ovirt"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
@@ -140,6 +142,117 @@ func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, data
 	return
 }
 
+// Measure the data (MB) changed between the previous and the latest precopy.
+// The snapshot disk actual size is the allocation of the volume
+// (extents on block storage) rather than the data changed so the
+// delta is not measured.
+func (r *Client) PrecopyDelta(vmRef ref.Ref, precopies []planapi.Precopy) (delta int64, err error) {
+	err = base.PrecopyDeltaNotSupportedError{}
+	return
+}
+
+// Perform a decommission action on the source VM.
+func (r *Client) Decommission(vmRef ref.Ref, action planapi.DecommissionAction) (undo string, err error) {
+	vm, vmService, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	update := ovirtsdk.NewVmBuilder()
+	switch action.Action {
+	case planapi.DecommissionRename:
+		undo = vm.MustName()
+		update.Name(undo + action.Value)
+	case planapi.DecommissionAnnotate:
+		undo, _ = vm.Description()
+		value := action.Value
+		if undo != "" {
+			value = undo + "\n" + value
+		}
+		update.Description(value)
+	case planapi.DecommissionDisableAutostart:
+		enabled := false
+		if ha, found := vm.HighAvailability(); found {
+			enabled, _ = ha.Enabled()
+		}
+		undo = strconv.FormatBool(enabled)
+		update.HighAvailability(
+			ovirtsdk.NewHighAvailabilityBuilder().Enabled(false).MustBuild())
+	case planapi.DecommissionTag:
+		response, aErr := vmService.TagsService().Add().
+			Tag(ovirtsdk.NewTagBuilder().Name(action.Value).MustBuild()).
+			Query("correlation_id", r.Migration.Name).
+			Send()
+		if aErr != nil {
+			err = liberr.Wrap(aErr)
+			return
+		}
+		if tag, found := response.Tag(); found {
+			undo, _ = tag.Id()
+		}
+		return
+	case planapi.DecommissionDelete:
+		_, err = vmService.Remove().Query("correlation_id", r.Migration.Name).Send()
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+		return
+	}
+	_, err = vmService.Update().
+		Vm(update.MustBuild()).
+		Query("correlation_id", r.Migration.Name).
+		Send()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Undo a decommission action performed on the source VM.
+func (r *Client) UndoDecommission(vmRef ref.Ref, action planapi.DecommissionAction) (err error) {
+	_, vmService, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	update := ovirtsdk.NewVmBuilder()
+	switch action.Action {
+	case planapi.DecommissionRename:
+		update.Name(action.Undo)
+	case planapi.DecommissionAnnotate:
+		update.Description(action.Undo)
+	case planapi.DecommissionDisableAutostart:
+		enabled, _ := strconv.ParseBool(action.Undo)
+		update.HighAvailability(
+			ovirtsdk.NewHighAvailabilityBuilder().Enabled(enabled).MustBuild())
+	case planapi.DecommissionTag:
+		if action.Undo == "" {
+			return
+		}
+		_, err = vmService.TagsService().TagService(action.Undo).Remove().
+			Query("correlation_id", r.Migration.Name).
+			Send()
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+		return
+	}
+	_, err = vmService.Update().
+		Vm(update.MustBuild()).
+		Query("correlation_id", r.Migration.Name).
+		Send()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
 // Get the power state of the VM.
 func (r *Client) PowerState(vmRef ref.Ref) (state string, err error) {
 	vm, _, err := r.getVM(vmRef)
@@ -2,7 +2,9 @@ package ovirt
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -13,6 +15,8 @@ import (
 type Validator struct {
 	plan      *api.Plan
 	inventory web.Client
+	// Disks shared between VMs in the plan.
+	sharedDisks base.SharedDisks
 }
 
 // Load.
@@ -80,7 +84,7 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 			return
 		}
 		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID && mapped.Destination.Type == Pod {
+			if nic.Profile.Network == network.ID && mapped.Destination.Type == api.PodNetwork {
 				podMapped++
 			}
 		}
@@ -106,6 +110,10 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 	for _, da := range vm.DiskAttachments {
+		// direct LUNs are not on a storage domain.
+		if da.Disk.Lun != nil {
+			continue
+		}
 		if !r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: da.Disk.StorageDomain}) {
 			return
 		}
@@ -114,8 +122,255 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's direct LUN disks are copied or
+// mapped to persistent volumes. The LUNs are copied
+// using the LUN storage class.
+func (r *Validator) LUNs(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	luns := planapi.LUNs{}
+	if r.plan.Spec.LUNs != nil {
+		luns = *r.plan.Spec.LUNs
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.Lun == nil {
+			continue
+		}
+		if _, found := luns.FindVolume(da.Disk.Lun.ID, da.Disk.Lun.Serial); found {
+			continue
+		}
+		if !luns.Copy || luns.StorageClass == "" {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that a VM's disks are not encrypted by the provider.
+// No-op for oVirt.
+func (r *Validator) EncryptedDisks(_ ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// Validate that a VM's CD-ROMs have a mapped ISO
+// when the CD-ROMs are preserved.
+func (r *Validator) CDROMs(vmRef ref.Ref) (ok bool, err error) {
+	devices := r.plan.Spec.Devices
+	if devices == nil || !devices.CDROMs {
+		ok = true
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, cdrom := range vm.CDROMs {
+		if _, found := devices.FindISO(cdrom.File); !found {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
 // Validate that a VM's Host isn't in maintenance mode. No-op for oVirt.
 func (r *Validator) MaintenanceMode(_ ref.Ref) (ok bool, err error) {
 	ok = true
 	return
 }
+
+// Validate that a VM is within the inventory scope.
+// Contained in a listed cluster.
+func (r *Validator) Scoped(vmRef ref.Ref, scope *api.ProviderScope) (ok bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	ok = scope.HasCluster(vm.Cluster.ID, vm.Cluster.Name)
+
+	return
+}
+
+// Select the VMs matching the selector.
+func (r *Validator) SelectVMs(selector *planapi.VMSelector) (refs []ref.Ref, err error) {
+	switch {
+	case selector.Folder != "":
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "folder"})
+		return
+	case selector.Project != "":
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "project"})
+		return
+	case len(selector.Tags) > 0:
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "tags"})
+		return
+	case len(selector.Labels) > 0:
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "labels"})
+		return
+	}
+	cluster := ""
+	if selector.Cluster != "" {
+		cluster, err = r.clusterID(selector.Cluster)
+		if err != nil {
+			return
+		}
+	}
+	list := []model.VM{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, vm := range list {
+		if cluster != "" && vm.Cluster != cluster {
+			continue
+		}
+		if selector.ExcludeCritical && base.HasCriticalConcern(vm.Concerns) {
+			continue
+		}
+		matched, mErr := selector.MatchName(vm.Name)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		if matched {
+			refs = append(refs, ref.Ref{ID: vm.ID, Name: vm.Name})
+		}
+	}
+
+	return
+}
+
+// Find the ID of the cluster (name, path or ID).
+func (r *Validator) clusterID(cluster string) (id string, err error) {
+	list := []model.Cluster{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, c := range list {
+		if c.ID == cluster || c.Name == cluster || c.Path == cluster {
+			id = c.ID
+			return
+		}
+	}
+	err = liberr.Wrap(web.NotFoundError{Ref: ref.Ref{Name: cluster}})
+	return
+}
+
+// Return the destination resources requested by a VM.
+// The disks are sized as the builder sizes the DataVolumes.
+func (r *Validator) Requests(vmRef ref.Ref) (requests base.Requests, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	requests = base.Requests{
+		CPU:    int64(vm.CpuSockets) * int64(vm.CpuCores) * int64(vm.CpuThreads),
+		Memory: vm.Memory,
+	}
+	if vm.Host != nil {
+		requests.Host = vm.Host.ID
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	if r.sharedDisks == nil {
+		r.sharedDisks, err = sharedDisks(r.inventory, r.plan.Spec.VMs)
+		if err != nil {
+			return
+		}
+	}
+	luns := planapi.LUNs{}
+	if r.plan.Spec.LUNs != nil {
+		luns = *r.plan.Spec.LUNs
+	}
+	disks, err := mappedDisks(r.inventory, r.plan.Referenced.Map.Storage.Spec.Map, luns, r.sharedDisks, vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, disk := range disks {
+		request := base.DiskRequest{
+			StorageClass: disk.StorageClass,
+			Size:         disk.Size,
+		}
+		if disk.Mapped != nil {
+			request.Storage = disk.Attachment.Disk.StorageDomain
+		}
+		requests.Disks = append(requests.Disks, request)
+	}
+
+	return
+}
+
+// Return the concerns reported for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planapi.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	concerns = base.Concerns(vm.Concerns)
+
+	return
+}
+
+// Return the firmware state to be persisted for a VM.
+func (r *Validator) PersistentState(vmRef ref.Ref) (state base.PersistentState, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
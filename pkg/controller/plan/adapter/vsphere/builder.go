@@ -28,6 +28,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	cnv "kubevirt.io/client-go/api/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,9 +39,16 @@ const (
 	Efi = "efi"
 )
 
+// Datacenter of a (stand-alone) ESXi host.
+const (
+	HostDatacenter = "ha-datacenter"
+)
+
 // Bus types
 const (
 	Virtio = "virtio"
+	Sata   = "sata"
+	Scsi   = "scsi"
 )
 
 // Input types
@@ -48,12 +56,6 @@ const (
 	Tablet = "tablet"
 )
 
-// Network types
-const (
-	Pod    = "pod"
-	Multus = "multus"
-)
-
 // Template labels
 const (
 	TemplateOSLabel       = "os.template.kubevirt.io/%s"
@@ -74,6 +76,13 @@ const (
 	AnnImportBackingFile = "cdi.kubevirt.io/storage.import.backingFile"
 )
 
+// Metadata mapping
+const (
+	// Separates the tags mapped to a label value.
+	// The `,` annotation separator is not valid in labels.
+	LabelValueSeparator = "_"
+)
+
 // Map of vmware guest ids to osinfo ids.
 var osMap = map[string]string{
 	"centos64Guest":         "centos5.11",
@@ -124,6 +133,9 @@ var osMap = map[string]string{
 // vSphere disk backing file.
 var backingFilePattern = regexp.MustCompile("-\\d\\d\\d\\d\\d\\d.vmdk")
 
+// Datastore path. E.g.: [datastore1] vm/vm.vmx
+var datastorePathPattern = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
+
 // vSphere builder.
 type Builder struct {
 	*plancontext.Context
@@ -131,6 +143,8 @@ type Builder struct {
 	hosts map[string]*api.Host
 	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
 	macConflictsMap map[string]string
+	// Disks shared between VMs in the plan.
+	sharedDisks planbase.SharedDisks
 }
 
 // Get list of destination VMs with mac addresses that would
@@ -238,6 +252,58 @@ func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env
 			Value: libvirtURL.String(),
 		},
 	)
+	// The NVRAM is downloaded from the ESXi host when the
+	// host is defined (matching the credentials).
+	if persistentState(vm).EFI {
+		server := host.ManagementServerIp
+		datacenter := strings.Split(strings.TrimPrefix(host.Path, "/"), "/")[0]
+		if hostDef, found := r.hosts[vm.Host]; found {
+			server = hostDef.Spec.IpAddress
+			datacenter = HostDatacenter
+		}
+		if nvram, found := nvramURL(vm, server, datacenter); found {
+			env = append(
+				env,
+				core.EnvVar{
+					Name:  "V2V_nvram",
+					Value: nvram.String(),
+				})
+		}
+	}
+	return
+}
+
+// Build the (datastore file access) URL of the VM NVRAM file.
+// The NVRAM file is set by the `nvram` extra config option,
+// relative to the VM configuration (vmx) file directory, and
+// defaults to the vmx file name with the `.nvram` extension.
+func nvramURL(vm *model.VM, server, datacenter string) (u *liburl.URL, found bool) {
+	match := datastorePathPattern.FindStringSubmatch(vm.ConfigFile)
+	if match == nil {
+		return
+	}
+	datastore, file := match[1], match[2]
+	nvram := vm.NVRAM
+	if nvram == "" {
+		nvram = strings.TrimSuffix(path.Base(file), path.Ext(file)) + ".nvram"
+	}
+	if match = datastorePathPattern.FindStringSubmatch(nvram); match != nil {
+		datastore, file = match[1], match[2]
+	} else if path.IsAbs(nvram) {
+		return
+	} else {
+		file = path.Join(path.Dir(file), nvram)
+	}
+	u = &liburl.URL{
+		Scheme: "https",
+		Host:   server,
+		Path:   path.Join("/folder", file),
+		RawQuery: liburl.Values{
+			"dcPath": []string{datacenter},
+			"dsName": []string{datastore},
+		}.Encode(),
+	}
+	found = true
 	return
 }
 
@@ -298,67 +364,135 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 		}
 		thumbprint = h.Thumbprint
 	}
+	sharedDisks, err := r.SharedDisks()
+	if err != nil {
+		return
+	}
+	disks, err := mappedDisks(r.Source.Inventory, r.Context.Map.Storage.Spec.Map, r.luns(), vm)
+	if err != nil {
+		return
+	}
+	for _, md := range disks {
+		disk := md.Disk
+		mapped := md.Mapped
+		source := trimBackingFileName(disk.File)
+		shared := sharedDisks.Shared(source)
+		if shared && !sharedDisks.Owner(source, vm.ID) {
+			continue
+		}
+		storageClass := md.StorageClass
+		var dvSource cdi.DataVolumeSource
+		if r.Context.UseEl9VirtV2v() {
+			// Let virt-v2v do the copying
+			dvSource = cdi.DataVolumeSource{
+				Blank: &cdi.DataVolumeBlankImage{},
+			}
+		} else {
+			// Let CDI do the copying
+			dvSource = cdi.DataVolumeSource{
+				VDDK: &cdi.DataVolumeSourceVDDK{
+					BackingFile:  trimBackingFileName(disk.File),
+					UUID:         vm.UUID,
+					URL:          url,
+					SecretRef:    secret.Name,
+					Thumbprint:   thumbprint,
+					InitImageURL: r.Source.Provider.Spec.Settings["vddkInitImage"],
+				},
+			}
+		}
+		dvSpec := cdi.DataVolumeSpec{
+			Source: &dvSource,
+			Storage: &cdi.StorageSpec{
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: *resource.NewQuantity(md.Size, resource.BinarySI),
+					},
+				},
+				StorageClassName: &storageClass,
+			},
+		}
+		// set the access mode and volume mode if they were specified in the storage map.
+		// otherwise, let the storage profile decide the default values.
+		if mapped.Destination.AccessMode != "" {
+			dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
+		}
+		if mapped.Destination.VolumeMode != "" {
+			dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
+		}
+		// LUN contents are copied into block PVCs unless the
+		// volume mode is specified in the storage map.
+		if disk.RDM && mapped.Destination.VolumeMode == "" {
+			volumeMode := core.PersistentVolumeBlock
+			dvSpec.Storage.VolumeMode = &volumeMode
+		}
+		// shared disks are attached to multiple VMs.
+		if shared {
+			volumeMode := core.PersistentVolumeBlock
+			dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteMany}
+			dvSpec.Storage.VolumeMode = &volumeMode
+		}
 
-	dsMapIn := r.Context.Map.Storage.Spec.Map
-	for i := range dsMapIn {
-		mapped := &dsMapIn[i]
-		ref := mapped.Source
+		dv := dvTemplate.DeepCopy()
+		dv.Spec = dvSpec
+		if dv.ObjectMeta.Annotations == nil {
+			dv.ObjectMeta.Annotations = make(map[string]string)
+		}
+		dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = source
+		if shared {
+			dv.ObjectMeta.Annotations[planbase.AnnSharedDisk] = sharedDisks.Annotation(source)
+		}
+		dvs = append(dvs, *dv)
+	}
+
+	return
+}
+
+// Source disk on a mapped datastore.
+type mappedDisk struct {
+	// Source disk.
+	Disk vsphere.Disk
+	// Storage map entry.
+	Mapped *api.StoragePair
+	// Destination storage class.
+	StorageClass string
+	// Destination size (bytes).
+	Size int64
+}
+
+// Find the disks on mapped datastores copied to the destination
+// along with the storage class and size of the destination PVC.
+// RDM disks backed by LUNs mapped to persistent volumes are not
+// copied. Shared by the builder and the validator so the validated
+// requests match the DataVolumes.
+func mappedDisks(inventory web.Client, storageMap []api.StoragePair, luns plan.LUNs, vm *model.VM) (disks []mappedDisk, err error) {
+	for i := range storageMap {
+		mapped := &storageMap[i]
 		ds := &model.Datastore{}
-		fErr := r.Source.Inventory.Find(ds, ref)
-		if fErr != nil {
-			err = fErr
+		err = inventory.Find(ds, mapped.Source)
+		if err != nil {
 			return
 		}
 		for _, disk := range vm.Disks {
-			if disk.Datastore.ID == ds.ID {
-				storageClass := mapped.Destination.StorageClass
-				var dvSource cdi.DataVolumeSource
-				if r.Context.UseEl9VirtV2v() {
-					// Let virt-v2v do the copying
-					dvSource = cdi.DataVolumeSource{
-						Blank: &cdi.DataVolumeBlankImage{},
-					}
-				} else {
-					// Let CDI do the copying
-					dvSource = cdi.DataVolumeSource{
-						VDDK: &cdi.DataVolumeSourceVDDK{
-							BackingFile:  trimBackingFileName(disk.File),
-							UUID:         vm.UUID,
-							URL:          url,
-							SecretRef:    secret.Name,
-							Thumbprint:   thumbprint,
-							InitImageURL: r.Source.Provider.Spec.Settings["vddkInitImage"],
-						},
-					}
-				}
-				dvSpec := cdi.DataVolumeSpec{
-					Source: &dvSource,
-					Storage: &cdi.StorageSpec{
-						Resources: core.ResourceRequirements{
-							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(disk.Capacity, resource.BinarySI),
-							},
-						},
-						StorageClassName: &storageClass,
-					},
-				}
-				// set the access mode and volume mode if they were specified in the storage map.
-				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
-				}
-				if mapped.Destination.VolumeMode != "" {
-					dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
+			if disk.Datastore.ID != ds.ID {
+				continue
+			}
+			storageClass := mapped.Destination.StorageClass
+			if disk.RDM {
+				if _, found := luns.FindVolume(disk.LUN); found {
+					continue
 				}
-
-				dv := dvTemplate.DeepCopy()
-				dv.Spec = dvSpec
-				if dv.ObjectMeta.Annotations == nil {
-					dv.ObjectMeta.Annotations = make(map[string]string)
+				if luns.StorageClass != "" {
+					storageClass = luns.StorageClass
 				}
-				dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = trimBackingFileName(disk.File)
-				dvs = append(dvs, *dv)
 			}
+			disks = append(
+				disks,
+				mappedDisk{
+					Disk:         disk,
+					Mapped:       mapped,
+					StorageClass: storageClass,
+					Size:         disk.Capacity,
+				})
 		}
 	}
 
@@ -416,10 +550,15 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return
 	}
 
+	sharedDisks, err := r.SharedDisks()
+	if err != nil {
+		return
+	}
+
 	if object.Template == nil {
 		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
 	}
-	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapDisks(vm, persistentVolumeClaims, sharedDisks, object)
 	r.mapFirmware(vm, object)
 	r.mapCPU(vm, object)
 	r.mapMemory(vm, object)
@@ -441,6 +580,9 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	netMapIn := r.Context.Map.Network.Spec.Map
 	for i := range netMapIn {
 		mapped := &netMapIn[i]
+		if mapped.Destination.Type == api.IgnoredNetwork {
+			continue
+		}
 		ref := mapped.Source
 		network := &model.Network{}
 		fErr := r.Source.Inventory.Find(network, ref)
@@ -477,14 +619,20 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 				MacAddress: nic.MAC,
 			}
 			switch mapped.Destination.Type {
-			case Pod:
+			case api.PodNetwork:
 				kNetwork.Pod = &cnv.PodNetwork{}
 				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
+			case api.MultusNetwork, api.OvnNetwork:
 				kNetwork.Multus = &cnv.MultusNetwork{
 					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
 				}
 				kInterface.Bridge = &cnv.InterfaceBridge{}
+			case api.SriovNetwork:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Model = ""
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 			}
 			kNetworks = append(kNetworks, kNetwork)
 			kInterfaces = append(kInterfaces, kInterface)
@@ -540,16 +688,16 @@ func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	}
 	switch vm.Firmware {
 	case Efi:
-		// We don't distinguish between UEFI and UEFI with secure boot but we anyway would have
-		// disabled secure boot, even if we knew it was enabled on the source, because the guest
-		// OS won't be able to boot without getting the NVRAM data. By starting the VM without
-		// secure boot we ease the procedure users need to do in order to make a guest OS that
-		// was previously configured with secure boot to boot.
-		secureBootEnabled := false
+		// Secure boot is enabled when enabled on the source. The NVRAM is
+		// copied to the persistent EFI variable store by the conversion pod.
+		secureBootEnabled := vm.SecureBoot
 		firmware.Bootloader = &cnv.Bootloader{
 			EFI: &cnv.EFI{
 				SecureBoot: &secureBootEnabled,
 			}}
+		if secureBootEnabled {
+			features.SMM = &cnv.FeatureState{Enabled: &secureBootEnabled}
+		}
 	default:
 		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
 	}
@@ -557,10 +705,12 @@ func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	object.Template.Spec.Domain.Firmware = firmware
 }
 
-func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []core.PersistentVolumeClaim, sharedDisks planbase.SharedDisks, object *cnv.VirtualMachineSpec) {
 	var kVolumes []cnv.Volume
 	var kDisks []cnv.Disk
 
+	devices := r.devices()
+	bootOrder, cdromOrder := r.bootOrder(vm)
 	disks := vm.Disks
 	sort.Slice(disks, func(i, j int) bool {
 		return disks[i].Key < disks[j].Key
@@ -586,21 +736,160 @@ func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []core.Persisten
 				},
 			},
 		}
+		bus := Virtio
+		if devices.DiskBus {
+			bus = r.diskBus(disk.Bus)
+		}
 		kubevirtDisk := cnv.Disk{
 			Name: volumeName,
 			DiskDevice: cnv.DiskDevice{
 				Disk: &cnv.DiskTarget{
-					Bus: Virtio,
+					Bus: bus,
 				},
 			},
 		}
+		if order, found := bootOrder[disk.Key]; found {
+			kubevirtDisk.BootOrder = &order
+		}
+		if sharedDisks.Shared(trimBackingFileName(disk.File)) {
+			kubevirtDisk.Cache = cnv.CacheNone
+			kubevirtDisk.IO = cnv.IONative
+		}
 		kVolumes = append(kVolumes, volume)
 		kDisks = append(kDisks, kubevirtDisk)
 	}
+	// CD-ROMs without a mapped ISO are not created
+	// (reported by the plan validation).
+	if devices.CDROMs {
+		for i, cdrom := range vm.CDROMs {
+			claimName, found := devices.FindISO(cdrom.ISO)
+			if !found {
+				continue
+			}
+			name := fmt.Sprintf("cdrom-%v", i)
+			kubevirtDisk := cnv.Disk{
+				Name: name,
+				DiskDevice: cnv.DiskDevice{
+					CDRom: &cnv.CDRomTarget{
+						Bus: r.cdromBus(cdrom.Bus),
+					},
+				},
+			}
+			kVolumes = append(
+				kVolumes,
+				cnv.Volume{
+					Name: name,
+					VolumeSource: cnv.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: claimName,
+							ReadOnly:  true,
+						},
+					},
+				})
+			if cdromOrder > 0 {
+				order := cdromOrder
+				kubevirtDisk.BootOrder = &order
+				cdromOrder = 0
+			}
+			kDisks = append(kDisks, kubevirtDisk)
+		}
+	}
 	object.Template.Spec.Volumes = kVolumes
 	object.Template.Spec.Domain.Devices.Disks = kDisks
 }
 
+// Source device preservation.
+func (r *Builder) devices() (devices plan.Devices) {
+	if r.Plan.Spec.Devices != nil {
+		devices = *r.Plan.Spec.Devices
+	}
+	return
+}
+
+// RDM disk handling.
+func (r *Builder) luns() (luns plan.LUNs) {
+	if r.Plan.Spec.LUNs != nil {
+		luns = *r.Plan.Spec.LUNs
+	}
+	return
+}
+
+// Build the RDM disks mapped to persistent volumes.
+// Keyed by the (trimmed) backing file.
+func (r *Builder) MappedLUNs(vmRef ref.Ref) (volumes map[string]string, err error) {
+	volumes = map[string]string{}
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	luns := r.luns()
+	for _, disk := range vm.Disks {
+		if !disk.RDM {
+			continue
+		}
+		if volume, found := luns.FindVolume(disk.LUN); found {
+			volumes[trimBackingFileName(disk.File)] = volume
+		}
+	}
+	return
+}
+
+// Boot order of disks (by key) and the (first) CD-ROM from
+// the source boot configuration. Empty when not preserved or
+// not configured on the source.
+func (r *Builder) bootOrder(vm *model.VM) (disks map[int32]uint, cdrom uint) {
+	disks = map[int32]uint{}
+	if !r.devices().BootOrder {
+		return
+	}
+	order := uint(1)
+	for _, device := range vm.BootOrder {
+		switch device.Kind {
+		case vsphere.BootDisk:
+			disks[device.Key] = order
+			order++
+		case vsphere.BootCDROM:
+			if cdrom == 0 && r.devices().CDROMs {
+				cdrom = order
+				order++
+			}
+		}
+	}
+	return
+}
+
+// Disk bus for the source disk controller.
+// Guests booting from IDE disks expect an emulated
+// controller so SATA is used.
+func (r *Builder) diskBus(controller string) (bus string) {
+	switch controller {
+	case vsphere.BusSCSI:
+		bus = Scsi
+	case vsphere.BusSATA, vsphere.BusIDE:
+		bus = Sata
+	default:
+		bus = Virtio
+	}
+	return
+}
+
+// CD-ROM bus for the source controller.
+func (r *Builder) cdromBus(controller string) (bus string) {
+	switch controller {
+	case vsphere.BusSCSI:
+		bus = Scsi
+	default:
+		bus = Sata
+	}
+	return
+}
+
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	vm := &model.VM{}
@@ -613,7 +902,14 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 			vmRef.String())
 		return
 	}
+	luns := r.luns()
 	for _, disk := range vm.Disks {
+		// LUNs mapped to persistent volumes are not copied.
+		if disk.RDM {
+			if _, found := luns.FindVolume(disk.LUN); found {
+				continue
+			}
+		}
 		mB := disk.Capacity / 0x100000
 		list = append(
 			list,
@@ -662,6 +958,77 @@ func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err e
 	return
 }
 
+// Build the labels and annotations mapped from the source VM metadata.
+// Label values that are not valid are skipped.
+func (r *Builder) Metadata(vmRef ref.Ref) (labels, annotations map[string]string, err error) {
+	if len(r.Plan.Spec.MetadataMapping) == 0 {
+		return
+	}
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	labels = make(map[string]string)
+	annotations = make(map[string]string)
+	for _, mapping := range r.Plan.Spec.MetadataMapping {
+		values := r.metadataValue(vm, mapping)
+		if len(values) == 0 {
+			continue
+		}
+		if mapping.Label != "" {
+			value := strings.Join(values, LabelValueSeparator)
+			if len(k8svalidation.IsValidLabelValue(value)) == 0 {
+				labels[mapping.Label] = value
+			} else {
+				r.Log.Info(
+					"Metadata value not valid for label, skipped.",
+					"vm",
+					vmRef.String(),
+					"label",
+					mapping.Label)
+			}
+		}
+		if mapping.Annotation != "" {
+			annotations[mapping.Annotation] = strings.Join(values, ",")
+		}
+	}
+
+	return
+}
+
+// Find the VM metadata values for the mapping.
+// Tags in the category are sorted.
+func (r *Builder) metadataValue(vm *model.VM, mapping plan.MetadataMapping) (values []string) {
+	switch mapping.Source {
+	case plan.MetadataTag:
+		for _, tag := range vm.Tags {
+			if tag.Category == mapping.Name {
+				values = append(values, tag.Name)
+			}
+		}
+		sort.Strings(values)
+	case plan.MetadataAttribute:
+		for _, attribute := range vm.CustomAttributes {
+			if attribute.Name == mapping.Name {
+				values = []string{attribute.Value}
+				break
+			}
+		}
+	case plan.MetadataAnnotation:
+		if vm.Annotation != "" {
+			values = []string{vm.Annotation}
+		}
+	}
+
+	return
+}
+
 // Return a stable identifier for a VDDK DataVolume.
 func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
 	return trimBackingFileName(dv.ObjectMeta.Annotations[planbase.AnnDiskSource])
@@ -785,6 +1152,61 @@ func (r *Builder) PersistentVolumeClaimWithSourceRef(da interface{}, storageName
 	return nil
 }
 
+// Build the firmware state to be persisted on the target VM.
+// The EFI variables are persisted for secure boot.
+func (r *Builder) PersistentState(vmRef ref.Ref) (state planbase.PersistentState, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
+
+// Build the firmware state to be persisted for the VM.
+func persistentState(vm *model.VM) (state planbase.PersistentState) {
+	state.EFI = vm.Firmware == Efi && vm.SecureBoot
+	state.TPM = vm.TpmEnabled
+	return
+}
+
+// Build the disks shared between VMs in the plan.
+// Keyed by the (trimmed) backing file. Built once
+// (per reconcile) by the builder.
+func (r *Builder) SharedDisks() (disks planbase.SharedDisks, err error) {
+	if r.sharedDisks != nil {
+		disks = r.sharedDisks
+		return
+	}
+	disks = planbase.SharedDisks{}
+	for _, planVM := range r.Plan.Spec.VMs {
+		vm := &model.VM{}
+		err = r.Source.Inventory.Find(vm, planVM.Ref)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"VM lookup failed.",
+				"vm",
+				planVM.Ref.String())
+			return
+		}
+		for _, disk := range vm.Disks {
+			if disk.Shared {
+				disks.Add(trimBackingFileName(disk.File), vm.ID)
+			}
+		}
+	}
+	disks.Prune()
+	r.sharedDisks = disks
+	return
+}
+
 func (r *Builder) PreTransferActions(c planbase.Client, vmRef ref.Ref) (ready bool, err error) {
 	return true, nil
 }
@@ -0,0 +1,196 @@
+package vsphere
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &model.VM{}
+	vm.Disks = []vsphere.Disk{
+		{Key: 2001, File: "[ds] vm/vm_1.vmdk", Bus: vsphere.BusIDE},
+		{Key: 2000, File: "[ds] vm/vm.vmdk", Bus: vsphere.BusSCSI},
+	}
+	vm.CDROMs = []vsphere.CDROM{
+		{Key: 3000, Bus: vsphere.BusIDE, ISO: "[ds] iso/rhel.iso"},
+		{Key: 3001, Bus: vsphere.BusSCSI},
+	}
+	vm.BootOrder = []vsphere.BootDevice{
+		{Kind: vsphere.BootCDROM},
+		{Kind: vsphere.BootDisk, Key: 2001},
+	}
+	pvc := func(name, file string) (pvc core.PersistentVolumeClaim) {
+		pvc.ObjectMeta = meta.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				planbase.AnnDiskSource: file,
+			},
+		}
+		return
+	}
+	pvcs := []core.PersistentVolumeClaim{
+		pvc("disk-0", "[ds] vm/vm.vmdk"),
+		pvc("disk-1", "[ds] vm/vm_1.vmdk"),
+	}
+	builder := &Builder{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{},
+		},
+	}
+	order := func(n uint) *uint {
+		return &n
+	}
+
+	// Not preserved.
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, pvcs, planbase.SharedDisks{}, object)
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(disks).To(gomega.HaveLen(2))
+	for _, disk := range disks {
+		g.Expect(disk.Disk.Bus).To(gomega.Equal(Virtio))
+		g.Expect(disk.BootOrder).To(gomega.BeNil())
+	}
+
+	// Preserved.
+	builder.Plan.Spec.Devices = &plan.Devices{
+		DiskBus:   true,
+		BootOrder: true,
+		CDROMs:    true,
+		ISOs: []plan.ISOMapping{
+			{Source: "rhel.iso", ClaimName: "rhel-iso"},
+		},
+	}
+	object = &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, pvcs, planbase.SharedDisks{}, object)
+	disks = object.Template.Spec.Domain.Devices.Disks
+	g.Expect(disks).To(gomega.HaveLen(3))
+	// Bus.
+	g.Expect(disks[0].Name).To(gomega.Equal("vol-0"))
+	g.Expect(disks[0].Disk.Bus).To(gomega.Equal(Scsi))
+	g.Expect(disks[1].Name).To(gomega.Equal("vol-1"))
+	g.Expect(disks[1].Disk.Bus).To(gomega.Equal(Sata))
+	// Boot order.
+	g.Expect(disks[0].BootOrder).To(gomega.BeNil())
+	g.Expect(disks[1].BootOrder).To(gomega.Equal(order(2)))
+	g.Expect(disks[2].BootOrder).To(gomega.Equal(order(1)))
+	// CD-ROMs. Not created without a mapped ISO.
+	g.Expect(disks[2].Name).To(gomega.Equal("cdrom-0"))
+	g.Expect(disks[2].CDRom.Bus).To(gomega.Equal(Sata))
+	volumes := object.Template.Spec.Volumes
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("disk-0"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("disk-1"))
+	g.Expect(volumes[2].Name).To(gomega.Equal("cdrom-0"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("rhel-iso"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ReadOnly).To(gomega.BeTrue())
+}
+
+// Inventory finding the VM.
+type vmInventory struct {
+	web.Client
+	vm *model.VM
+}
+
+func (r *vmInventory) Find(resource interface{}, ref ref.Ref) error {
+	*resource.(*model.VM) = *r.vm
+	return nil
+}
+
+func TestMetadata(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &model.VM{}
+	vm.Annotation = "Database server."
+	vm.Tags = []vsphere.Tag{
+		{ID: "t1", Name: "prod", Category: "env"},
+		{ID: "t2", Name: "db", Category: "env"},
+		{ID: "t3", Name: "gold", Category: "tier"},
+	}
+	vm.CustomAttributes = []vsphere.CustomAttribute{
+		{Key: 1, Name: "owner", Value: "dba"},
+		{Key: 2, Name: "notes", Value: "not a label value"},
+	}
+	p := &api.Plan{}
+	p.Spec.MetadataMapping = []plan.MetadataMapping{
+		{Source: plan.MetadataTag, Name: "env", Label: "env", Annotation: "example.com/env"},
+		{Source: plan.MetadataTag, Name: "tier", Label: "tier"},
+		{Source: plan.MetadataTag, Name: "missing", Label: "missing"},
+		{Source: plan.MetadataAttribute, Name: "owner", Label: "owner"},
+		{Source: plan.MetadataAttribute, Name: "notes", Label: "notes", Annotation: "example.com/notes"},
+		{Source: plan.MetadataAnnotation, Annotation: "example.com/description"},
+	}
+	ctx := &plancontext.Context{
+		Plan: p,
+		Log:  logging.WithName("test"),
+	}
+	ctx.Source.Inventory = &vmInventory{vm: vm}
+	builder := &Builder{Context: ctx}
+
+	labels, annotations, err := builder.Metadata(ref.Ref{ID: "vm-1"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(labels).To(gomega.Equal(map[string]string{
+		"env":   "db_prod",
+		"tier":  "gold",
+		"owner": "dba",
+	}))
+	g.Expect(annotations).To(gomega.Equal(map[string]string{
+		"example.com/env":         "db,prod",
+		"example.com/notes":       "not a label value",
+		"example.com/description": "Database server.",
+	}))
+
+	// Not mapped.
+	p.Spec.MetadataMapping = nil
+	labels, annotations, err = builder.Metadata(ref.Ref{ID: "vm-1"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(labels).To(gomega.BeEmpty())
+	g.Expect(annotations).To(gomega.BeEmpty())
+}
+
+func TestNvramURL(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &model.VM{}
+	vm.ConfigFile = "[ds 1] vm/vm.vmx"
+
+	// Default.
+	u, found := nvramURL(vm, "vcenter.example.com", "dc")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(u.String()).To(gomega.Equal(
+		"https://vcenter.example.com/folder/vm/vm.nvram?dcPath=dc&dsName=ds+1"))
+
+	// Relative.
+	vm.NVRAM = "efi.nvram"
+	u, found = nvramURL(vm, "esx.example.com", HostDatacenter)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(u.String()).To(gomega.Equal(
+		"https://esx.example.com/folder/vm/efi.nvram?dcPath=ha-datacenter&dsName=ds+1"))
+
+	// Datastore path.
+	vm.NVRAM = "[ds2] nvram/vm.nvram"
+	u, found = nvramURL(vm, "vcenter.example.com", "dc")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(u.String()).To(gomega.Equal(
+		"https://vcenter.example.com/folder/nvram/vm.nvram?dcPath=dc&dsName=ds2"))
+
+	// Not found.
+	vm.NVRAM = "/vmfs/volumes/ds/vm.nvram"
+	_, found = nvramURL(vm, "vcenter.example.com", "dc")
+	g.Expect(found).To(gomega.BeFalse())
+	vm.ConfigFile = ""
+	vm.NVRAM = ""
+	_, found = nvramURL(vm, "vcenter.example.com", "dc")
+	g.Expect(found).To(gomega.BeFalse())
+}
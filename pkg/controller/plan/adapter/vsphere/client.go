@@ -15,6 +15,7 @@ import (
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
@@ -122,6 +123,70 @@ func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, data
 	return
 }
 
+// Measure the data (MB) changed between the previous and the latest precopy
+// using changed block tracking.
+func (r *Client) PrecopyDelta(vmRef ref.Ref, precopies []planapi.Precopy) (delta int64, err error) {
+	n := len(precopies)
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	previous, err := r.snapshotDisks(vm, precopies[n-2].Snapshot)
+	if err != nil {
+		return
+	}
+	changeIds := make(map[int32]string)
+	for _, disk := range previous {
+		changeIds[disk.Key] = changeId(disk)
+	}
+	current, err := r.snapshotDisks(vm, precopies[n-1].Snapshot)
+	if err != nil {
+		return
+	}
+	snapshot := types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: precopies[n-1].Snapshot}
+	var size int64
+	for _, disk := range current {
+		id := changeIds[disk.Key]
+		if id == "" {
+			continue
+		}
+		var offset int64
+		for offset < disk.CapacityInBytes {
+			var res *types.QueryChangedDiskAreasResponse
+			res, err = methods.QueryChangedDiskAreas(
+				context.TODO(),
+				r.client.Client,
+				&types.QueryChangedDiskAreas{
+					This:        vm.Reference(),
+					Snapshot:    &snapshot,
+					DeviceKey:   disk.Key,
+					StartOffset: offset,
+					ChangeId:    id,
+				})
+			if err != nil {
+				err = liberr.Wrap(
+					err,
+					"Unable to query changed disk areas.",
+					"vm",
+					vm.Reference().Value,
+					"disk",
+					disk.Key)
+				return
+			}
+			for _, area := range res.Returnval.ChangedArea {
+				size += area.Length
+			}
+			if res.Returnval.Length == 0 {
+				break
+			}
+			offset = res.Returnval.StartOffset + res.Returnval.Length
+		}
+	}
+	delta = size / (1024 * 1024)
+
+	return
+}
+
 // Get the power state of the VM.
 func (r *Client) PowerState(vmRef ref.Ref) (state string, err error) {
 	vm, err := r.getVM(vmRef)
@@ -257,6 +322,47 @@ func (r *Client) getChangeIds(vmRef ref.Ref, snapshotId string) (changeIdMapping
 	return
 }
 
+// Get the disks in a VM snapshot.
+func (r *Client) snapshotDisks(vm *object.VirtualMachine, snapshotId string) (disks []*types.VirtualDisk, err error) {
+	var snapshot mo.VirtualMachineSnapshot
+	err = vm.Properties(
+		context.TODO(),
+		types.ManagedObjectReference{Type: "VirtualMachineSnapshot", Value: snapshotId},
+		[]string{"config.hardware.device"},
+		&snapshot)
+	if err != nil {
+		err = liberr.Wrap(err,
+			"Unable to get snapshot properties.",
+			"vm",
+			vm.Reference().Value,
+			"snapshot",
+			snapshotId)
+		return
+	}
+	for _, device := range snapshot.Config.Hardware.Device {
+		if disk, cast := device.(*types.VirtualDisk); cast {
+			disks = append(disks, disk)
+		}
+	}
+
+	return
+}
+
+// Get the change ID of the disk backing.
+func changeId(disk *types.VirtualDisk) (id string) {
+	switch backing := disk.Backing.(type) {
+	case *types.VirtualDiskFlatVer2BackingInfo:
+		id = backing.ChangeId
+	case *types.VirtualDiskSparseVer2BackingInfo:
+		id = backing.ChangeId
+	case *types.VirtualDiskRawDiskMappingVer1BackingInfo:
+		id = backing.ChangeId
+	case *types.VirtualDiskRawDiskVer2BackingInfo:
+		id = backing.ChangeId
+	}
+	return
+}
+
 // Get the VM by ref.
 func (r *Client) getVM(vmRef ref.Ref) (vsphereVm *object.VirtualMachine, err error) {
 	vm := &model.VM{}
@@ -0,0 +1,393 @@
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	container "github.com/konveyor/forklift-controller/pkg/controller/provider/container/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Autostart and HA settings.
+const (
+	RestartPriorityDisabled = "disabled"
+	StartActionNone         = "none"
+)
+
+// The VM autostart and HA settings replaced
+// when autostart is disabled.
+type Autostart struct {
+	// HA enabled on the cluster.
+	HA bool `json:"ha,omitempty"`
+	// The cluster had an HA override for the VM.
+	Override bool `json:"override,omitempty"`
+	// Overridden HA restart priority.
+	RestartPriority string `json:"restartPriority,omitempty"`
+	// Host autostart power info.
+	PowerInfo *types.AutoStartPowerInfo `json:"powerInfo,omitempty"`
+}
+
+// Perform a decommission action on the source VM.
+func (r *Client) Decommission(vmRef ref.Ref, action planapi.DecommissionAction) (undo string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	ctx := context.TODO()
+	mVM := mo.VirtualMachine{}
+	err = vm.Properties(
+		ctx,
+		vm.Reference(),
+		[]string{"name", "parent", "config.annotation", "runtime.host"},
+		&mVM)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch action.Action {
+	case planapi.DecommissionRename:
+		undo = mVM.Name
+		err = r.wait(vm.Rename(ctx, mVM.Name+action.Value))
+	case planapi.DecommissionAnnotate:
+		if mVM.Config != nil {
+			undo = mVM.Config.Annotation
+		}
+		value := action.Value
+		if undo != "" {
+			value = undo + "\n" + value
+		}
+		err = r.wait(vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{Annotation: value}))
+	case planapi.DecommissionMove:
+		if mVM.Parent == nil {
+			err = liberr.New("VM folder not found.", "vm", vmRef.String())
+			return
+		}
+		undo = mVM.Parent.Value
+		err = r.move(vm, action.Value)
+	case planapi.DecommissionTag:
+		undo, err = r.tag(vm, action.Value)
+	case planapi.DecommissionDisableAutostart:
+		undo, err = r.disableAutostart(vm, &mVM)
+	case planapi.DecommissionDelete:
+		err = r.wait(vm.Destroy(ctx))
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+	}
+
+	return
+}
+
+// Undo a decommission action performed on the source VM.
+func (r *Client) UndoDecommission(vmRef ref.Ref, action planapi.DecommissionAction) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	ctx := context.TODO()
+	switch action.Action {
+	case planapi.DecommissionRename:
+		err = r.wait(vm.Rename(ctx, action.Undo))
+	case planapi.DecommissionAnnotate:
+		if action.Undo == "" {
+			err = r.clearAnnotation(vm)
+		} else {
+			err = r.wait(vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{Annotation: action.Undo}))
+		}
+	case planapi.DecommissionMove:
+		folder := object.NewFolder(
+			r.client.Client,
+			types.ManagedObjectReference{Type: "Folder", Value: action.Undo})
+		err = r.wait(folder.MoveInto(ctx, []types.ManagedObjectReference{vm.Reference()}))
+	case planapi.DecommissionTag:
+		err = r.untag(vm, action.Undo)
+	case planapi.DecommissionDisableAutostart:
+		err = r.restoreAutostart(vm, action.Undo)
+	default:
+		err = base.DecommissionNotSupportedError{Action: action.Action}
+	}
+
+	return
+}
+
+// Wait for the task to complete.
+func (r *Client) wait(task *object.Task, err error) error {
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	err = task.Wait(context.TODO())
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	return nil
+}
+
+// Move the VM into the folder found by inventory path.
+func (r *Client) move(vm *object.VirtualMachine, path string) (err error) {
+	ctx := context.TODO()
+	searchIndex := object.NewSearchIndex(r.client.Client)
+	found, err := searchIndex.FindByInventoryPath(ctx, path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	folder, cast := found.(*object.Folder)
+	if !cast {
+		err = liberr.New("Folder not found.", "path", path)
+		return
+	}
+	err = r.wait(folder.MoveInto(ctx, []types.ManagedObjectReference{vm.Reference()}))
+
+	return
+}
+
+// Disable HA restart and host autostart for the VM.
+// Returns the (json) replaced settings.
+func (r *Client) disableAutostart(vm *object.VirtualMachine, mVM *mo.VirtualMachine) (undo string, err error) {
+	if mVM.Runtime.Host == nil {
+		err = liberr.New("VM host not found.", "vm", mVM.Name)
+		return
+	}
+	ctx := context.TODO()
+	pc := property.DefaultCollector(r.client.Client)
+	host := mo.HostSystem{}
+	err = pc.RetrieveOne(
+		ctx,
+		*mVM.Runtime.Host,
+		[]string{"parent", "configManager.autoStartManager"},
+		&host)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	replaced := Autostart{}
+	if host.Parent != nil && host.Parent.Type == "ClusterComputeResource" {
+		cluster := mo.ClusterComputeResource{}
+		err = pc.RetrieveOne(ctx, *host.Parent, []string{"configurationEx"}, &cluster)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		config, cast := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx)
+		if cast && config.DasConfig.Enabled != nil && *config.DasConfig.Enabled {
+			replaced.HA = true
+			for _, override := range config.DasVmConfig {
+				if override.Key == vm.Reference() {
+					replaced.Override = true
+					if override.DasSettings != nil {
+						replaced.RestartPriority = override.DasSettings.RestartPriority
+					}
+					break
+				}
+			}
+			operation := types.ArrayUpdateOperationAdd
+			if replaced.Override {
+				operation = types.ArrayUpdateOperationEdit
+			}
+			err = r.setRestartPriority(vm, *host.Parent, operation, RestartPriorityDisabled)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if host.ConfigManager.AutoStartManager != nil {
+		manager := mo.HostAutoStartManager{}
+		err = pc.RetrieveOne(ctx, *host.ConfigManager.AutoStartManager, []string{"config"}, &manager)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, info := range manager.Config.PowerInfo {
+			if info.Key == vm.Reference() && info.StartAction != StartActionNone {
+				replaced.PowerInfo = &info
+				disabled := info
+				disabled.StartAction = StartActionNone
+				err = r.setAutostart(*host.ConfigManager.AutoStartManager, disabled)
+				if err != nil {
+					return
+				}
+				break
+			}
+		}
+	}
+	b, err := json.Marshal(replaced)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	undo = string(b)
+
+	return
+}
+
+// Restore the HA restart and host autostart settings.
+func (r *Client) restoreAutostart(vm *object.VirtualMachine, undo string) (err error) {
+	replaced := Autostart{}
+	err = json.Unmarshal([]byte(undo), &replaced)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx := context.TODO()
+	mVM := mo.VirtualMachine{}
+	err = vm.Properties(ctx, vm.Reference(), []string{"runtime.host"}, &mVM)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if mVM.Runtime.Host == nil {
+		err = liberr.New("VM host not found.", "vm", vm.Reference().Value)
+		return
+	}
+	pc := property.DefaultCollector(r.client.Client)
+	host := mo.HostSystem{}
+	err = pc.RetrieveOne(
+		ctx,
+		*mVM.Runtime.Host,
+		[]string{"parent", "configManager.autoStartManager"},
+		&host)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if replaced.HA && host.Parent != nil && host.Parent.Type == "ClusterComputeResource" {
+		if replaced.Override {
+			err = r.setRestartPriority(vm, *host.Parent, types.ArrayUpdateOperationEdit, replaced.RestartPriority)
+		} else {
+			err = r.setRestartPriority(vm, *host.Parent, types.ArrayUpdateOperationRemove, "")
+		}
+		if err != nil {
+			return
+		}
+	}
+	if replaced.PowerInfo != nil && host.ConfigManager.AutoStartManager != nil {
+		err = r.setAutostart(*host.ConfigManager.AutoStartManager, *replaced.PowerInfo)
+	}
+
+	return
+}
+
+// Add, edit or remove the cluster HA override for the VM.
+func (r *Client) setRestartPriority(vm *object.VirtualMachine, cluster types.ManagedObjectReference, operation types.ArrayUpdateOperation, priority string) error {
+	spec := types.ClusterDasVmConfigSpec{
+		ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: operation},
+	}
+	if operation == types.ArrayUpdateOperationRemove {
+		spec.RemoveKey = vm.Reference()
+	} else {
+		spec.Info = &types.ClusterDasVmConfigInfo{
+			Key: vm.Reference(),
+			DasSettings: &types.ClusterDasVmSettings{
+				RestartPriority: priority,
+			},
+		}
+	}
+	compute := object.NewComputeResource(r.client.Client, cluster)
+	return r.wait(
+		compute.Reconfigure(
+			context.TODO(),
+			&types.ClusterConfigSpecEx{
+				DasVmConfigSpec: []types.ClusterDasVmConfigSpec{spec},
+			},
+			true))
+}
+
+// Update the host autostart power info for the VM.
+func (r *Client) setAutostart(manager types.ManagedObjectReference, info types.AutoStartPowerInfo) (err error) {
+	_, err = methods.ReconfigureAutostart(
+		context.TODO(),
+		r.client.Client,
+		&types.ReconfigureAutostart{
+			This: manager,
+			Spec: types.HostAutoStartManagerConfig{
+				PowerInfo: []types.AutoStartPowerInfo{info},
+			},
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Attach the tag to the VM using the vAPI.
+// The tag is specified as `category:name` or `name`.
+// Returns the tag ID.
+func (r *Client) tag(vm *object.VirtualMachine, tag string) (id string, err error) {
+	ctx := context.TODO()
+	tagging, err := container.NewTagging(ctx, r.client.Client, r.user(), r.password())
+	if err != nil {
+		return
+	}
+	defer tagging.Close()
+	id, err = tagging.FindTag(ctx, tag)
+	if err != nil {
+		return
+	}
+	err = tagging.Attach(ctx, id, vm.Reference())
+
+	return
+}
+
+// Detach the tag from the VM using the vAPI.
+func (r *Client) untag(vm *object.VirtualMachine, id string) (err error) {
+	ctx := context.TODO()
+	tagging, err := container.NewTagging(ctx, r.client.Client, r.user(), r.password())
+	if err != nil {
+		return
+	}
+	defer tagging.Close()
+	err = tagging.Detach(ctx, id, vm.Reference())
+
+	return
+}
+
+// ReconfigVM_Task request body used to clear the annotation.
+// The annotation is omitted from the (generated) config spec
+// when empty so the spec cannot be used to clear it.
+type clearAnnotationBody struct {
+	Req    *clearAnnotationRequest        `xml:"urn:vim25 ReconfigVM_Task,omitempty"`
+	Res    *types.ReconfigVM_TaskResponse `xml:"ReconfigVM_TaskResponse,omitempty"`
+	Fault_ *soap.Fault                    `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *clearAnnotationBody) Fault() *soap.Fault { return b.Fault_ }
+
+// ReconfigVM_Task request with an (empty) annotation.
+type clearAnnotationRequest struct {
+	This types.ManagedObjectReference `xml:"_this"`
+	Spec struct {
+		Annotation string `xml:"annotation"`
+	} `xml:"spec"`
+}
+
+// Clear the VM annotation.
+func (r *Client) clearAnnotation(vm *object.VirtualMachine) (err error) {
+	ctx := context.TODO()
+	request := clearAnnotationBody{
+		Req: &clearAnnotationRequest{
+			This: vm.Reference(),
+		},
+	}
+	response := clearAnnotationBody{}
+	err = r.client.Client.RoundTrip(ctx, &request, &response)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if response.Res == nil {
+		err = liberr.New("Reconfigure task not returned.", "vm", vm.Reference().Value)
+		return
+	}
+	err = r.wait(object.NewTask(r.client.Client, response.Res.Returnval), nil)
+
+	return
+}
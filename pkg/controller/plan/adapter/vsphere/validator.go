@@ -1,8 +1,12 @@
 package vsphere
 
 import (
+	"strings"
+
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -79,7 +83,7 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 			return
 		}
 		for _, nic := range vm.NICs {
-			if nic.Network.ID == network.ID && mapped.Destination.Type == Pod {
+			if nic.Network.ID == network.ID && mapped.Destination.Type == api.PodNetwork {
 				podMapped++
 			}
 		}
@@ -105,7 +109,14 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
+	luns := r.luns()
 	for _, disk := range vm.Disks {
+		// LUNs mapped to persistent volumes are not copied.
+		if disk.RDM {
+			if _, found := luns.FindVolume(disk.LUN); found {
+				continue
+			}
+		}
 		if !r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: disk.Datastore.ID}) {
 			return
 		}
@@ -114,6 +125,93 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's RDM disks are copied or
+// mapped to persistent volumes.
+func (r *Validator) LUNs(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	luns := r.luns()
+	for _, disk := range vm.Disks {
+		if !disk.RDM {
+			continue
+		}
+		if _, found := luns.FindVolume(disk.LUN); found {
+			continue
+		}
+		// copied through the datastore (descriptor).
+		if !luns.Copy || disk.Datastore.ID == "" {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that a VM's disks are not encrypted by vSphere
+// VM encryption. VDDK cannot read the encrypted disks.
+func (r *Validator) EncryptedDisks(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		if disk.VMEncrypted {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that a VM's CD-ROMs have a mapped ISO
+// when the CD-ROMs are preserved.
+func (r *Validator) CDROMs(vmRef ref.Ref) (ok bool, err error) {
+	devices := r.plan.Spec.Devices
+	if devices == nil || !devices.CDROMs {
+		ok = true
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, cdrom := range vm.CDROMs {
+		if _, found := devices.FindISO(cdrom.ISO); !found {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// RDM disk handling.
+func (r *Validator) luns() (luns planapi.LUNs) {
+	if r.plan.Spec.LUNs != nil {
+		luns = *r.plan.Spec.LUNs
+	}
+	return
+}
+
 // Validate that a VM's Host isn't in maintenance mode.
 func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 	vm := &model.VM{}
@@ -144,3 +242,229 @@ func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 	ok = !host.InMaintenanceMode
 	return
 }
+
+// Validate that a VM is within the inventory scope.
+// Contained in a listed folder or on a host within
+// a listed cluster.
+func (r *Validator) Scoped(vmRef ref.Ref, scope *api.ProviderScope) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	if scope.HasFolder(vm.Path) {
+		ok = true
+		return
+	}
+	host := &model.Host{}
+	err = r.inventory.Get(host, vm.Host)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Host not found in inventory.",
+			"host",
+			vm.Host)
+		return
+	}
+	cluster := &model.Cluster{}
+	err = r.inventory.Get(cluster, host.Cluster)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Cluster not found in inventory.",
+			"cluster",
+			host.Cluster)
+		return
+	}
+	ok = scope.HasCluster(cluster.ID, cluster.Name)
+
+	return
+}
+
+// Select the VMs matching the selector.
+func (r *Validator) SelectVMs(selector *planapi.VMSelector) (refs []ref.Ref, err error) {
+	if selector.Project != "" {
+		err = liberr.Wrap(base.SelectorNotSupportedError{Criteria: "project"})
+		return
+	}
+	var hosts map[string]bool
+	if selector.Cluster != "" {
+		hosts, err = r.clusterHosts(selector.Cluster)
+		if err != nil {
+			return
+		}
+	}
+	folder := strings.TrimSuffix(selector.Folder, "/") + "/"
+	list := []model.VM{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, vm := range list {
+		if vm.IsTemplate {
+			continue
+		}
+		if selector.Folder != "" && !strings.HasPrefix(vm.Path, folder) {
+			continue
+		}
+		if hosts != nil && !hosts[vm.Host] {
+			continue
+		}
+		if !r.hasTags(&vm, selector.Tags) || !r.hasAttributes(&vm, selector.Labels) {
+			continue
+		}
+		if selector.ExcludeCritical && base.HasCriticalConcern(vm.Concerns) {
+			continue
+		}
+		matched, mErr := selector.MatchName(vm.Name)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		if matched {
+			refs = append(refs, ref.Ref{ID: vm.ID, Name: vm.Name})
+		}
+	}
+
+	return
+}
+
+// Find the hosts in the cluster (name, path or ID).
+func (r *Validator) clusterHosts(cluster string) (hosts map[string]bool, err error) {
+	list := []model.Cluster{}
+	err = r.inventory.List(
+		&list,
+		web.Param{
+			Key:   model.DetailParam,
+			Value: "all",
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, c := range list {
+		if c.ID == cluster || c.Name == cluster || c.Path == cluster {
+			hosts = map[string]bool{}
+			for _, host := range c.Hosts {
+				hosts[host.ID] = true
+			}
+			return
+		}
+	}
+	err = liberr.Wrap(web.NotFoundError{Ref: ref.Ref{Name: cluster}})
+	return
+}
+
+// Determine whether the VM has all of the tags.
+// The tag may be qualified by category as `category:name`.
+func (r *Validator) hasTags(vm *model.VM, tags []string) bool {
+next:
+	for _, wanted := range tags {
+		for _, tag := range vm.Tags {
+			if wanted == tag.Name || wanted == tag.Category+":"+tag.Name {
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Determine whether the VM has all of the custom attributes.
+func (r *Validator) hasAttributes(vm *model.VM, attributes map[string]string) bool {
+next:
+	for name, value := range attributes {
+		for _, attribute := range vm.CustomAttributes {
+			if attribute.Name == name && attribute.Value == value {
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Return the destination resources requested by a VM.
+// The disks are sized as the builder sizes the DataVolumes.
+func (r *Validator) Requests(vmRef ref.Ref) (requests base.Requests, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	requests = base.Requests{
+		CPU:    int64(vm.CpuCount),
+		Memory: int64(vm.MemoryMB) * 1024 * 1024,
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	luns := planapi.LUNs{}
+	if r.plan.Spec.LUNs != nil {
+		luns = *r.plan.Spec.LUNs
+	}
+	disks, err := mappedDisks(r.inventory, r.plan.Referenced.Map.Storage.Spec.Map, luns, vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, disk := range disks {
+		requests.Disks = append(
+			requests.Disks,
+			base.DiskRequest{
+				StorageClass: disk.StorageClass,
+				Size:         disk.Size,
+			})
+	}
+
+	return
+}
+
+// Return the concerns reported for a VM.
+func (r *Validator) Concerns(vmRef ref.Ref) (concerns []planapi.Concern, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	concerns = base.Concerns(vm.Concerns)
+
+	return
+}
+
+// Return the firmware state to be persisted for a VM.
+func (r *Validator) PersistentState(vmRef ref.Ref) (state base.PersistentState, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	state = persistentState(vm)
+	return
+}
@@ -0,0 +1,45 @@
+package vsphere
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+)
+
+func TestValidateCDROMs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &model.VM{}
+	vm.CDROMs = []vsphere.CDROM{
+		{Key: 3000, ISO: "[ds] iso/rhel.iso"},
+		{Key: 3001},
+	}
+	p := &api.Plan{}
+	validator := &Validator{plan: p, inventory: &vmInventory{vm: vm}}
+
+	// Not preserved.
+	ok, err := validator.CDROMs(ref.Ref{ID: "vm-1"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeTrue())
+
+	// Not mapped.
+	p.Spec.Devices = &plan.Devices{
+		CDROMs: true,
+		ISOs: []plan.ISOMapping{
+			{Source: "rhel.iso", ClaimName: "rhel-iso"},
+		},
+	}
+	ok, err = validator.CDROMs(ref.Ref{ID: "vm-1"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeFalse())
+
+	// Mapped.
+	vm.CDROMs = vm.CDROMs[:1]
+	ok, err = validator.CDROMs(ref.Ref{ID: "vm-1"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeTrue())
+}
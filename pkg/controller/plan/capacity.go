@@ -0,0 +1,340 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Resources counted by quotas.
+const (
+	// Storage class scoped resource (prefix).
+	StorageClassResource = ".storageclass.storage.k8s.io/"
+	// VirtualMachine count.
+	VMCountResource = "count/virtualmachines.kubevirt.io"
+)
+
+// KubeVirt requests 1/CPUAllocationRatio of a CPU per vCPU.
+const (
+	CPUAllocationRatio = 10
+)
+
+// Destination resources demanded by the plan.
+type Demand struct {
+	// Aggregated resources.
+	Total core.ResourceList
+	// Storage (bytes) keyed by storage class.
+	Storage map[string]int64
+	// VM requests keyed by VM.
+	VMs map[string]adapter.Requests
+}
+
+// Add the resources requested by a VM.
+func (r *Demand) Add(vm string, requests adapter.Requests) {
+	if r.Total == nil {
+		r.Total = core.ResourceList{}
+		r.Storage = map[string]int64{}
+		r.VMs = map[string]adapter.Requests{}
+	}
+	r.VMs[vm] = requests
+	add := func(name core.ResourceName, q *resource.Quantity) {
+		total := r.Total[name]
+		total.Add(*q)
+		r.Total[name] = total
+	}
+	add(VMCountResource, resource.NewQuantity(1, resource.DecimalSI))
+	cpu := resource.NewMilliQuantity(requests.CPU*1000/CPUAllocationRatio, resource.DecimalSI)
+	add(core.ResourceCPU, cpu)
+	add(core.ResourceRequestsCPU, cpu)
+	memory := resource.NewQuantity(requests.Memory, resource.BinarySI)
+	add(core.ResourceMemory, memory)
+	add(core.ResourceRequestsMemory, memory)
+	for _, disk := range requests.Disks {
+		size := resource.NewQuantity(disk.Size, resource.BinarySI)
+		count := resource.NewQuantity(1, resource.DecimalSI)
+		add(core.ResourceRequestsStorage, size)
+		add(core.ResourcePersistentVolumeClaims, count)
+		add(core.ResourceName(disk.StorageClass+StorageClassResource+string(core.ResourceRequestsStorage)), size)
+		add(core.ResourceName(disk.StorageClass+StorageClassResource+string(core.ResourcePersistentVolumeClaims)), count)
+		r.Storage[disk.StorageClass] += disk.Size
+	}
+}
+
+// Find the quota resources with less remaining than demanded.
+func (r *Demand) QuotaExceeded(quotas []core.ResourceQuota) (items []string) {
+	for _, quota := range quotas {
+		hard := quota.Status.Hard
+		if len(hard) == 0 {
+			hard = quota.Spec.Hard
+		}
+		for name, limit := range hard {
+			demanded, found := r.Total[name]
+			if !found {
+				continue
+			}
+			remaining := limit.DeepCopy()
+			if used, found := quota.Status.Used[name]; found {
+				remaining.Sub(used)
+			}
+			if demanded.Cmp(remaining) > 0 {
+				items = append(
+					items,
+					fmt.Sprintf(
+						"%s: %s requested, %s available (quota: %s).",
+						name,
+						demanded.String(),
+						remaining.String(),
+						quota.Name))
+			}
+		}
+	}
+	sort.Strings(items)
+	return
+}
+
+// Find the VM resources outside of the limit ranges.
+func (r *Demand) LimitExceeded(ranges []core.LimitRange) (items []string) {
+	vms := []string{}
+	for vm := range r.VMs {
+		vms = append(vms, vm)
+	}
+	sort.Strings(vms)
+	for _, lr := range ranges {
+		for _, limit := range lr.Spec.Limits {
+			for _, vm := range vms {
+				requests := r.VMs[vm]
+				switch limit.Type {
+				case core.LimitTypePersistentVolumeClaim:
+					for _, disk := range requests.Disks {
+						size := resource.NewQuantity(disk.Size, resource.BinarySI)
+						if max, found := limit.Max[core.ResourceStorage]; found && size.Cmp(max) > 0 {
+							items = append(
+								items,
+								fmt.Sprintf(
+									"VM %s disk size %s exceeds the maximum %s (limit range: %s).",
+									vm,
+									size.String(),
+									max.String(),
+									lr.Name))
+						}
+					}
+				case core.LimitTypePod, core.LimitTypeContainer:
+					memory := resource.NewQuantity(requests.Memory, resource.BinarySI)
+					if max, found := limit.Max[core.ResourceMemory]; found && memory.Cmp(max) > 0 {
+						items = append(
+							items,
+							fmt.Sprintf(
+								"VM %s memory %s exceeds the %s maximum %s (limit range: %s).",
+								vm,
+								memory.String(),
+								strings.ToLower(string(limit.Type)),
+								max.String(),
+								lr.Name))
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Find the storage classes with less capacity
+// reported by the CSI driver than demanded.
+// Capacity is reported per topology segment and a volume is
+// provisioned within a single segment so the largest segment
+// is compared rather than the sum.
+func (r *Demand) CapacityExceeded(capacities []storage.CSIStorageCapacity) (items []string) {
+	available := map[string]*resource.Quantity{}
+	for i := range capacities {
+		capacity := &capacities[i]
+		if capacity.Capacity == nil {
+			continue
+		}
+		largest, found := available[capacity.StorageClassName]
+		if !found || capacity.Capacity.Cmp(*largest) > 0 {
+			available[capacity.StorageClassName] = capacity.Capacity
+		}
+	}
+	for class, size := range r.Storage {
+		largest, found := available[class]
+		if !found {
+			continue
+		}
+		demanded := resource.NewQuantity(size, resource.BinarySI)
+		if demanded.Cmp(*largest) > 0 {
+			items = append(
+				items,
+				fmt.Sprintf(
+					"%s: %s requested, %s available.",
+					class,
+					demanded.String(),
+					largest.String()))
+		}
+	}
+	sort.Strings(items)
+	return
+}
+
+// Validate that the destination has the quota and
+// capacity for the VMs that have not been migrated.
+func (r *Reconciler) validateCapacity(plan *api.Plan) (err error) {
+	if plan.Status.HasCondition(Executing) {
+		return
+	}
+	source := plan.Referenced.Provider.Source
+	destination := plan.Referenced.Provider.Destination
+	if source == nil || destination == nil {
+		return
+	}
+	pAdapter, err := adapter.New(source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	demand := Demand{}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		if status, found := plan.Status.Migration.FindVM(vm.Ref); found && status.HasCondition(Succeeded) {
+			continue
+		}
+		requests, rErr := validator.Requests(vm.Ref)
+		if rErr != nil {
+			if errors.As(liberr.Unwrap(rErr), &web.NotFoundError{}) {
+				continue
+			}
+			err = rErr
+			return
+		}
+		demand.Add(vm.Ref.String(), requests)
+	}
+	if len(demand.VMs) == 0 {
+		return
+	}
+	destClient, err := r.destinationClient(destination)
+	if err != nil {
+		return
+	}
+	quotas := &core.ResourceQuotaList{}
+	err = destClient.List(context.TODO(), quotas, client.InNamespace(plan.Spec.TargetNamespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ranges := &core.LimitRangeList{}
+	err = destClient.List(context.TODO(), ranges, client.InNamespace(plan.Spec.TargetNamespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	capacities, checked, err := storageCapacities(destClient)
+	if err != nil {
+		return
+	}
+	if !checked {
+		r.Log.V(1).Info(
+			"Storage capacity not checked.",
+			"plan",
+			path.Join(plan.Namespace, plan.Name))
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     StorageCapacityNotChecked,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Advisory,
+			Message:  "The storage capacity cannot be checked. The destination cluster does not serve CSIStorageCapacity (storage.k8s.io/v1beta1).",
+		})
+	}
+	if items := demand.QuotaExceeded(quotas.Items); len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     QuotaExceeded,
+			Status:   True,
+			Reason:   Exceeded,
+			Category: Critical,
+			Message:  "The target namespace quota is exceeded.",
+			Items:    items,
+		})
+	}
+	if items := demand.LimitExceeded(ranges.Items); len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     LimitRangeExceeded,
+			Status:   True,
+			Reason:   Exceeded,
+			Category: Critical,
+			Message:  "The target namespace limit ranges are exceeded.",
+			Items:    items,
+		})
+	}
+	if items := demand.CapacityExceeded(capacities); len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     StorageCapacityExceeded,
+			Status:   True,
+			Reason:   Exceeded,
+			Category: Warn,
+			Message:  "The storage capacity reported for the storage classes may be insufficient.",
+			Items:    items,
+		})
+	}
+
+	return
+}
+
+// List the storage capacities reported by the CSI drivers.
+// Not checked when the storage.k8s.io/v1beta1 API is not served
+// by the cluster (removed in Kubernetes 1.27).
+func storageCapacities(destClient client.Client) (capacities []storage.CSIStorageCapacity, checked bool, err error) {
+	list := &storage.CSIStorageCapacityList{}
+	err = destClient.List(context.TODO(), list)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	capacities = list.Items
+	checked = true
+	return
+}
+
+// Build a client for the destination cluster.
+func (r *Reconciler) destinationClient(provider *api.Provider) (destClient client.Client, err error) {
+	if provider.IsHost() {
+		destClient = r.Client
+		return
+	}
+	ref := provider.Spec.Secret
+	secret := &core.Secret{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	destClient, err = provider.Client(secret)
+
+	return
+}
@@ -0,0 +1,109 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1beta1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client of a cluster not serving the listed kinds.
+type noMatchClient struct {
+	client.Client
+}
+
+func (r *noMatchClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return &apimeta.NoKindMatchError{}
+}
+
+func TestDemand(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gi := int64(1024 * 1024 * 1024)
+	demand := Demand{}
+	demand.Add("vm-1", adapter.Requests{
+		CPU:    4,
+		Memory: 8 * gi,
+		Disks: []adapter.DiskRequest{
+			{StorageClass: "fast", Size: 100 * gi},
+			{StorageClass: "slow", Size: 500 * gi},
+		},
+	})
+	demand.Add("vm-2", adapter.Requests{
+		CPU:    2,
+		Memory: 4 * gi,
+		Disks: []adapter.DiskRequest{
+			{StorageClass: "fast", Size: 50 * gi},
+		},
+	})
+	g.Expect(demand.Storage["fast"]).To(gomega.Equal(150 * gi))
+	cpu := demand.Total[core.ResourceRequestsCPU]
+	g.Expect(cpu.String()).To(gomega.Equal("600m"))
+
+	quota := core.ResourceQuota{ObjectMeta: meta.ObjectMeta{Name: "quota"}}
+	quota.Spec.Hard = core.ResourceList{
+		core.ResourceRequestsMemory:                         resource.MustParse("16Gi"),
+		"fast.storageclass.storage.k8s.io/requests.storage": resource.MustParse("200Gi"),
+	}
+	quota.Status.Used = core.ResourceList{
+		core.ResourceRequestsMemory:                         resource.MustParse("2Gi"),
+		"fast.storageclass.storage.k8s.io/requests.storage": resource.MustParse("100Gi"),
+	}
+	items := demand.QuotaExceeded([]core.ResourceQuota{quota})
+	g.Expect(items).To(gomega.HaveLen(1))
+	g.Expect(items[0]).To(gomega.HavePrefix("fast.storageclass.storage.k8s.io/requests.storage"))
+
+	lr := core.LimitRange{ObjectMeta: meta.ObjectMeta{Name: "limits"}}
+	lr.Spec.Limits = []core.LimitRangeItem{
+		{
+			Type: core.LimitTypePersistentVolumeClaim,
+			Max:  core.ResourceList{core.ResourceStorage: resource.MustParse("200Gi")},
+		},
+		{
+			Type: core.LimitTypeContainer,
+			Max:  core.ResourceList{core.ResourceMemory: resource.MustParse("6Gi")},
+		},
+	}
+	items = demand.LimitExceeded([]core.LimitRange{lr})
+	g.Expect(items).To(gomega.HaveLen(2))
+
+	capacity := resource.MustParse("100Gi")
+	large := resource.MustParse("200Gi")
+	// Segments are not summed.
+	items = demand.CapacityExceeded([]storage.CSIStorageCapacity{
+		{StorageClassName: "fast", Capacity: &capacity},
+		{StorageClassName: "fast", Capacity: &capacity},
+	})
+	g.Expect(items).To(gomega.HaveLen(1))
+	// Largest segment.
+	items = demand.CapacityExceeded([]storage.CSIStorageCapacity{
+		{StorageClassName: "fast", Capacity: &capacity},
+		{StorageClassName: "fast", Capacity: &large},
+	})
+	g.Expect(items).To(gomega.BeEmpty())
+}
+
+func TestStorageCapacities(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	capacity := &storage.CSIStorageCapacity{
+		ObjectMeta:       meta.ObjectMeta{Namespace: "csi", Name: "fast-1"},
+		StorageClassName: "fast",
+	}
+	capacities, checked, err := storageCapacities(fakeClient(capacity))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(checked).To(gomega.BeTrue())
+	g.Expect(capacities).To(gomega.HaveLen(1))
+
+	// API not served.
+	capacities, checked, err = storageCapacities(&noMatchClient{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(checked).To(gomega.BeFalse())
+	g.Expect(capacities).To(gomega.BeEmpty())
+}
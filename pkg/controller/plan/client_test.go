@@ -0,0 +1,43 @@
+package plan
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	cnv "kubevirt.io/client-go/api/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Build a fake client with the core, forklift,
+// KubeVirt and CDI types registered.
+// Only the latest KubeVirt version is registered so
+// the version of the KubeVirt types is not ambiguous.
+func fakeClient(objects ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		scheme.AddToScheme,
+		apis.AddToScheme,
+		cdi.AddToScheme,
+	} {
+		err := add(s)
+		if err != nil {
+			panic(err)
+		}
+	}
+	s.AddKnownTypes(
+		cnv.GroupVersion,
+		&cnv.VirtualMachine{},
+		&cnv.VirtualMachineList{},
+		&cnv.VirtualMachineInstance{},
+		&cnv.VirtualMachineInstanceList{},
+		&cnv.KubeVirt{},
+		&cnv.KubeVirtList{})
+	meta.AddToGroupVersion(s, cnv.GroupVersion)
+	return fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objects...).
+		Build()
+}
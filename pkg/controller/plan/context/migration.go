@@ -10,6 +10,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -60,6 +61,8 @@ type Context struct {
 	Destination Destination
 	// Hooks.
 	Hooks []*api.Hook
+	// Event recorder.
+	Recorder record.EventRecorder
 	// Logger.
 	Log logr.Logger
 }
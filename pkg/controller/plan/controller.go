@@ -187,6 +187,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("Plan deleted.")
+			purgeRequests(request.Namespace, request.Name)
 			err = nil
 		}
 		return
@@ -238,6 +239,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	// End staging conditions.
 	plan.Status.EndStagingConditions()
 
+	// Estimate the migration duration.
+	r.estimate(plan)
+
 	// Record events.
 	r.Record(plan, plan.Status.Conditions)
 
@@ -293,11 +297,19 @@ func (r *Reconciler) archive(plan *api.Plan) {
 //  5. If a new migration is being started, update the context and snapshot.
 //  6. Run the migration.
 func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
-	if plan.Status.HasBlockerCondition() || plan.Status.HasCondition(Archived) {
+	if plan.Status.HasCondition(Archived) {
+		return
+	}
+	err = r.undecommission(plan)
+	if err != nil {
+		return
+	}
+	if plan.Status.HasBlockerCondition() {
 		return
 	}
 	defer func() {
 		if err == nil {
+			r.estimate(plan)
 			err = r.Status().Update(context.TODO(), plan)
 			if err != nil {
 				err = liberr.Wrap(err)
@@ -310,6 +322,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	if err != nil {
 		return
 	}
+	ctx.Recorder = r.EventRecorder
 	//
 	// Find and validate the current (active) migration.
 	migration, err = r.activeMigration(plan)
@@ -426,6 +439,46 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	return
 }
 
+// Undo the source VM decommission.
+// Not while a migration is executing. Performed regardless of
+// blocker conditions because the source VMs renamed or deleted
+// by the decommission are no longer found.
+func (r *Reconciler) undecommission(plan *api.Plan) (err error) {
+	if plan.Spec.Decommission == nil || !plan.Spec.Decommission.Undo {
+		return
+	}
+	snapshot := plan.Status.Migration.ActiveSnapshot()
+	if snapshot.HasCondition(Executing) && !snapshot.HasAnyCondition(Canceled, Failed, Succeeded) {
+		return
+	}
+	pending := false
+	for _, vm := range plan.Status.Migration.VMs {
+		if undoable(vm) {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		return
+	}
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		return
+	}
+	ctx.Recorder = r.EventRecorder
+	runner := Migration{Context: ctx}
+	err = runner.Undecommission()
+	if err != nil {
+		return
+	}
+	err = r.Status().Update(context.TODO(), plan)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
 // Create a new snapshot.
 // Return: The new active snapshot.
 func (r *Reconciler) newSnapshot(ctx *plancontext.Context) *planapi.Snapshot {
@@ -0,0 +1,155 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/client-go/api/v1"
+)
+
+func TestUnsafeOptions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(unsafeOptions([]string{
+		"--root=/dev/sda2",
+		"--mac", "00:11:22:33:44:55:network:default",
+		"-n", "in:out",
+		"--compressed",
+	})).To(gomega.BeEmpty())
+	g.Expect(unsafeOptions([]string{
+		"-o", "local",
+		"--root",
+	})).To(gomega.Equal([]string{"-o", "local", "--root"}))
+	g.Expect(unsafeOptions([]string{
+		"--compressed=true",
+		"--root=first\n-o",
+	})).To(gomega.Equal([]string{"--compressed=true", "--root=first\n-o"}))
+}
+
+func TestValidateConversion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	permitted := Settings.Migration.VirtV2vPermittedImages
+	defer func() {
+		Settings.Migration.VirtV2vPermittedImages = permitted
+	}()
+	Settings.Migration.VirtV2vPermittedImages = map[string]bool{
+		"quay.io/example/virt-v2v:custom": true,
+	}
+	r := Reconciler{}
+	size := resource.MustParse("20Gi")
+	p := &api.Plan{}
+	p.Spec.Conversion = &plan.Conversion{
+		Image:   "quay.io/example/virt-v2v:custom",
+		Options: []string{"--root=first"},
+	}
+	p.Spec.VMs = []plan.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			Conversion: &plan.Conversion{
+				Options: []string{"--root=/dev/sda2"},
+				Scratch: &plan.ScratchVolume{Size: &size, StorageClass: "fast"},
+			},
+		},
+	}
+	r.validateConversion(p)
+	g.Expect(p.Status.HasCondition(ConversionNotValid)).To(gomega.BeFalse())
+
+	// VM configuration overrides the plan configuration.
+	conversion := p.Spec.FindConversion(ref.Ref{ID: "vm-1"})
+	g.Expect(conversion.Image).To(gomega.Equal("quay.io/example/virt-v2v:custom"))
+	g.Expect(conversion.Options).To(gomega.Equal([]string{"--root=/dev/sda2"}))
+	g.Expect(conversion.Scratch.StorageClass).To(gomega.Equal("fast"))
+
+	// Unsafe options and scratch PVC without size.
+	p.Spec.Conversion.Options = []string{"-os", "/tmp"}
+	p.Spec.VMs[0].Conversion.Scratch.Size = nil
+	r.validateConversion(p)
+	cnd := p.Status.FindCondition(ConversionNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Items).To(gomega.Equal([]string{"-os", "/tmp", "scratch"}))
+
+	// Image not permitted.
+	p.Status.DeleteCondition(ConversionNotValid)
+	p.Spec.Conversion.Options = nil
+	p.Spec.VMs[0].Conversion.Scratch.Size = &size
+	p.Spec.VMs[0].Conversion.Image = "quay.io/example/virt-v2v:other"
+	r.validateConversion(p)
+	cnd = p.Status.FindCondition(ConversionNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Items).To(gomega.Equal([]string{"quay.io/example/virt-v2v:other"}))
+}
+
+func TestConversionPodImage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	permitted := Settings.Migration.VirtV2vPermittedImages
+	image := Settings.Migration.VirtV2vImageCold
+	defer func() {
+		Settings.Migration.VirtV2vPermittedImages = permitted
+		Settings.Migration.VirtV2vImageCold = image
+	}()
+	Settings.Migration.VirtV2vPermittedImages = map[string]bool{
+		"quay.io/example/virt-v2v:custom": true,
+	}
+	Settings.Migration.VirtV2vImageCold = "quay.io/kubev2v/forklift-virt-v2v:latest"
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.VMs = []plan.VM{
+		{
+			Ref:        ref.Ref{ID: "vm-1"},
+			Conversion: &plan.Conversion{Image: "quay.io/example/virt-v2v:custom"},
+		},
+		{
+			Ref:        ref.Ref{ID: "vm-2"},
+			Conversion: &plan.Conversion{Image: "quay.io/example/virt-v2v:other"},
+		},
+	}
+	vm := &plan.VMStatus{}
+
+	// Permitted.
+	vm.ID = "vm-1"
+	pod, err := conversionPod(p, vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(pod.Spec.Containers[0].Image).To(gomega.Equal("quay.io/example/virt-v2v:custom"))
+
+	// Not permitted.
+	vm.ID = "vm-2"
+	pod, err = conversionPod(p, vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(pod.Spec.Containers[0].Image).To(gomega.Equal("quay.io/kubev2v/forklift-virt-v2v:latest"))
+}
+
+func TestOptionsConfigMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Name = "plan"
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Conversion = &plan.Conversion{
+		Options: []string{"--root", "/dev/sda2"},
+	}
+	p.Spec.VMs = []plan.VM{{Ref: ref.Ref{ID: "vm-1"}}}
+	kubevirt := conversionKubeVirt(p)
+	vmCr := &VirtualMachine{
+		VirtualMachine: &cnv.VirtualMachine{
+			Spec: cnv.VirtualMachineSpec{
+				Template: &cnv.VirtualMachineInstanceTemplateSpec{
+					Spec: cnv.VirtualMachineInstanceSpec{
+						Domain: cnv.DomainSpec{CPU: &cnv.CPU{Sockets: 1, Cores: 1}},
+					},
+				},
+			},
+		},
+	}
+	configMap, err := kubevirt.ensureLibvirtConfigMap(ref.Ref{ID: "vm-1"}, vmCr, &[]core.PersistentVolumeClaim{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(configMap.BinaryData["options"])).To(gomega.Equal("--root\n/dev/sda2\n"))
+
+	// Options removed.
+	p.Spec.Conversion = nil
+	configMap, err = kubevirt.ensureLibvirtConfigMap(ref.Ref{ID: "vm-1"}, vmCr, &[]core.PersistentVolumeClaim{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(configMap.BinaryData).ToNot(gomega.HaveKey("options"))
+}
@@ -0,0 +1,200 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+)
+
+func TestValidateCustomization(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fakeClient(
+				&core.ConfigMap{
+					ObjectMeta: meta.ObjectMeta{Namespace: "target", Name: "fixup"},
+					Data: map[string]string{
+						"remove-tools.sh": "#!/bin/sh",
+						"fstab":           "",
+					},
+				}),
+		},
+	}
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Customization = []plan.Customization{
+		{
+			ConfigMap: core.LocalObjectReference{Name: "fixup"},
+			Run:       []string{"remove-tools.sh"},
+			Install:   []string{"qemu-guest-agent", "cloud-init"},
+			Upload:    []plan.Upload{{Key: "fstab", Path: "/etc/fstab"}},
+		},
+	}
+	p.Spec.VMs = []plan.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			Customization: []plan.Customization{
+				{
+					ConfigMap: core.LocalObjectReference{Name: "fixup"},
+					Firstboot: []string{"remove-tools.sh"},
+				},
+			},
+		},
+	}
+	g.Expect(r.validateCustomization(p)).To(gomega.Succeed())
+	g.Expect(p.Status.HasCondition(CustomizationNotValid)).To(gomega.BeFalse())
+
+	// Plan customization followed by the VM customization.
+	commands := []string{}
+	for _, c := range p.Spec.FindCustomization(ref.Ref{ID: "vm-1"}) {
+		commands = append(commands, c.Commands("/mnt")...)
+	}
+	g.Expect(commands).To(gomega.Equal([]string{
+		"upload /mnt/fstab:/etc/fstab",
+		"install qemu-guest-agent,cloud-init",
+		"run /mnt/remove-tools.sh",
+		"firstboot /mnt/remove-tools.sh",
+	}))
+
+	// Key not found.
+	p.Spec.VMs[0].Customization[0].Firstboot = []string{"missing.sh"}
+	g.Expect(r.validateCustomization(p)).To(gomega.Succeed())
+	cnd := p.Status.FindCondition(CustomizationNotValid)
+	g.Expect(cnd.Reason).To(gomega.Equal(NotValid))
+	g.Expect(cnd.Items).To(gomega.Equal([]string{"fixup/missing.sh"}))
+
+	// Control characters and commas.
+	p.Status.DeleteCondition(CustomizationNotValid)
+	p.Spec.VMs[0].Customization[0].Firstboot = []string{"remove-tools.sh"}
+	p.Spec.Customization[0].Upload[0].Path = "/etc/fstab\nrun /tmp/x"
+	p.Spec.Customization[0].Install = []string{"qemu-guest-agent,nc", "cloud-init\nrun /tmp/x"}
+	g.Expect(r.validateCustomization(p)).To(gomega.Succeed())
+	cnd = p.Status.FindCondition(CustomizationNotValid)
+	g.Expect(cnd.Reason).To(gomega.Equal(NotValid))
+	g.Expect(cnd.Items).To(gomega.Equal([]string{
+		"fixup/fstab",
+		"fixup/install[0]",
+		"fixup/install[1]",
+	}))
+
+	// ConfigMap not found.
+	p.Status.DeleteCondition(CustomizationNotValid)
+	p.Spec.Customization[0].Upload[0].Path = "/etc/fstab"
+	p.Spec.Customization[0].Install = []string{"qemu-guest-agent"}
+	p.Spec.VMs[0].Customization[0].ConfigMap.Name = "missing"
+	g.Expect(r.validateCustomization(p)).To(gomega.Succeed())
+	g.Expect(p.Status.FindCondition(CustomizationNotValid).Reason).To(gomega.Equal(NotFound))
+}
+
+func TestCustomizationPodVolumes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Customization = []plan.Customization{
+		{ConfigMap: core.LocalObjectReference{Name: "fixup"}},
+	}
+	p.Spec.VMs = []plan.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			Customization: []plan.Customization{
+				{ConfigMap: core.LocalObjectReference{Name: "vm-1"}},
+			},
+		},
+	}
+	vm := &plan.VMStatus{}
+	vm.ID = "vm-1"
+	pod, err := conversionPod(p, vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(pod.Spec.Volumes).To(gomega.ContainElements(
+		gomega.And(
+			gomega.HaveField("Name", "customize-0"),
+			gomega.HaveField("VolumeSource.ConfigMap.Name", "fixup")),
+		gomega.And(
+			gomega.HaveField("Name", "customize-1"),
+			gomega.HaveField("VolumeSource.ConfigMap.Name", "vm-1"))))
+	g.Expect(pod.Spec.Containers[0].VolumeMounts).To(gomega.ContainElements(
+		core.VolumeMount{Name: "customize-0", MountPath: "/mnt/customize/0", ReadOnly: true},
+		core.VolumeMount{Name: "customize-1", MountPath: "/mnt/customize/1", ReadOnly: true}))
+}
+
+func TestCustomizeConfigMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Name = "plan"
+	p.Spec.TargetNamespace = "target"
+	p.Spec.VMs = []plan.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			Customization: []plan.Customization{
+				{
+					ConfigMap: core.LocalObjectReference{Name: "fixup"},
+					Run:       []string{"remove-tools.sh"},
+				},
+			},
+		},
+		{Ref: ref.Ref{ID: "vm-2"}},
+	}
+	kubevirt := conversionKubeVirt(p)
+	vmCr := &VirtualMachine{
+		VirtualMachine: &cnv.VirtualMachine{
+			Spec: cnv.VirtualMachineSpec{
+				Template: &cnv.VirtualMachineInstanceTemplateSpec{
+					Spec: cnv.VirtualMachineInstanceSpec{
+						Domain: cnv.DomainSpec{CPU: &cnv.CPU{Sockets: 1, Cores: 1}},
+					},
+				},
+			},
+		},
+	}
+	configMap, err := kubevirt.ensureLibvirtConfigMap(ref.Ref{ID: "vm-1"}, vmCr, &[]core.PersistentVolumeClaim{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(configMap.BinaryData["customize"])).To(gomega.Equal("run /mnt/customize/0/remove-tools.sh\n"))
+
+	// Not customized.
+	configMap, err = kubevirt.ensureLibvirtConfigMap(ref.Ref{ID: "vm-2"}, vmCr, &[]core.PersistentVolumeClaim{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(configMap.BinaryData).ToNot(gomega.HaveKey("customize"))
+}
+
+func TestReflectCustomization(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pod := &core.Pod{}
+	terminated := func(message string) {
+		pod.Status.ContainerStatuses = []core.ContainerStatus{
+			{
+				Name: "virt-v2v",
+				State: core.ContainerState{
+					Terminated: &core.ContainerStateTerminated{Message: message},
+				},
+			},
+		}
+	}
+	r := &Migration{}
+
+	// Conversion failed.
+	step := &plan.Step{}
+	terminated("")
+	g.Expect(r.reflectCustomization(pod, step)).To(gomega.BeFalse())
+	g.Expect(step.Annotations).ToNot(gomega.HaveKey("customization"))
+
+	// Customization failed.
+	step = &plan.Step{}
+	output := make([]byte, CustomizationOutputLimit*2)
+	for i := range output {
+		output[i] = 'x'
+	}
+	output[len(output)-1] = '!'
+	terminated(string(output))
+	g.Expect(r.reflectCustomization(pod, step)).To(gomega.BeTrue())
+	message := step.Annotations["customization"]
+	g.Expect(message).To(gomega.HaveLen(CustomizationOutputLimit + 3))
+	g.Expect(message).To(gomega.HavePrefix("..."))
+	g.Expect(message).To(gomega.HaveSuffix("x!"))
+}
@@ -0,0 +1,117 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Event reasons.
+const (
+	Decommissioned     = "Decommissioned"
+	DecommissionFailed = "DecommissionFailed"
+	DecommissionUndone = "DecommissionUndone"
+)
+
+// Perform the decommission actions on the source VM.
+// Each action is recorded on the VM status. Failed actions
+// are not retried and do not fail the migration.
+func (r *Migration) decommission(vm *plan.VMStatus) {
+	for _, action := range r.Plan.Spec.Decommission.Actions() {
+		if vm.FindDecommission(action.Action) != nil {
+			continue
+		}
+		undo, err := r.provider.Decommission(vm.Ref, action)
+		if err != nil {
+			action.Error = err.Error()
+			r.Log.Error(
+				err,
+				"Decommission action failed.",
+				"vm",
+				vm.String(),
+				"action",
+				action.Action)
+			r.record(
+				core.EventTypeWarning,
+				DecommissionFailed,
+				fmt.Sprintf(
+					"VM %s decommission action %s has FAILED: %s",
+					vm.String(),
+					action.Action,
+					action.Error))
+		} else {
+			now := meta.Now()
+			action.Undo = undo
+			action.Applied = &now
+			r.record(
+				core.EventTypeNormal,
+				Decommissioned,
+				fmt.Sprintf(
+					"VM %s decommission action %s applied.",
+					vm.String(),
+					action.Action))
+		}
+		vm.Decommission = append(vm.Decommission, action)
+	}
+}
+
+// Undo the decommission actions applied to the source VMs
+// in the reverse order they were applied.
+// A deleted VM cannot be restored.
+func (r *Migration) Undecommission() (err error) {
+	err = r.init()
+	if err != nil {
+		return
+	}
+	defer r.provider.Close()
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		r.undecommission(vm)
+	}
+
+	return
+}
+
+// Undo the decommission actions applied to the source VM.
+// Undone actions are removed so they are applied again by
+// a later migration. Failed actions are recorded and retried.
+func (r *Migration) undecommission(vm *plan.VMStatus) {
+	for i := len(vm.Decommission) - 1; i >= 0; i-- {
+		action := &vm.Decommission[i]
+		if !action.Active() || action.Action == plan.DecommissionDelete {
+			continue
+		}
+		err := r.provider.UndoDecommission(vm.Ref, *action)
+		if err != nil {
+			action.Error = err.Error()
+			r.Log.Error(
+				err,
+				"Decommission undo failed.",
+				"vm",
+				vm.String(),
+				"action",
+				action.Action)
+			continue
+		}
+		r.record(
+			core.EventTypeNormal,
+			DecommissionUndone,
+			fmt.Sprintf(
+				"VM %s decommission action %s undone.",
+				vm.String(),
+				action.Action))
+		vm.Decommission = append(vm.Decommission[:i], vm.Decommission[i+1:]...)
+	}
+}
+
+// Determine whether the VM has decommission actions to be undone.
+func undoable(vm *plan.VMStatus) bool {
+	for i := range vm.Decommission {
+		action := &vm.Decommission[i]
+		if action.Active() && action.Action != plan.DecommissionDelete {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+)
+
+// Client recording the decommission actions.
+type decommissionClient struct {
+	adapter.Client
+	undone []string
+}
+
+func (r *decommissionClient) Decommission(vmRef ref.Ref, action plan.DecommissionAction) (undo string, err error) {
+	if action.Action == plan.DecommissionTag {
+		err = errors.New("tag not found")
+		return
+	}
+	undo = "original"
+	return
+}
+
+func (r *decommissionClient) UndoDecommission(vmRef ref.Ref, action plan.DecommissionAction) error {
+	r.undone = append(r.undone, action.Action)
+	return nil
+}
+
+func TestDecommission(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	client := &decommissionClient{}
+	p := &api.Plan{}
+	p.Spec.Decommission = &plan.Decommission{
+		RenameSuffix: "-migrated",
+		Tag:          "migrated",
+		Annotation:   "Migrated.",
+	}
+	migration := Migration{
+		Context: &plancontext.Context{
+			Plan:      p,
+			Migration: &api.Migration{},
+			Log:       logging.WithName("test"),
+		},
+		provider: client,
+	}
+	vm := &plan.VMStatus{}
+	migration.decommission(vm)
+	g.Expect(vm.Decommission).To(gomega.HaveLen(3))
+	g.Expect(vm.FindDecommission(plan.DecommissionRename).Active()).To(gomega.BeTrue())
+	g.Expect(vm.FindDecommission(plan.DecommissionRename).Undo).To(gomega.Equal("original"))
+	g.Expect(vm.FindDecommission(plan.DecommissionTag).Active()).To(gomega.BeFalse())
+	g.Expect(vm.FindDecommission(plan.DecommissionTag).Error).ToNot(gomega.BeEmpty())
+
+	// Applied once.
+	migration.decommission(vm)
+	g.Expect(vm.Decommission).To(gomega.HaveLen(3))
+
+	// Undone in reverse order.
+	g.Expect(undoable(vm)).To(gomega.BeTrue())
+	migration.undecommission(vm)
+	g.Expect(client.undone).To(gomega.Equal([]string{plan.DecommissionAnnotate, plan.DecommissionRename}))
+	g.Expect(undoable(vm)).To(gomega.BeFalse())
+	g.Expect(vm.Decommission).To(gomega.HaveLen(1))
+
+	// Applied again.
+	migration.decommission(vm)
+	g.Expect(vm.Decommission).To(gomega.HaveLen(3))
+	g.Expect(undoable(vm)).To(gomega.BeTrue())
+}
+
+func TestMigrated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	succeeded := &plan.VMStatus{}
+	succeeded.ID = "vm-1"
+	succeeded.Name = "one"
+	succeeded.SetCondition(libcnd.Condition{Type: Succeeded, Status: True})
+	decommissioned := &plan.VMStatus{}
+	decommissioned.ID = "vm-2"
+	decommissioned.Name = "two"
+	decommissioned.Decommission = []plan.DecommissionAction{{Action: plan.DecommissionDelete}}
+	failed := &plan.VMStatus{}
+	failed.ID = "vm-3"
+	failed.Name = "three"
+	failed.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+	p.Status.Migration.VMs = []*plan.VMStatus{succeeded, decommissioned, failed}
+
+	g.Expect(migrated(p, ref.Ref{ID: "vm-1"})).To(gomega.BeTrue())
+	g.Expect(migrated(p, ref.Ref{Name: "one"})).To(gomega.BeTrue())
+	g.Expect(migrated(p, ref.Ref{Name: "two"})).To(gomega.BeTrue())
+	g.Expect(migrated(p, ref.Ref{ID: "vm-3"})).To(gomega.BeFalse())
+	g.Expect(migrated(p, ref.Ref{ID: "vm-4"})).To(gomega.BeFalse())
+}
@@ -0,0 +1,48 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateDevices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	iso := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "target",
+			Name:      "rhel-iso",
+		},
+	}
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fakeClient(iso),
+		},
+	}
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "target"
+	host := api.OpenShift
+	p.Referenced.Provider.Destination = &api.Provider{}
+	p.Referenced.Provider.Destination.Spec.Type = &host
+	p.Spec.Devices = &plan.Devices{
+		CDROMs: true,
+		ISOs: []plan.ISOMapping{
+			{Source: "rhel.iso", ClaimName: "rhel-iso"},
+		},
+	}
+	g.Expect(r.validateDevices(p)).To(gomega.Succeed())
+	g.Expect(p.Status.HasCondition(DevicesNotValid)).To(gomega.BeFalse())
+	claimName, found := p.Spec.Devices.FindISO("[datastore1] iso/rhel.iso")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(claimName).To(gomega.Equal("rhel-iso"))
+
+	// PVC not found.
+	p.Spec.Devices.ISOs[0].ClaimName = "missing"
+	g.Expect(r.validateDevices(p)).To(gomega.Succeed())
+	g.Expect(p.Status.FindCondition(DevicesNotValid).Reason).To(gomega.Equal(NotFound))
+}
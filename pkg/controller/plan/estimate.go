@@ -0,0 +1,284 @@
+package plan
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/throughput"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Record the throughput observed for the completed disk transfer.
+// The history is used to estimate migration durations and
+// recording is best-effort.
+func (r *Migration) recordThroughput(vm *plan.VMStatus, step *plan.Step) {
+	if step.Started == nil || step.Progress.Completed == 0 {
+		return
+	}
+	completed := time.Now()
+	if step.Completed != nil {
+		completed = step.Completed.Time
+	}
+	seconds := completed.Sub(step.Started.Time).Seconds()
+	if seconds <= 0 {
+		return
+	}
+	rate := float64(step.Progress.Completed) / seconds
+	requests, err := r.requests(vm.Ref)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Throughput not recorded.",
+			"vm",
+			vm.String())
+		return
+	}
+	history, err := throughput.Load(r.Client, r.Source.Provider)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Throughput not recorded.",
+			"vm",
+			vm.String())
+		return
+	}
+	keys := map[string]bool{}
+	if requests.Host != "" {
+		keys[throughput.HostKey(requests.Host)] = true
+	}
+	for _, disk := range requests.Disks {
+		if disk.Storage != "" {
+			keys[throughput.StorageKey(disk.Storage)] = true
+		}
+		keys[throughput.StorageClassKey(disk.StorageClass)] = true
+	}
+	for key := range keys {
+		history.Record(key, rate)
+	}
+	err = throughput.Save(r.Client, r.Source.Provider, history)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Throughput not recorded.",
+			"vm",
+			vm.String())
+		return
+	}
+	r.Log.V(1).Info(
+		"Throughput recorded.",
+		"vm",
+		vm.String(),
+		"rate",
+		rate)
+}
+
+// Find the destination resources requested by the VM.
+func (r *Migration) requests(vmRef ref.Ref) (requests adapter.Requests, err error) {
+	pAdapter, err := adapter.New(r.Source.Provider)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(r.Plan)
+	if err != nil {
+		return
+	}
+	requests, err = validator.Requests(vmRef)
+	return
+}
+
+// Destination resources requested by the VMs of a plan.
+// Cached for the plan generation to avoid inventory
+// lookups on each reconcile.
+type planRequests struct {
+	// Plan UID.
+	uid types.UID
+	// Plan generation.
+	generation int64
+	// Requests keyed by VM.
+	vms map[string]adapter.Requests
+}
+
+// Requests cache keyed by plan namespace/name.
+var requestCache = struct {
+	sync.Mutex
+	plans map[string]*planRequests
+}{
+	plans: map[string]*planRequests{},
+}
+
+// Find the (cached) requests of the plan VMs.
+// VMs for which requests cannot be determined are omitted.
+func cachedRequests(p *api.Plan) (requests map[string]adapter.Requests) {
+	requestCache.Lock()
+	defer requestCache.Unlock()
+	key := path.Join(p.Namespace, p.Name)
+	cached, found := requestCache.plans[key]
+	if !found || cached.uid != p.UID || cached.generation != p.Generation {
+		cached = &planRequests{
+			uid:        p.UID,
+			generation: p.Generation,
+			vms:        map[string]adapter.Requests{},
+		}
+		requestCache.plans[key] = cached
+	}
+	var validator adapter.Validator
+	for _, vm := range p.Spec.VMs {
+		if _, found := cached.vms[vm.Ref.String()]; found {
+			continue
+		}
+		if validator == nil {
+			pAdapter, err := adapter.New(p.Referenced.Provider.Source)
+			if err != nil {
+				break
+			}
+			validator, err = pAdapter.Validator(p)
+			if err != nil {
+				break
+			}
+		}
+		vmRequests, err := validator.Requests(vm.Ref)
+		if err != nil {
+			continue
+		}
+		cached.vms[vm.Ref.String()] = vmRequests
+	}
+	requests = cached.vms
+	return
+}
+
+// Purge the cached requests of a deleted plan.
+func purgeRequests(namespace, name string) {
+	requestCache.Lock()
+	defer requestCache.Unlock()
+	delete(requestCache.plans, path.Join(namespace, name))
+}
+
+// Estimate the (remaining) migration duration of
+// the plan and each VM that has not completed.
+// VMs without throughput history are not estimated.
+func (r *Reconciler) estimate(p *api.Plan) {
+	p.Status.Estimate = nil
+	provider := p.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	history, err := throughput.Load(r.Client, provider)
+	if err != nil {
+		r.Log.Error(err, "Estimate failed.")
+		return
+	}
+	if len(history.Throughput) == 0 {
+		return
+	}
+	vmRequests := cachedRequests(p)
+	now := time.Now()
+	estimate := &plan.PlanEstimate{}
+	durations := []time.Duration{}
+	for _, vm := range p.Spec.VMs {
+		status, found := p.Status.Migration.FindVM(vm.Ref)
+		if found && status.MarkedCompleted() {
+			continue
+		}
+		requests, found := vmRequests[vm.Ref.String()]
+		if !found {
+			continue
+		}
+		duration, known := remaining(history, requests, status, now)
+		if !known {
+			continue
+		}
+		durations = append(durations, duration)
+		eta := meta.NewTime(now.Add(duration))
+		estimate.VMs = append(
+			estimate.VMs,
+			plan.VMEstimate{
+				Ref: vm.Ref,
+				Estimate: plan.Estimate{
+					Duration: int64(duration.Seconds()),
+					ETA:      &eta,
+				},
+			})
+	}
+	if len(durations) == 0 {
+		return
+	}
+	duration := total(durations, Settings.Migration.MaxInFlight)
+	eta := meta.NewTime(now.Add(duration))
+	estimate.Duration = int64(duration.Seconds())
+	estimate.ETA = &eta
+	p.Status.Estimate = estimate
+}
+
+// Estimate the remaining transfer duration of a VM.
+// The rate observed by the running transfer is preferred
+// over the history.
+func remaining(history *throughput.History, requests adapter.Requests, status *plan.VMStatus, now time.Time) (duration time.Duration, known bool) {
+	if status != nil {
+		for _, name := range []string{DiskTransfer, DiskTransferV2v} {
+			step, found := status.FindStep(name)
+			if !found {
+				continue
+			}
+			if step.MarkedCompleted() {
+				known = true
+				return
+			}
+			if step.Started == nil || step.Progress.Completed == 0 {
+				continue
+			}
+			elapsed := now.Sub(step.Started.Time).Seconds()
+			if elapsed <= 0 {
+				continue
+			}
+			rate := float64(step.Progress.Completed) / elapsed
+			left := step.Progress.Total - step.Progress.Completed
+			if left < 0 {
+				left = 0
+			}
+			duration = time.Duration(float64(left)/rate) * time.Second
+			known = true
+			return
+		}
+	}
+	disks := []throughput.Disk{}
+	for _, disk := range requests.Disks {
+		disks = append(
+			disks,
+			throughput.Disk{
+				Storage:      disk.Storage,
+				StorageClass: disk.StorageClass,
+				Size:         disk.Size,
+			})
+	}
+	duration, known = history.Duration(requests.Host, disks)
+	return
+}
+
+// Estimate the total duration of VM migrations
+// run concurrently up to the in-flight limit.
+func total(durations []time.Duration, inFlight int) (duration time.Duration) {
+	if inFlight < 1 {
+		inFlight = 1
+	}
+	var sum, longest time.Duration
+	for _, d := range durations {
+		sum += d
+		if d > longest {
+			longest = d
+		}
+	}
+	if len(durations) < inFlight {
+		inFlight = len(durations)
+	}
+	duration = sum / time.Duration(inFlight)
+	if longest > duration {
+		duration = longest
+	}
+	return
+}
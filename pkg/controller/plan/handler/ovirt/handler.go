@@ -88,7 +88,7 @@ func (r *Handler) changed(models ...*ovirt.VM) {
 			continue
 		}
 		referenced := false
-		for _, planVM := range plan.Spec.VMs {
+		for _, planVM := range plan.VMs() {
 			ref := planVM.Ref
 			for _, vm := range models {
 				if ref.ID == vm.ID || strings.HasSuffix(vm.Path, ref.Name) {
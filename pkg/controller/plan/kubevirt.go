@@ -2,6 +2,7 @@ package plan
 
 import (
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"math/rand"
@@ -21,7 +22,6 @@ import (
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	cnv "kubevirt.io/client-go/api/v1"
 	libvirtxml "libvirt.org/libvirt-go-xml"
@@ -30,6 +30,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	openstackutil "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
@@ -39,7 +40,9 @@ import (
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -89,6 +92,22 @@ const (
 	qemuGroup = int64(107)
 )
 
+// KubeVirt feature gates.
+const (
+	// Persist the EFI variables and vTPM state.
+	PersistentStateGate = "VMPersistentState"
+)
+
+// Persistent VM state.
+const (
+	// Name prefix of the (KubeVirt) persistent state PVC.
+	PersistentStatePrefix = "persistent-state-for-"
+	// Size of the persistent state PVC.
+	PersistentStateSize = "10Mi"
+	// Persistent state PVC mount in the conversion pod.
+	PersistentStateMount = "/mnt/nvram"
+)
+
 // Map of VirtualMachines keyed by vmID.
 type VirtualMachineMap map[string]VirtualMachine
 
@@ -225,6 +244,7 @@ func (r *KubeVirt) GetImporterPod(pvc core.PersistentVolumeClaim) (pod *core.Pod
 }
 
 // Delete the DataVolumes associated with the VM.
+// Shared DataVolumes are only deleted with the owner.
 func (r *KubeVirt) DeleteDataVolumes(vm *plan.VMStatus) (err error) {
 	dvs, err := r.getDVs(vm)
 	if err != nil {
@@ -232,6 +252,9 @@ func (r *KubeVirt) DeleteDataVolumes(vm *plan.VMStatus) (err error) {
 		return
 	}
 	for _, dv := range dvs {
+		if dv.Labels[kVM] != vm.ID {
+			continue
+		}
 		err = r.Destination.Client.Delete(context.TODO(), dv.DataVolume)
 		if err != nil {
 			return
@@ -288,9 +311,8 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) (err error) {
 	virtualMachine := &cnv.VirtualMachine{}
 	if len(list.Items) == 0 {
 		virtualMachine = newVM
-		err = r.Destination.Client.Create(context.TODO(), virtualMachine)
+		err = r.createVM(vm, virtualMachine)
 		if err != nil {
-			err = liberr.Wrap(err)
 			return
 		}
 		r.Log.Info(
@@ -327,6 +349,14 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) (err error) {
 
 	for _, pvc := range pvcs {
 		ownerRefs := []meta.OwnerReference{vmOwnerReference(virtualMachine)}
+		// shared PVCs are owned by each of the VMs.
+		if _, shared := pvc.Annotations[planbase.AnnSharedDisk]; shared {
+			for _, owner := range pvc.OwnerReferences {
+				if owner.UID != virtualMachine.UID {
+					ownerRefs = append(ownerRefs, owner)
+				}
+			}
+		}
 		pvcCopy := pvc.DeepCopy()
 		pvc.OwnerReferences = ownerRefs
 		patch := client.MergeFrom(pvcCopy)
@@ -353,6 +383,18 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) (err error) {
 		}
 	}
 
+	// The persistent state PVC is owned by the VM.
+	statePVC, found, err := r.persistentStatePVC(vm)
+	if err != nil || !found {
+		return
+	}
+	pvcCopy := statePVC.DeepCopy()
+	statePVC.OwnerReferences = []meta.OwnerReference{vmOwnerReference(virtualMachine)}
+	err = r.Destination.Client.Patch(context.TODO(), statePVC, client.MergeFrom(pvcCopy))
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
 	return
 }
 
@@ -461,6 +503,57 @@ func (r *KubeVirt) SetRunning(vmCr *VirtualMachine, running bool) (err error) {
 	return
 }
 
+// Set the readiness probe on a Kubevirt VirtualMachine.
+// The probe is (raw) patched so that probe actions not known
+// to the KubeVirt API client may be used. A nil probe removes
+// the readiness probe.
+func (r *KubeVirt) SetReadinessProbe(vmCr *VirtualMachine, probe map[string]interface{}) (err error) {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"readinessProbe": probe,
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.Destination.Client.Patch(
+		context.TODO(),
+		vmCr.VirtualMachine,
+		client.RawPatch(types.MergePatchType, data))
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Get the VirtualMachineInstance for a Kubevirt VirtualMachine.
+func (r *KubeVirt) GetVMI(vmCr *VirtualMachine) (vmi *cnv.VirtualMachineInstance, found bool, err error) {
+	vmi = &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: vmCr.Namespace,
+			Name:      vmCr.Name,
+		},
+		vmi)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	found = true
+	return
+}
+
 func (r *KubeVirt) DataVolumes(vm *plan.VMStatus) (dataVolumes []cdi.DataVolume, err error) {
 	secret, err := r.ensureSecret(vm.Ref, r.secretDataSetterForCDI(vm.Ref))
 	if err != nil {
@@ -530,14 +623,142 @@ func (r *KubeVirt) EnsureDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVo
 	return
 }
 
+// Ensure the PVCs bound to the persistent volumes
+// the LUNs are mapped to. The LUNs are not copied.
+func (r *KubeVirt) EnsureLunClaims(vm *plan.VMStatus) (err error) {
+	volumes, err := r.Builder.MappedLUNs(vm.Ref)
+	if err != nil || len(volumes) == 0 {
+		return
+	}
+	pvcs, err := r.getPVCs(vm)
+	if err != nil {
+		return
+	}
+	for source, name := range volumes {
+		exists := false
+		for _, pvc := range pvcs {
+			if pvc.Annotations[planbase.AnnDiskSource] == source {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		pv := &core.PersistentVolume{}
+		err = r.Destination.Client.Get(context.TODO(), client.ObjectKey{Name: name}, pv)
+		if err != nil {
+			err = liberr.Wrap(err, "pv", name)
+			return
+		}
+		var claimable bool
+		claimable, err = lunVolumeClaimable(r.Destination.Client, r.Plan, pv)
+		if err != nil {
+			return
+		}
+		if !claimable {
+			err = liberr.New(
+				"LUN persistent volume cannot be claimed.",
+				"pv",
+				name)
+			return
+		}
+		annotations := r.vmLabels(vm.Ref)
+		annotations[planbase.AnnDiskSource] = source
+		storageClass := pv.Spec.StorageClassName
+		pvc := &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:    r.Plan.Spec.TargetNamespace,
+				GenerateName: r.getGeneratedName(vm),
+				Annotations:  annotations,
+				Labels:       r.vmLabels(vm.Ref),
+			},
+			Spec: core.PersistentVolumeClaimSpec{
+				AccessModes: pv.Spec.AccessModes,
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: pv.Spec.Capacity[core.ResourceStorage],
+					},
+				},
+				StorageClassName: &storageClass,
+				VolumeMode:       pv.Spec.VolumeMode,
+				VolumeName:       pv.Name,
+			},
+		}
+		// Pre-bound volumes are claimed by name.
+		if ref := pv.Spec.ClaimRef; ref != nil && ref.Name != "" {
+			pvc.GenerateName = ""
+			pvc.Name = ref.Name
+		}
+		err = r.Destination.Client.Create(context.TODO(), pvc)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info("Created LUN PVC.",
+			"pvc",
+			path.Join(
+				pvc.Namespace,
+				pvc.Name),
+			"pv",
+			pv.Name,
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+// Determine whether the LUN persistent volume may be claimed
+// by the plan. The volume must be block and either available
+// or bound to a PVC created by the plan. The volume must be
+// labeled for or pre-bound to the target namespace.
+func lunVolumeClaimable(cl client.Client, p *v1beta1.Plan, pv *core.PersistentVolume) (claimable bool, err error) {
+	if pv.Spec.VolumeMode == nil || *pv.Spec.VolumeMode != core.PersistentVolumeBlock {
+		return
+	}
+	namespace := p.Spec.TargetNamespace
+	ref := pv.Spec.ClaimRef
+	if ref != nil && ref.Name != "" && ref.Namespace != namespace {
+		return
+	}
+	if pv.Labels[plan.LUNNamespaceLabel] != namespace && (ref == nil || ref.Name == "") {
+		return
+	}
+	if ref == nil || ref.UID == "" {
+		claimable = pv.Status.Phase == core.VolumeAvailable
+		return
+	}
+	pvc := &core.PersistentVolumeClaim{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		pvc)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	claimable = pvc.UID == ref.UID && pvc.Labels[kPlan] == string(p.GetUID())
+
+	return
+}
+
 // Return DataVolumes associated with a VM.
+// Includes the shared DataVolumes attached to the VM.
 func (r *KubeVirt) getDVs(vm *plan.VMStatus) (dvs []DataVolume, err error) {
 	dvsList := &cdi.DataVolumeList{}
 	err = r.Destination.Client.List(
 		context.TODO(),
 		dvsList,
 		&client.ListOptions{
-			LabelSelector: labels.SelectorFromSet(r.vmLabels(vm.Ref)),
+			LabelSelector: labels.SelectorFromSet(r.planLabels()),
 			Namespace:     r.Plan.Spec.TargetNamespace,
 		})
 
@@ -549,6 +770,9 @@ func (r *KubeVirt) getDVs(vm *plan.VMStatus) (dvs []DataVolume, err error) {
 	dvs = []DataVolume{}
 	for i := range dvsList.Items {
 		dv := &dvsList.Items[i]
+		if dv.Labels[kVM] != vm.ID && !planbase.SharedWith(dv.Annotations, vm.ID) {
+			continue
+		}
 		dvs = append(dvs, DataVolume{
 			DataVolume: dv,
 		})
@@ -577,6 +801,8 @@ func (r *KubeVirt) getPVCs(vm *plan.VMStatus) (pvcs []core.PersistentVolumeClaim
 		pvcAnn := pvc.GetAnnotations()
 		if pvcAnn[kVM] == vmLabels[kVM] && pvcAnn[kPlan] == vmLabels[kPlan] {
 			pvcs = append(pvcs, *pvc)
+		} else if pvcAnn[kPlan] == vmLabels[kPlan] && planbase.SharedWith(pvcAnn, vm.ID) {
+			pvcs = append(pvcs, *pvc)
 		} else if r.isOpenstack(vm) {
 			if _, ok := pvc.Labels["migration"]; ok {
 				if pvc.Labels["migration"] == r.Migration.Name {
@@ -616,8 +842,24 @@ func (r *KubeVirt) createVolumesForOvirt(vm ref.Ref) (pvcNames []string, err err
 		return
 	}
 
+	sharedDisks, err := r.Builder.SharedDisks()
+	if err != nil {
+		return
+	}
+	mappedLUNs, err := r.Builder.MappedLUNs(vm)
+	if err != nil {
+		return
+	}
+
 	storageName := &r.Context.Map.Storage.Spec.Map[0].Destination.StorageClass
 	for _, da := range ovirtVm.DiskAttachments {
+		shared := sharedDisks.Shared(da.Disk.ID)
+		if shared && !sharedDisks.Owner(da.Disk.ID, ovirtVm.ID) {
+			continue
+		}
+		if _, mapped := mappedLUNs[da.Disk.ID]; mapped {
+			continue
+		}
 		populatorCr := r.OvirtVolumePopulator(da, sourceUrl, r.Plan.Spec.TransferNetwork, secret.Name)
 		failure := r.Client.Create(context.Background(), populatorCr, &client.CreateOptions{})
 		if failure != nil && !k8serr.IsAlreadyExists(failure) {
@@ -628,6 +870,11 @@ func (r *KubeVirt) createVolumesForOvirt(vm ref.Ref) (pvcNames []string, err err
 		if failure != nil {
 			return nil, failure
 		}
+		if shared {
+			block := core.PersistentVolumeBlock
+			accessModes = []core.PersistentVolumeAccessMode{core.ReadWriteMany}
+			volumeMode = &block
+		}
 
 		pvc := r.Builder.PersistentVolumeClaimWithSourceRef(da, storageName, populatorCr.Name, accessModes, volumeMode)
 		if pvc == nil {
@@ -673,7 +920,7 @@ func (r *KubeVirt) createPodToBindPVCs(vm *plan.VMStatus, pvcNames []string) err
 					Name: "main",
 					// For v2v the consumer pod is used only when we execute cold migration with el9.
 					// In that case, we could benefit from pulling the image of the conversion pod, so it will be present on the node.
-					Image:   Settings.Migration.VirtV2vImageCold,
+					Image:   r.virtV2vImage(vm.Ref),
 					Command: []string{"/bin/sh"},
 					SecurityContext: &core.SecurityContext{
 						AllowPrivilegeEscalation: &allowPrivilageEscalation,
@@ -689,7 +936,7 @@ func (r *KubeVirt) createPodToBindPVCs(vm *plan.VMStatus, pvcNames []string) err
 		},
 	}
 	// Align with the conversion pod request, to prevent breakage
-	r.setKvmOnPodSpec(&pod.Spec)
+	r.setKvmOnPodSpec(vm.Ref, &pod.Spec)
 
 	err := r.Client.Create(context.TODO(), pod, &client.CreateOptions{})
 	if err != nil {
@@ -700,8 +947,12 @@ func (r *KubeVirt) createPodToBindPVCs(vm *plan.VMStatus, pvcNames []string) err
 }
 
 // Sets KVM requirement to the pod and container.
-func (r *KubeVirt) setKvmOnPodSpec(podSpec *core.PodSpec) {
-	if *r.Plan.Provider.Source.Spec.Type == v1beta1.VSphere && !Settings.VirtV2vDontRequestKVM {
+func (r *KubeVirt) setKvmOnPodSpec(vmRef ref.Ref, podSpec *core.PodSpec) {
+	requestKVM := !Settings.VirtV2vDontRequestKVM
+	if conversion := r.Plan.Spec.FindConversion(vmRef); conversion.RequestKVM != nil {
+		requestKVM = *conversion.RequestKVM
+	}
+	if *r.Plan.Provider.Source.Spec.Type == v1beta1.VSphere && requestKVM {
 		if podSpec.NodeSelector == nil {
 			podSpec.NodeSelector = make(map[string]string)
 		}
@@ -741,13 +992,39 @@ func (r *KubeVirt) areOvirtPVCsReady(vm ref.Ref, step *plan.Step) (ready bool, e
 	if err != nil {
 		return
 	}
+	mappedLUNs, err := r.Builder.MappedLUNs(vm)
+	if err != nil {
+		return
+	}
+	sharedDisks, err := r.Builder.SharedDisks()
+	if err != nil {
+		return
+	}
 	ready = true
 
 	for _, da := range ovirtVm.DiskAttachments {
+		if _, mapped := mappedLUNs[da.Disk.ID]; mapped {
+			continue
+		}
 		obj := client.ObjectKey{Namespace: r.Plan.Spec.TargetNamespace, Name: da.Disk.ID}
 		pvc := core.PersistentVolumeClaim{}
 		err = r.Client.Get(context.Background(), obj, &pvc)
 		if err != nil {
+			if !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			// shared disks are created by the owner.
+			if sharedDisks.Shared(da.Disk.ID) && !sharedDisks.Owner(da.Disk.ID, ovirtVm.ID) {
+				err = nil
+				ready = false
+				break
+			}
+			err = liberr.Wrap(
+				err,
+				"PVC not found.",
+				"pvc",
+				obj.String())
 			return
 		}
 
@@ -816,7 +1093,12 @@ func (r *KubeVirt) EnsureGuestConversionPod(vm *plan.VMStatus, vmCr *VirtualMach
 		return
 	}
 
-	newPod, err := r.guestConversionPod(vm, vmCr.Spec.Template.Spec.Volumes, configMap, pvcs, v2vSecret)
+	statePVC, err := r.ensurePersistentStatePVC(vm)
+	if err != nil {
+		return
+	}
+
+	newPod, err := r.guestConversionPod(vm, vmCr.Spec.Template.Spec.Volumes, configMap, pvcs, v2vSecret, statePVC)
 	if err != nil {
 		return
 	}
@@ -1024,19 +1306,9 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus) (object *cnv.VirtualMachine
 		return
 	}
 
-	//If the VM name is not valid according to DNS1123 labeling
-	//convention it will be automatically changed.
-	var originalName string
-
-	if errs := k8svalidation.IsDNS1123Label(vm.Name); len(errs) > 0 {
-		originalName = vm.Name
-		vm.Name, err = r.changeVmNameDNS1123(vm.Name, r.Plan.Spec.TargetNamespace)
-		if err != nil {
-			r.Log.Error(err, "Failed to update the VM name to meet DNS1123 protocol requirements.")
-			return
-		}
-		r.Log.Info("VM name ", originalName, " was incompatible with DNS1123 RFC, changing to ",
-			vm.Name)
+	err = r.ensureTargetName(vm)
+	if err != nil {
+		return
 	}
 
 	var ok bool
@@ -1048,11 +1320,16 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus) (object *cnv.VirtualMachine
 		object = r.emptyVm(vm)
 	}
 	//Add the original name and ID info to the VM annotations
-	if len(originalName) > 0 {
-		annotations := make(map[string]string)
-		annotations[AnnOriginalName] = originalName
-		annotations[AnnOriginalID] = vm.ID
-		object.ObjectMeta.Annotations = annotations
+	if len(vm.OriginalName) > 0 {
+		if object.ObjectMeta.Annotations == nil {
+			object.ObjectMeta.Annotations = make(map[string]string)
+		}
+		object.ObjectMeta.Annotations[AnnOriginalName] = vm.OriginalName
+		object.ObjectMeta.Annotations[AnnOriginalID] = vm.ID
+	}
+	err = r.mapMetadata(vm, object)
+	if err != nil {
+		return
 	}
 	running := false
 	object.Spec.Running = &running
@@ -1065,6 +1342,271 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus) (object *cnv.VirtualMachine
 	return
 }
 
+// Create the VirtualMachine.
+// The firmware state (EFI, vTPM) is not modeled by the KubeVirt
+// API used to build the VM and is set on the unstructured
+// representation. The state is persisted only when the
+// VMPersistentState feature gate is enabled. Otherwise, the
+// vTPM is not persistent and the EFI variables are not kept.
+func (r *KubeVirt) createVM(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	state, err := r.Builder.PersistentState(vm.Ref)
+	if err != nil {
+		return
+	}
+	if !state.EFI && !state.TPM {
+		err = r.Destination.Client.Create(context.TODO(), object)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	persistent, err := persistentStateEnabled(r.Destination.Client)
+	if err != nil {
+		return
+	}
+	if !persistent {
+		r.Log.Info(
+			"Feature gate not enabled, firmware state not persisted.",
+			"gate",
+			PersistentStateGate,
+			"vm",
+			vm.String())
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(cnv.VirtualMachineGroupVersionKind)
+	domain := []string{"spec", "template", "spec", "domain"}
+	if state.EFI && persistent {
+		efi := append(domain, "firmware", "bootloader", "efi")
+		if _, found, _ := unstructured.NestedMap(content, efi...); found {
+			err = unstructured.SetNestedField(content, true, append(efi, "persistent")...)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	if state.TPM {
+		tpm := map[string]interface{}{}
+		if persistent {
+			tpm["persistent"] = true
+		}
+		err = unstructured.SetNestedMap(
+			content,
+			tpm,
+			append(domain, "devices", "tpm")...)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = r.Destination.Client.Create(context.TODO(), u)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, object)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Determine whether the KubeVirt feature gate required to
+// persist the firmware state is enabled on the cluster.
+func persistentStateEnabled(cl client.Client) (enabled bool, err error) {
+	list := &cnv.KubeVirtList{}
+	err = cl.List(context.TODO(), list)
+	if err != nil {
+		if apimeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, kv := range list.Items {
+		config := kv.Spec.Configuration.DeveloperConfiguration
+		if config == nil {
+			continue
+		}
+		for _, gate := range config.FeatureGates {
+			if gate == PersistentStateGate {
+				enabled = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// The storage class of the persistent VM state configured
+// on the KubeVirt CR. Not modeled by the KubeVirt API used
+// and read from the unstructured representation.
+// Empty when not configured (the default storage class).
+func vmStateStorageClass(cl client.Client) (storageClass string, err error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(cnv.GroupVersion.WithKind("KubeVirtList"))
+	err = cl.List(context.TODO(), list)
+	if err != nil {
+		if apimeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, kv := range list.Items {
+		storageClass, _, _ = unstructured.NestedString(
+			kv.Object,
+			"spec",
+			"configuration",
+			"vmStateStorageClass")
+		if storageClass != "" {
+			return
+		}
+	}
+
+	return
+}
+
+// Ensure the PVC holding the persistent state (EFI variable
+// store) of the target VM. The PVC is named as the KubeVirt
+// (backend storage) persistent state PVC of the target VM so
+// that it is used by the VM. The NVRAM copied from the source
+// VM is written to the PVC by the conversion pod. Not created
+// (nil) when the EFI variables are not persisted.
+func (r *KubeVirt) ensurePersistentStatePVC(vm *plan.VMStatus) (pvc *core.PersistentVolumeClaim, err error) {
+	state, err := r.Builder.PersistentState(vm.Ref)
+	if err != nil || !state.EFI {
+		return
+	}
+	persistent, err := persistentStateEnabled(r.Destination.Client)
+	if err != nil || !persistent {
+		return
+	}
+	err = r.ensureTargetName(vm)
+	if err != nil {
+		return
+	}
+	pvc, found, err := r.persistentStatePVC(vm)
+	if err != nil || found {
+		return
+	}
+	storageClass, err := vmStateStorageClass(r.Destination.Client)
+	if err != nil {
+		return
+	}
+	volumeMode := core.PersistentVolumeFilesystem
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Name:      PersistentStatePrefix + vm.Name,
+			Labels:    r.vmLabels(vm.Ref),
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: []core.PersistentVolumeAccessMode{
+				core.ReadWriteOnce,
+			},
+			VolumeMode: &volumeMode,
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: resource.MustParse(PersistentStateSize),
+				},
+			},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	err = r.Destination.Client.Create(context.TODO(), pvc)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created persistent state PVC.",
+		"pvc",
+		path.Join(
+			pvc.Namespace,
+			pvc.Name),
+		"vm",
+		vm.String())
+
+	return
+}
+
+// Find the persistent state PVC created for the target VM.
+func (r *KubeVirt) persistentStatePVC(vm *plan.VMStatus) (pvc *core.PersistentVolumeClaim, found bool, err error) {
+	pvc = &core.PersistentVolumeClaim{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Name:      PersistentStatePrefix + vm.Name,
+		},
+		pvc)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		pvc = nil
+		return
+	}
+	vmLabels := r.vmLabels(vm.Ref)
+	found = pvc.Labels[kPlan] == vmLabels[kPlan] && pvc.Labels[kVM] == vmLabels[kVM]
+	if !found {
+		pvc = nil
+	}
+
+	return
+}
+
+// Delete the persistent state PVC created for the target VM.
+func (r *KubeVirt) DeletePersistentStatePVC(vm *plan.VMStatus) (err error) {
+	pvc, found, err := r.persistentStatePVC(vm)
+	if err != nil || !found {
+		return
+	}
+	err = r.DeleteObject(pvc, vm, "Deleted persistent state PVC.", "pvc")
+	return
+}
+
+// Add the labels and annotations mapped from the source
+// VM metadata. Existing labels and annotations are not replaced.
+func (r *KubeVirt) mapMetadata(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	labels, annotations, err := r.Builder.Metadata(vm.Ref)
+	if err != nil {
+		return
+	}
+	if len(labels) > 0 && object.Labels == nil {
+		object.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		if _, found := object.Labels[k]; !found {
+			object.Labels[k] = v
+		}
+	}
+	if len(annotations) > 0 && object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		if _, found := object.Annotations[k]; !found {
+			object.Annotations[k] = v
+		}
+	}
+
+	return
+}
+
 // Attempt to find a suitable template and extract a VirtualMachine definition from it.
 func (r *KubeVirt) vmTemplate(vm *plan.VMStatus) (virtualMachine *cnv.VirtualMachine, ok bool) {
 	tmpl, err := r.findTemplate(vm)
@@ -1227,26 +1769,95 @@ func (r *KubeVirt) findTemplate(vm *plan.VMStatus) (tmpl *template.Template, err
 	return
 }
 
-func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs *[]core.PersistentVolumeClaim, v2vSecret *core.Secret) (pod *core.Pod, err error) {
+func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs *[]core.PersistentVolumeClaim, v2vSecret *core.Secret, statePVC *core.PersistentVolumeClaim) (pod *core.Pod, err error) {
 	volumes, volumeMounts, volumeDevices := r.podVolumeMounts(vmVolumes, configMap, pvcs)
+	// LUKS keys passed to virt-v2v as --key options.
+	if luks := r.Plan.Spec.FindLUKS(vm.Ref); luks != nil {
+		mode := int32(0440)
+		volumes = append(volumes, core.Volume{
+			Name: "luks",
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName:  luks.Name,
+					DefaultMode: &mode,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, core.VolumeMount{
+			Name:      "luks",
+			MountPath: "/etc/luks",
+			ReadOnly:  true,
+		})
+	}
+	// Guest customization scripts and files.
+	for i, customization := range r.Plan.Spec.FindCustomization(vm.Ref) {
+		mode := int32(0555)
+		name := fmt.Sprintf("customize-%d", i)
+		volumes = append(volumes, core.Volume{
+			Name: name,
+			VolumeSource: core.VolumeSource{
+				ConfigMap: &core.ConfigMapVolumeSource{
+					LocalObjectReference: customization.ConfigMap,
+					DefaultMode:          &mode,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, core.VolumeMount{
+			Name:      name,
+			MountPath: fmt.Sprintf("/mnt/customize/%d", i),
+			ReadOnly:  true,
+		})
+	}
 
 	// qemu group
 	fsGroup := qemuGroup
 	user := qemuUser
 	nonRoot := true
 	allowPrivilageEscalation := false
-	// virt-v2v image
-	var virtV2vImage string
-	if r.Context.UseEl9VirtV2v() {
-		virtV2vImage = Settings.Migration.VirtV2vImageCold
-	} else {
-		virtV2vImage = Settings.Migration.VirtV2vImageWarm
+	conversion := r.Plan.Spec.FindConversion(vm.Ref)
+	// Scratch space for virt-v2v.
+	if conversion.Scratch != nil {
+		volumes = append(volumes, r.scratchVolume(conversion.Scratch))
+		volumeMounts = append(volumeMounts, core.VolumeMount{
+			Name:      "scratch",
+			MountPath: "/var/tmp",
+		})
+	}
+	resources := core.ResourceRequirements{}
+	if conversion.Resources != nil {
+		conversion.Resources.DeepCopyInto(&resources)
 	}
 	// pod environment
 	environment, err := r.Builder.PodEnvironment(vm.Ref, r.Source.Secret)
 	if err != nil {
 		return
 	}
+	// Persistent state (NVRAM) written to the
+	// path of the EFI variable store of the VM.
+	if statePVC != nil {
+		volumes = append(volumes, core.Volume{
+			Name: "nvram",
+			VolumeSource: core.VolumeSource{
+				PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+					ClaimName: statePVC.Name,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, core.VolumeMount{
+			Name:      "nvram",
+			MountPath: PersistentStateMount,
+		})
+		environment = append(
+			environment,
+			core.EnvVar{
+				Name:  "V2V_nvramFile",
+				Value: path.Join("nvram", vm.Name+"_VARS.fd"),
+			},
+			core.EnvVar{
+				Name:  "V2V_reEnrollKeys",
+				Value: strconv.FormatBool(r.Plan.Spec.NVRAM != nil && r.Plan.Spec.NVRAM.ReEnrollKeys),
+			})
+	}
 	// pod
 	pod = &core.Pod{
 		ObjectMeta: meta.ObjectMeta{
@@ -1294,9 +1905,10 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 							},
 						},
 					},
-					Image:         virtV2vImage,
+					Image:         r.virtV2vImage(vm.Ref),
 					VolumeMounts:  volumeMounts,
 					VolumeDevices: volumeDevices,
+					Resources:     resources,
 					Ports: []core.ContainerPort{
 						{
 							Name:          "metrics",
@@ -1318,7 +1930,57 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 	// Request access to /dev/kvm via Kubevirt's Device Manager
 	// That is to ensure the appliance virt-v2v uses would not
 	// run in emulation mode, which is significantly slower
-	r.setKvmOnPodSpec(&pod.Spec)
+	r.setKvmOnPodSpec(vm.Ref, &pod.Spec)
+
+	return
+}
+
+// The virt-v2v image for the VM.
+// The image specified by the plan must be permitted.
+func (r *KubeVirt) virtV2vImage(vmRef ref.Ref) (image string) {
+	conversion := r.Plan.Spec.FindConversion(vmRef)
+	if Settings.Migration.VirtV2vPermittedImages[conversion.Image] {
+		image = conversion.Image
+		return
+	}
+	if r.Context.UseEl9VirtV2v() {
+		image = Settings.Migration.VirtV2vImageCold
+	} else {
+		image = Settings.Migration.VirtV2vImageWarm
+	}
+
+	return
+}
+
+// Build the virt-v2v scratch volume.
+// An ephemeral PVC when the storage class is specified,
+// otherwise an emptyDir limited to the size.
+func (r *KubeVirt) scratchVolume(scratch *plan.ScratchVolume) (volume core.Volume) {
+	volume.Name = "scratch"
+	if scratch.StorageClass == "" {
+		volume.EmptyDir = &core.EmptyDirVolumeSource{
+			SizeLimit: scratch.Size,
+		}
+		return
+	}
+	volumeMode := core.PersistentVolumeFilesystem
+	claim := core.PersistentVolumeClaimSpec{
+		AccessModes: []core.PersistentVolumeAccessMode{
+			core.ReadWriteOnce,
+		},
+		VolumeMode:       &volumeMode,
+		StorageClassName: &scratch.StorageClass,
+	}
+	if scratch.Size != nil {
+		claim.Resources.Requests = core.ResourceList{
+			core.ResourceStorage: *scratch.Size,
+		}
+	}
+	volume.Ephemeral = &core.EphemeralVolumeSource{
+		VolumeClaimTemplate: &core.PersistentVolumeClaimTemplate{
+			Spec: claim,
+		},
+	}
 
 	return
 }
@@ -1519,6 +2181,18 @@ func (r *KubeVirt) ensureLibvirtConfigMap(vmRef ref.Ref, vmCr *VirtualMachine, p
 		configMap.BinaryData = make(map[string][]byte)
 	}
 	configMap.BinaryData["input.xml"] = domainXML
+	commands := r.customizeCommands(vmRef)
+	if len(commands) > 0 {
+		configMap.BinaryData["customize"] = []byte(strings.Join(commands, "\n") + "\n")
+	} else {
+		delete(configMap.BinaryData, "customize")
+	}
+	options := r.Plan.Spec.FindConversion(vmRef).Options
+	if len(options) > 0 {
+		configMap.BinaryData["options"] = []byte(strings.Join(options, "\n") + "\n")
+	} else {
+		delete(configMap.BinaryData, "options")
+	}
 	err = r.Destination.Client.Update(context.TODO(), configMap)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -1536,6 +2210,19 @@ func (r *KubeVirt) ensureLibvirtConfigMap(vmRef ref.Ref, vmCr *VirtualMachine, p
 	return
 }
 
+// Build the virt-customize commands for the VM.
+// Each customization ConfigMap is mounted in the
+// guest conversion pod at /mnt/customize/<index>.
+func (r *KubeVirt) customizeCommands(vmRef ref.Ref) (commands []string) {
+	for i, customization := range r.Plan.Spec.FindCustomization(vmRef) {
+		commands = append(
+			commands,
+			customization.Commands(fmt.Sprintf("/mnt/customize/%d", i))...)
+	}
+
+	return
+}
+
 // Build the config map.
 func (r *KubeVirt) configMap(vmRef ref.Ref) (object *core.ConfigMap, err error) {
 	object = &core.ConfigMap{
@@ -1778,10 +2465,12 @@ func (r *KubeVirt) ensureOpenStackVolumes(vm ref.Ref, ready bool) (pvcNames []st
 		return
 	}
 
-	storageName := r.Context.Map.Storage.Spec.Map[0].Destination.StorageClass
+	storageMap := r.Context.Map.Storage.Spec.Map
 
 	if len(openstackVm.Volumes) > 0 {
-		for _, vol := range openstackVm.Volumes {
+		for i := range openstackVm.Volumes {
+			vol := &openstackVm.Volumes[i]
+			storageName := openstackutil.OpenstackVolumeStorage(storageMap, openstackVm, vol).Destination.StorageClass
 			image := &openstack.Image{}
 			err = r.Source.Inventory.Find(image, ref.Ref{Name: fmt.Sprintf("%s-%s", r.Migration.Name, vol.ID)})
 			if err != nil {
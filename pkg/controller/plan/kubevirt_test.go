@@ -0,0 +1,204 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPersistentStateEnabled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kv := &cnv.KubeVirt{
+		ObjectMeta: meta.ObjectMeta{Namespace: "kubevirt", Name: "kubevirt"},
+	}
+	// Not configured.
+	enabled, err := persistentStateEnabled(fakeClient(kv.DeepCopy()))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(enabled).To(gomega.BeFalse())
+	// Other gates.
+	kv.Spec.Configuration.DeveloperConfiguration = &cnv.DeveloperConfiguration{
+		FeatureGates: []string{"Snapshot"},
+	}
+	enabled, err = persistentStateEnabled(fakeClient(kv.DeepCopy()))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(enabled).To(gomega.BeFalse())
+	// Enabled.
+	kv.Spec.Configuration.DeveloperConfiguration.FeatureGates = append(
+		kv.Spec.Configuration.DeveloperConfiguration.FeatureGates,
+		PersistentStateGate)
+	enabled, err = persistentStateEnabled(fakeClient(kv.DeepCopy()))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(enabled).To(gomega.BeTrue())
+}
+
+// Builder with an empty pod environment and config map.
+type conversionBuilder struct {
+	adapter.Builder
+}
+
+func (r *conversionBuilder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) ([]core.EnvVar, error) {
+	return nil, nil
+}
+
+func (r *conversionBuilder) ConfigMap(vmRef ref.Ref, secret *core.Secret, object *core.ConfigMap) error {
+	return nil
+}
+
+// Inventory finding every VM.
+type vmInventory struct {
+	web.Client
+}
+
+func (r *vmInventory) VM(ref *ref.Ref) (interface{}, error) {
+	return nil, nil
+}
+
+// Build the KubeVirt guest conversion for a vSphere plan.
+func conversionKubeVirt(p *api.Plan, objects ...client.Object) *KubeVirt {
+	vsphere := api.VSphere
+	host := api.OpenShift
+	p.Referenced.Provider.Source = &api.Provider{}
+	p.Referenced.Provider.Source.Spec.Type = &vsphere
+	p.Referenced.Provider.Destination = &api.Provider{}
+	p.Referenced.Provider.Destination.Spec.Type = &host
+	ctx := &plancontext.Context{
+		Plan:      p,
+		Migration: &api.Migration{},
+		Log:       logging.WithName("test"),
+	}
+	ctx.Source.Provider = p.Referenced.Provider.Source
+	ctx.Source.Inventory = &vmInventory{}
+	ctx.Destination.Provider = p.Referenced.Provider.Destination
+	ctx.Destination.Client = fakeClient(objects...)
+	return &KubeVirt{Context: ctx, Builder: &conversionBuilder{}}
+}
+
+// Build the guest conversion pod of the VM.
+func conversionPod(p *api.Plan, vm *plan.VMStatus) (*core.Pod, error) {
+	return conversionKubeVirt(p).guestConversionPod(
+		vm,
+		nil,
+		&core.ConfigMap{ObjectMeta: meta.ObjectMeta{Name: "domain"}},
+		&[]core.PersistentVolumeClaim{},
+		&core.Secret{ObjectMeta: meta.ObjectMeta{Name: "v2v"}},
+		nil)
+}
+
+// Builder of the persisted firmware state.
+type stateBuilder struct {
+	conversionBuilder
+	state planbase.PersistentState
+}
+
+func (r *stateBuilder) PersistentState(vmRef ref.Ref) (planbase.PersistentState, error) {
+	return r.state, nil
+}
+
+func TestPersistentStatePVC(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kv := &cnv.KubeVirt{
+		ObjectMeta: meta.ObjectMeta{Namespace: "kubevirt", Name: "kubevirt"},
+	}
+	kv.Spec.Configuration.DeveloperConfiguration = &cnv.DeveloperConfiguration{
+		FeatureGates: []string{PersistentStateGate},
+	}
+	p := &api.Plan{}
+	p.UID = "plan-1"
+	p.Spec.TargetNamespace = "test"
+	p.Spec.NVRAM = &plan.NVRAM{ReEnrollKeys: true}
+	vm := &plan.VMStatus{}
+	vm.ID = "vm-1"
+	vm.Name = "Web_01"
+	kubevirt := conversionKubeVirt(p, kv)
+	builder := &stateBuilder{state: planbase.PersistentState{EFI: true}}
+	kubevirt.Builder = builder
+
+	// Created for the (renamed) target VM.
+	pvc, err := kubevirt.ensurePersistentStatePVC(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.Name).To(gomega.Equal("web-01"))
+	g.Expect(vm.OriginalName).To(gomega.Equal("Web_01"))
+	g.Expect(pvc.Name).To(gomega.Equal("persistent-state-for-web-01"))
+	g.Expect(pvc.Labels[kVM]).To(gomega.Equal("vm-1"))
+	again, err := kubevirt.ensurePersistentStatePVC(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(again.ResourceVersion).To(gomega.Equal(pvc.ResourceVersion))
+
+	// Mounted by the conversion pod.
+	pod, err := kubevirt.guestConversionPod(
+		vm,
+		nil,
+		&core.ConfigMap{ObjectMeta: meta.ObjectMeta{Name: "domain"}},
+		&[]core.PersistentVolumeClaim{},
+		&core.Secret{ObjectMeta: meta.ObjectMeta{Name: "v2v"}},
+		pvc)
+	g.Expect(err).To(gomega.BeNil())
+	container := pod.Spec.Containers[0]
+	g.Expect(container.Env).To(gomega.ContainElements(
+		core.EnvVar{Name: "V2V_nvramFile", Value: "nvram/web-01_VARS.fd"},
+		core.EnvVar{Name: "V2V_reEnrollKeys", Value: "true"}))
+	g.Expect(container.VolumeMounts).To(gomega.ContainElement(
+		core.VolumeMount{Name: "nvram", MountPath: PersistentStateMount}))
+
+	// Deleted.
+	g.Expect(kubevirt.DeletePersistentStatePVC(vm)).To(gomega.Succeed())
+	_, found, err := kubevirt.persistentStatePVC(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(found).To(gomega.BeFalse())
+
+	// Not persisted.
+	builder.state.EFI = false
+	pvc, err = kubevirt.ensurePersistentStatePVC(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(pvc).To(gomega.BeNil())
+}
+
+// Builder mapping the source VM metadata.
+type metadataBuilder struct {
+	adapter.Builder
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func (r *metadataBuilder) Metadata(vmRef ref.Ref) (map[string]string, map[string]string, error) {
+	return r.labels, r.annotations, nil
+}
+
+func TestMapMetadata(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	kubevirt := &KubeVirt{
+		Context: &plancontext.Context{Plan: &api.Plan{}},
+		Builder: &metadataBuilder{
+			labels:      map[string]string{"env": "prod", "app": "mapped"},
+			annotations: map[string]string{"example.com/owner": "dba"},
+		},
+	}
+	vm := &plan.VMStatus{}
+	vm.ID = "vm-1"
+
+	// Existing labels are not replaced.
+	object := &cnv.VirtualMachine{}
+	object.Labels = map[string]string{"app": "template"}
+	g.Expect(kubevirt.mapMetadata(vm, object)).To(gomega.Succeed())
+	g.Expect(object.Labels).To(gomega.Equal(map[string]string{"env": "prod", "app": "template"}))
+	g.Expect(object.Annotations).To(gomega.Equal(map[string]string{"example.com/owner": "dba"}))
+
+	// Nothing mapped.
+	kubevirt.Builder = &metadataBuilder{}
+	object = &cnv.VirtualMachine{}
+	g.Expect(kubevirt.mapMetadata(vm, object)).To(gomega.Succeed())
+	g.Expect(object.Labels).To(gomega.BeNil())
+	g.Expect(object.Annotations).To(gomega.BeNil())
+}
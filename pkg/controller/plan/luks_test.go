@@ -0,0 +1,75 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateLUKS(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fakeClient(
+				&core.Secret{
+					ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "luks"},
+				}),
+		},
+	}
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.LUKS = &core.LocalObjectReference{Name: "luks"}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}, LUKS: &core.LocalObjectReference{Name: "vm-2"}},
+	}
+	g.Expect(p.Spec.FindLUKS(ref.Ref{ID: "vm-1"}).Name).To(gomega.Equal("luks"))
+	g.Expect(p.Spec.FindLUKS(ref.Ref{ID: "vm-2"}).Name).To(gomega.Equal("vm-2"))
+
+	// VM secret not found.
+	g.Expect(r.validateLUKS(p)).To(gomega.Succeed())
+	cnd := p.Status.FindCondition(LUKSNotValid)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Items).To(gomega.Equal([]string{"vm-2"}))
+
+	// Found.
+	p.Status.DeleteCondition(LUKSNotValid)
+	p.Spec.VMs[1].LUKS.Name = "luks"
+	g.Expect(r.validateLUKS(p)).To(gomega.Succeed())
+	g.Expect(p.Status.HasCondition(LUKSNotValid)).To(gomega.BeFalse())
+}
+
+func TestLUKSPodVolume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "test"
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}, LUKS: &core.LocalObjectReference{Name: "vm-2"}},
+	}
+	vm := &plan.VMStatus{}
+	vm.ID = "vm-1"
+
+	// No keys.
+	pod, err := conversionPod(p, vm)
+	g.Expect(err).To(gomega.BeNil())
+	for _, volume := range pod.Spec.Volumes {
+		g.Expect(volume.Name).ToNot(gomega.Equal("luks"))
+	}
+
+	// VM keys.
+	vm.ID = "vm-2"
+	pod, err = conversionPod(p, vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(pod.Spec.Volumes).To(gomega.ContainElement(gomega.And(
+		gomega.HaveField("Name", "luks"),
+		gomega.HaveField("VolumeSource.Secret.SecretName", "vm-2"))))
+	g.Expect(pod.Spec.Containers[0].VolumeMounts).To(gomega.ContainElement(
+		core.VolumeMount{Name: "luks", MountPath: "/etc/luks", ReadOnly: true}))
+}
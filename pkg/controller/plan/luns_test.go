@@ -0,0 +1,166 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Builder reporting the mapped LUNs.
+type lunBuilder struct {
+	planbase.Builder
+	volumes map[string]string
+}
+
+func (r *lunBuilder) MappedLUNs(vmRef ref.Ref) (map[string]string, error) {
+	return r.volumes, nil
+}
+
+// Build a LUN persistent volume.
+func lunVolume(name string, mode core.PersistentVolumeMode, phase core.PersistentVolumePhase) *core.PersistentVolume {
+	return &core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Spec: core.PersistentVolumeSpec{
+			VolumeMode:  &mode,
+			AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteMany},
+		},
+		Status: core.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func TestFindVolume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	luns := &plan.LUNs{
+		Volumes: []plan.LUNVolume{
+			{LUN: "naa.600a09803830303046244c554e4f5a4d", PersistentVolume: "lun-0"},
+		},
+	}
+	// vSphere LUN UUID (page 83 wrapper).
+	volume, found := luns.FindVolume("0200000000600A09803830303046244C554E4F5A4D4C554E20432D")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(volume).To(gomega.Equal("lun-0"))
+	// oVirt multipath WWID.
+	volume, found = luns.FindVolume("", "3600a09803830303046244c554e4f5a4d")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(volume).To(gomega.Equal("lun-0"))
+	// Partial and different identifiers.
+	_, found = luns.FindVolume("600a098038303030")
+	g.Expect(found).To(gomega.BeFalse())
+	_, found = luns.FindVolume("0200000000600a09803830303046244c554e4f5a4e4c554e20432d")
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestValidateLUNs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lun := lunVolume("lun-0", core.PersistentVolumeBlock, core.VolumeAvailable)
+	lun.Labels = map[string]string{plan.LUNNamespaceLabel: "test"}
+	fs := lunVolume("fs-0", core.PersistentVolumeFilesystem, core.VolumeAvailable)
+	fs.Labels = map[string]string{plan.LUNNamespaceLabel: "test"}
+	other := lunVolume("other-0", core.PersistentVolumeBlock, core.VolumeAvailable)
+	other.Labels = map[string]string{plan.LUNNamespaceLabel: "other"}
+	bound := lunVolume("bound-0", core.PersistentVolumeBlock, core.VolumeBound)
+	bound.Labels = map[string]string{plan.LUNNamespaceLabel: "test"}
+	bound.Spec.ClaimRef = &core.ObjectReference{Namespace: "test", Name: "data", UID: "pvc-1"}
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fakeClient(lun, fs, other, bound),
+		},
+	}
+	p := &api.Plan{}
+	p.Spec.TargetNamespace = "test"
+	host := api.OpenShift
+	p.Referenced.Provider.Destination = &api.Provider{}
+	p.Referenced.Provider.Destination.Spec.Type = &host
+	p.Spec.LUNs = &plan.LUNs{
+		Volumes: []plan.LUNVolume{
+			{LUN: "naa.600a09803830303046244c554e4f5a4d", PersistentVolume: "lun-0"},
+		},
+	}
+	g.Expect(r.validateLUNs(p)).To(gomega.Succeed())
+	g.Expect(p.Status.HasCondition(LUNsNotValid)).To(gomega.BeFalse())
+
+	// PV not found.
+	p.Spec.LUNs.Volumes[0].PersistentVolume = "missing"
+	g.Expect(r.validateLUNs(p)).To(gomega.Succeed())
+	g.Expect(p.Status.FindCondition(LUNsNotValid).Reason).To(gomega.Equal(NotFound))
+
+	// PV not claimable: not block, other namespace, bound to another claim.
+	for _, name := range []string{"fs-0", "other-0", "bound-0"} {
+		p.Status.DeleteCondition(LUNsNotValid)
+		p.Spec.LUNs.Volumes[0].PersistentVolume = name
+		g.Expect(r.validateLUNs(p)).To(gomega.Succeed())
+		cnd := p.Status.FindCondition(LUNsNotValid)
+		g.Expect(cnd).ToNot(gomega.BeNil())
+		g.Expect(cnd.Reason).To(gomega.Equal(NotValid))
+		g.Expect(cnd.Items).To(gomega.Equal([]string{name}))
+	}
+}
+
+func TestEnsureLunClaims(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	labeled := lunVolume("lun-0", core.PersistentVolumeBlock, core.VolumeAvailable)
+	labeled.Labels = map[string]string{plan.LUNNamespaceLabel: "test"}
+	preBound := lunVolume("lun-1", core.PersistentVolumeBlock, core.VolumeAvailable)
+	preBound.Spec.ClaimRef = &core.ObjectReference{Namespace: "test", Name: "data"}
+	unlabeled := lunVolume("lun-2", core.PersistentVolumeBlock, core.VolumeAvailable)
+	p := &api.Plan{}
+	p.Name = "plan"
+	p.UID = types.UID("plan-1")
+	p.Spec.TargetNamespace = "test"
+	ctx := &plancontext.Context{
+		Plan:      p,
+		Migration: &api.Migration{},
+		Log:       logging.WithName("test"),
+	}
+	ctx.Destination.Client = fakeClient(labeled, preBound, unlabeled)
+	builder := &lunBuilder{
+		volumes: map[string]string{
+			"disk-0": "lun-0",
+			"disk-1": "lun-1",
+		},
+	}
+	kubevirt := &KubeVirt{Context: ctx, Builder: builder}
+	vm := &plan.VMStatus{}
+	vm.ID = "vm-1"
+	listPVCs := func() map[string]core.PersistentVolumeClaim {
+		list := &core.PersistentVolumeClaimList{}
+		g.Expect(ctx.Destination.Client.List(
+			context.TODO(),
+			list,
+			client.InNamespace("test"))).To(gomega.Succeed())
+		pvcs := map[string]core.PersistentVolumeClaim{}
+		for _, pvc := range list.Items {
+			pvcs[pvc.Spec.VolumeName] = pvc
+		}
+		return pvcs
+	}
+	g.Expect(kubevirt.EnsureLunClaims(vm)).To(gomega.Succeed())
+	pvcs := listPVCs()
+	g.Expect(pvcs).To(gomega.HaveLen(2))
+	g.Expect(pvcs["lun-0"].Annotations[planbase.AnnDiskSource]).To(gomega.Equal("disk-0"))
+	g.Expect(pvcs["lun-0"].Labels[kVM]).To(gomega.Equal("vm-1"))
+	g.Expect(*pvcs["lun-0"].Spec.VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+	g.Expect(pvcs["lun-1"].Name).To(gomega.Equal("data"))
+	g.Expect(pvcs["lun-1"].Annotations[planbase.AnnDiskSource]).To(gomega.Equal("disk-1"))
+
+	// Claims are not duplicated.
+	g.Expect(kubevirt.EnsureLunClaims(vm)).To(gomega.Succeed())
+	g.Expect(listPVCs()).To(gomega.HaveLen(2))
+
+	// Volume not permitted in the target namespace.
+	builder.volumes["disk-2"] = "lun-2"
+	g.Expect(kubevirt.EnsureLunClaims(vm)).ToNot(gomega.Succeed())
+	g.Expect(listPVCs()).To(gomega.HaveLen(2))
+}
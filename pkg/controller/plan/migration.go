@@ -16,7 +16,9 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/notifier"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
@@ -43,6 +45,8 @@ var (
 	RequiresConversion libitr.Flag = 0x04
 	CDIDiskCopy        libitr.Flag = 0x08
 	VirtV2vDiskCopy    libitr.Flag = 0x10
+	HasVerification    libitr.Flag = 0x20
+	HasDecommission    libitr.Flag = 0x40
 )
 
 // Phases.
@@ -67,6 +71,9 @@ const (
 	ConvertGuest             = "ConvertGuest"
 	CopyDisksVirtV2V         = "CopyDisksVirtV2V"
 	PostHook                 = "PostHook"
+	StartVerification        = "StartVerification"
+	WaitForVerification      = "WaitForVerification"
+	DecommissionSource       = "DecommissionSource"
 	Completed                = "Completed"
 	WaitForSnapshot          = "WaitForSnapshot"
 	WaitForInitialSnapshot   = "WaitForInitialSnapshot"
@@ -78,10 +85,12 @@ const (
 	Initialize      = "Initialize"
 	Cutover         = "Cutover"
 	DiskAllocation  = "DiskAllocation"
-	DiskTransfer    = "DiskTransfer"
+	DiskTransfer    = planbase.DiskTransfer
 	ImageConversion = "ImageConversion"
 	DiskTransferV2v = "DiskTransferV2v"
 	VMCreation      = "VirtualMachineCreation"
+	Verification    = "Verification"
+	Decommission    = "Decommission"
 	Unknown         = "Unknown"
 )
 
@@ -90,6 +99,12 @@ const (
 	On = "On"
 )
 
+// Size (bytes) of the guest customization output
+// reflected on the step.
+const (
+	CustomizationOutputLimit = 512
+)
+
 var (
 	coldItinerary = libitr.Itinerary{
 		Name: "",
@@ -107,6 +122,9 @@ var (
 			{Name: CopyDisksVirtV2V, All: RequiresConversion},
 			{Name: CreateVM},
 			{Name: PostHook, All: HasPostHook},
+			{Name: StartVerification, All: HasVerification},
+			{Name: WaitForVerification, All: HasVerification},
+			{Name: DecommissionSource, All: HasDecommission},
 			{Name: Completed},
 		},
 	}
@@ -134,6 +152,9 @@ var (
 			{Name: ConvertGuest, All: RequiresConversion},
 			{Name: CreateVM},
 			{Name: PostHook, All: HasPostHook},
+			{Name: StartVerification, All: HasVerification},
+			{Name: WaitForVerification, All: HasVerification},
+			{Name: DecommissionSource, All: HasDecommission},
 			{Name: Completed},
 		},
 	}
@@ -148,6 +169,8 @@ type Migration struct {
 	kubevirt KubeVirt
 	// Source client.
 	provider adapter.Client
+	// Source validator.
+	validator adapter.Validator
 	// VirtualMachine CRs.
 	vmMap VirtualMachineMap
 	// VM scheduler
@@ -179,6 +202,7 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 	}
 
 	r.resolveCanceledRefs()
+	r.resolveCutoverRefs()
 
 	for _, vm := range r.runningVMs() {
 		err = r.execute(vm)
@@ -221,11 +245,15 @@ func (r *Migration) init() (err error) {
 	if err != nil {
 		return
 	}
+	r.validator, err = adapter.Validator(r.Plan)
+	if err != nil {
+		return
+	}
 	r.kubevirt = KubeVirt{
 		Context: r.Context,
 		Builder: r.builder,
 	}
-	r.scheduler, err = scheduler.New(r.Context)
+	r.scheduler, err = scheduler.New(r.Context, r.builder)
 	if err != nil {
 		return
 	}
@@ -249,6 +277,7 @@ func (r *Migration) begin() (err error) {
 			Message:  "The plan is EXECUTING.",
 			Durable:  true,
 		})
+	r.notifyPlan(notifier.PlanStarted, "The plan is EXECUTING.")
 	err = r.kubevirt.EnsureNamespace()
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -292,7 +321,7 @@ func (r *Migration) begin() (err error) {
 				err = liberr.Wrap(pErr)
 				return
 			}
-			status.DeleteCondition(Canceled, Failed)
+			status.DeleteCondition(Canceled, Failed, VerificationFailed)
 			status.MarkReset()
 			status.Pipeline = pipeline
 			status.Phase = step.Name
@@ -383,8 +412,10 @@ func (r *Migration) Cancel() (err error) {
 }
 
 // Delete left over migration resources associated with a VM.
+// The target VM (and disks) are retained when the migration
+// succeeded or only the verification failed.
 func (r *Migration) CleanUp(vm *plan.VMStatus) (err error) {
-	if vm.HasCondition(Succeeded) {
+	if vm.HasAnyCondition(Succeeded, VerificationFailed) {
 		err = r.deleteImporterPods(vm)
 		if err != nil {
 			return
@@ -394,6 +425,10 @@ func (r *Migration) CleanUp(vm *plan.VMStatus) (err error) {
 		if err != nil {
 			return
 		}
+		err = r.kubevirt.DeletePersistentStatePVC(vm)
+		if err != nil {
+			return
+		}
 	}
 	err = r.kubevirt.DeletePVCConsumerPod(vm)
 	if err != nil {
@@ -451,6 +486,15 @@ func (r *Migration) resolveCanceledRefs() {
 	}
 }
 
+// Best effort attempt to resolve cutover refs.
+func (r *Migration) resolveCutoverRefs() {
+	for i := range r.Context.Migration.Spec.Cutovers {
+		// resolve the VM ref in place
+		ref := &r.Context.Migration.Spec.Cutovers[i].Ref
+		_, _ = r.Source.Inventory.VM(ref)
+	}
+}
+
 func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 	vms = make([]*plan.VMStatus, 0)
 	for i := range r.Plan.Status.Migration.VMs {
@@ -504,6 +548,10 @@ func (r *Migration) step(vm *plan.VMStatus) (step string) {
 		step = DiskTransferV2v
 	case CreateVM:
 		step = VMCreation
+	case StartVerification, WaitForVerification:
+		step = Verification
+	case DecommissionSource:
+		step = Decommission
 	case PreHook, PostHook:
 		step = vm.Phase
 	case StorePowerState, PowerOffSource, WaitForPowerOff:
@@ -521,6 +569,8 @@ func (r *Migration) step(vm *plan.VMStatus) (step string) {
 // Steps a VM through the migration itinerary
 // and updates its status.
 func (r *Migration) execute(vm *plan.VMStatus) (err error) {
+	before := captureState(vm)
+	defer r.transition(vm, before)
 	// check whether the VM has been canceled by the user
 	if r.Context.Migration.Spec.Canceled(vm.Ref) {
 		vm.SetCondition(
@@ -543,6 +593,12 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		vm:      &vm.VM,
 		context: r.Context,
 	}
+	if vm.Warm != nil {
+		switch r.step(vm) {
+		case Initialize, PreHook, DiskTransfer:
+			vm.Warm.Cutover = r.Context.Migration.Spec.FindCutover(vm.Ref)
+		}
+	}
 
 	r.Log.Info(
 		"Migration [RUN]",
@@ -565,6 +621,16 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		vm.MarkStarted()
 		step.MarkStarted()
 		step.Phase = Running
+		concerns, cErr := r.validator.Concerns(vm.Ref)
+		if cErr != nil {
+			r.Log.Error(
+				cErr,
+				"Concerns not found.",
+				"vm",
+				vm.String())
+		}
+		vm.Concerns = concerns
+		r.captureDisks(vm)
 		err = r.CleanUp(vm)
 		if err != nil {
 			step.AddError(err.Error())
@@ -623,6 +689,12 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			r.Log.Info("PreTransferActions hook isn't ready yet")
 			return
 		}
+		err = r.kubevirt.EnsureLunClaims(vm)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
 
 		if r.kubevirt.useOvirtPopulator(vm) {
 			pvcNames, err = r.kubevirt.createVolumesForOvirt(vm.Ref)
@@ -707,6 +779,66 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		step.MarkCompleted()
 		step.Phase = Completed
 		vm.Phase = r.next(vm.Phase)
+	case StartVerification:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		step.MarkStarted()
+		step.Phase = Running
+		err = r.startVerification(vm)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		vm.Phase = r.next(vm.Phase)
+	case WaitForVerification:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		verified, failure, vErr := r.verifyVM(vm, step)
+		if vErr != nil {
+			err = liberr.Wrap(vErr)
+			return
+		}
+		if failure != "" {
+			err = r.verificationFailed(vm, step, failure)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			break
+		}
+		if !verified {
+			break
+		}
+		err = r.endVerification(vm)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		step.Reason = ""
+		step.Progress.Completed = step.Progress.Total
+		step.MarkCompleted()
+		step.Phase = Completed
+		vm.Phase = r.next(vm.Phase)
+	case DecommissionSource:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		step.MarkStarted()
+		r.decommission(vm)
+		step.Progress.Completed = step.Progress.Total
+		step.MarkCompleted()
+		step.Phase = Completed
+		vm.Phase = r.next(vm.Phase)
 	case AllocateDisks, CopyDisks:
 		step, found := vm.FindStep(r.step(vm))
 		if !found {
@@ -744,6 +876,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 
 			if ready {
 				step.Phase = Completed
+				r.recordThroughput(vm, step)
 				vm.Phase = r.next(vm.Phase)
 				break
 			} else {
@@ -762,17 +895,22 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		if step.MarkedCompleted() && !step.HasError() {
 			if r.Plan.Spec.Warm {
 				now := meta.Now()
-				next := meta.NewTime(now.Add(time.Duration(Settings.PrecopyInterval) * time.Minute))
 				n := len(vm.Warm.Precopies)
 				vm.Warm.Precopies[n-1].End = &now
+				vm.Warm.Interval = nextInterval(r.precopyPolicy(vm), vm.Warm.Precopies)
+				next := meta.NewTime(now.Add(time.Duration(vm.Warm.Interval) * time.Minute))
 				vm.Warm.NextPrecopyAt = &next
 				vm.Warm.Successes++
 			}
 			step.Phase = Completed
+			// Only the initial (full) copy is representative.
+			if vm.Warm == nil || len(vm.Warm.Precopies) == 1 {
+				r.recordThroughput(vm, step)
+			}
 			vm.Phase = r.next(vm.Phase)
 		}
 	case CopyingPaused:
-		if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
+		if vm.Warm.Cutover != nil && !vm.Warm.Cutover.After(time.Now()) {
 			vm.Phase = StorePowerState
 		} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
 			vm.Phase = CreateSnapshot
@@ -822,6 +960,9 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			break
 		}
 
+		if vm.Phase == AddCheckpoint {
+			r.measurePrecopy(vm)
+		}
 		err = r.setDataVolumeCheckpoints(vm)
 		if err != nil {
 			step.AddError(err.Error())
@@ -944,6 +1085,9 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		}
 		if step.MarkedCompleted() && !step.HasError() {
 			step.Phase = Completed
+			if vm.Phase == CopyDisksVirtV2V {
+				r.recordThroughput(vm, step)
+			}
 			vm.Phase = r.next(vm.Phase)
 		}
 	case Completed:
@@ -1133,6 +1277,28 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case StartVerification:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        Verification,
+						Description: "Boot and verify the VM.",
+						Phase:       Pending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
+		case DecommissionSource:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        Decommission,
+						Description: "Decommission the source VM.",
+						Phase:       Pending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		}
 		next, done, _ := r.itinerary().Next(step.Name)
 		if !done {
@@ -1181,6 +1347,7 @@ func (r *Migration) end() (completed bool, err error) {
 				Message:  "The plan execution has FAILED.",
 				Durable:  true,
 			})
+		r.notifyPlan(notifier.PlanFailed, "The plan execution has FAILED.")
 	} else if succeeded > 0 {
 		// if the migration didn't fail and at least one VM succeeded,
 		// then the migration succeeded.
@@ -1193,6 +1360,7 @@ func (r *Migration) end() (completed bool, err error) {
 				Message:  "The plan execution has SUCCEEDED.",
 				Durable:  true,
 			})
+		r.notifyPlan(notifier.PlanSucceeded, "The plan execution has SUCCEEDED.")
 	} else {
 		// if there were no failures or successes, but
 		// all the VMs are complete, then the migration must
@@ -1206,7 +1374,9 @@ func (r *Migration) end() (completed bool, err error) {
 				Message:  "The plan execution has been CANCELED.",
 				Durable:  true,
 			})
+		r.notifyPlan(notifier.PlanCanceled, "The plan execution has been CANCELED.")
 	}
+	r.report()
 
 	completed = true
 	return
@@ -1446,7 +1616,11 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 			step.Progress.Completed = step.Progress.Total
 		case core.PodFailed:
 			step.MarkCompleted()
-			step.AddError("Guest conversion failed. See pod logs for details.")
+			if r.reflectCustomization(pod, step) {
+				step.AddError("Guest customization failed. See the step annotations for details.")
+			} else {
+				step.AddError("Guest conversion failed. See pod logs for details.")
+			}
 		default:
 			if r.Context.UseEl9VirtV2v() {
 				err = r.updateConversionProgressEl9(pod, step)
@@ -1465,6 +1639,34 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 	return
 }
 
+// Reflect the output of a failed guest customization reported
+// by the conversion pod (termination message) on the step.
+// The output is truncated to the last CustomizationOutputLimit bytes.
+// Returns true when the guest customization failed.
+func (r *Migration) reflectCustomization(pod *core.Pod, step *plan.Step) (failed bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != "virt-v2v" || status.State.Terminated == nil {
+			continue
+		}
+		message := strings.TrimSpace(status.State.Terminated.Message)
+		if message == "" {
+			continue
+		}
+		if len(message) > CustomizationOutputLimit {
+			message = strings.ToValidUTF8(
+				"..."+message[len(message)-CustomizationOutputLimit:],
+				"")
+		}
+		if step.Annotations == nil {
+			step.Annotations = make(map[string]string)
+		}
+		step.Annotations["customization"] = message
+		failed = true
+	}
+
+	return
+}
+
 func (r *Migration) updateConversionProgressEl9(pod *core.Pod, step *plan.Step) (err error) {
 	if pod.Status.PodIP == "" {
 		return
@@ -1616,6 +1818,13 @@ func (r *Predicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		allowed = !r.context.UseEl9VirtV2v()
 	case VirtV2vDiskCopy:
 		allowed = r.context.UseEl9VirtV2v()
+	case HasVerification:
+		allowed = r.context.Plan.Spec.Verification != nil
+	case HasDecommission:
+		decommission := r.context.Plan.Spec.Decommission
+		allowed = decommission != nil &&
+			!decommission.Undo &&
+			len(decommission.Actions()) > 0
 	}
 
 	return
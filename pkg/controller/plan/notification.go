@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/notifier"
+	core "k8s.io/api/core/v1"
+)
+
+// Event reasons.
+const (
+	VMPhaseChanged       = "VMPhaseChanged"
+	NotificationNotValid = "NotificationNotValid"
+)
+
+// VM state captured before it is stepped
+// through the itinerary.
+type vmState struct {
+	phase     string
+	succeeded bool
+	failed    bool
+	canceled  bool
+}
+
+// Capture the VM state.
+func captureState(vm *plan.VMStatus) vmState {
+	return vmState{
+		phase:     vm.Phase,
+		succeeded: vm.HasCondition(Succeeded),
+		failed:    vm.HasCondition(Failed),
+		canceled:  vm.HasCondition(Canceled),
+	}
+}
+
+// Record the VM phase transition as an `Event` on the
+// plan and migration and notify external systems of
+// lifecycle changes.
+func (r *Migration) transition(vm *plan.VMStatus, before vmState) {
+	if vm.Phase != before.phase {
+		r.record(
+			core.EventTypeNormal,
+			VMPhaseChanged,
+			fmt.Sprintf(
+				"VM %s phase changed: %s -> %s.",
+				vm.String(),
+				before.phase,
+				vm.Phase))
+		switch {
+		case before.phase == Started:
+			r.notifyVM(notifier.VMStarted, vm, "The VM migration has started.")
+		case vm.Phase == CopyingPaused &&
+			vm.Warm != nil &&
+			vm.Warm.Cutover == nil &&
+			len(vm.Warm.Precopies) == 1:
+			r.notifyVM(notifier.VMWaitingForCutover, vm, "The VM is waiting for cutover.")
+		}
+	}
+	switch {
+	case !before.succeeded && vm.HasCondition(Succeeded):
+		r.notifyVM(notifier.VMSucceeded, vm, "The VM migration has SUCCEEDED.")
+	case !before.failed && vm.HasCondition(Failed):
+		r.record(
+			core.EventTypeWarning,
+			Failed,
+			fmt.Sprintf(
+				"VM %s migration has FAILED.",
+				vm.String()))
+		r.notifyVM(notifier.VMFailed, vm, "The VM migration has FAILED.")
+	case !before.canceled && vm.HasCondition(Canceled):
+		r.notifyVM(notifier.VMCanceled, vm, "The VM migration has been canceled.")
+	}
+}
+
+// Record an `Event` on the plan and the migration.
+func (r *Migration) record(eventType, reason, message string) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(r.Plan, eventType, reason, message)
+	if r.Migration.UID != "" {
+		r.Recorder.Event(r.Migration, eventType, reason, message)
+	}
+}
+
+// Notify external systems of a VM lifecycle change.
+func (r *Migration) notifyVM(eventType string, vm *plan.VMStatus, message string) {
+	data := r.eventData(message)
+	data.VM = &notifier.VM{
+		ID:    vm.ID,
+		Name:  vm.Name,
+		Phase: vm.Phase,
+	}
+	if vm.Error != nil {
+		data.VM.Error = strings.Join(vm.Error.Reasons, "; ")
+	}
+	r.notify(eventType, data)
+}
+
+// Notify external systems of a plan lifecycle change.
+func (r *Migration) notifyPlan(eventType string, message string) {
+	r.notify(eventType, r.eventData(message))
+}
+
+// Build the event data.
+func (r *Migration) eventData(message string) (data notifier.Data) {
+	data = notifier.Data{
+		Plan: notifier.Resource{
+			Namespace: r.Plan.Namespace,
+			Name:      r.Plan.Name,
+			UID:       string(r.Plan.UID),
+		},
+		Message: message,
+	}
+	if r.Migration.UID != "" {
+		data.Migration = &notifier.Resource{
+			Namespace: r.Migration.Namespace,
+			Name:      r.Migration.Name,
+			UID:       string(r.Migration.UID),
+		}
+	}
+
+	return
+}
+
+// Queue the event for delivery to the targets
+// configured in the plan namespace.
+// Notification is best-effort and never fails the migration.
+// Targets skipped because the signing Secret is not found
+// are reported as a warning `Event` when (re)loaded.
+func (r *Migration) notify(eventType string, data notifier.Data) {
+	resolved, loaded, err := notifier.DefaultCache.Targets(r.Client, r.Plan.Namespace)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Couldn't find notification targets.")
+		return
+	}
+	if loaded && len(resolved.NotFound) > 0 {
+		r.record(
+			core.EventTypeWarning,
+			NotificationNotValid,
+			fmt.Sprintf(
+				"Notification targets skipped, signing Secret not found: %s.",
+				strings.Join(resolved.NotFound, ", ")))
+	}
+	if len(resolved.Targets) == 0 {
+		return
+	}
+	notifier.Default.Send(resolved.Targets, notifier.New(eventType, data))
+}
@@ -0,0 +1,437 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"gopkg.in/yaml.v2"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Package logger.
+var log = logging.WithName("notifier")
+
+// Event types.
+const (
+	PlanStarted         = "io.konveyor.forklift.plan.started"
+	PlanSucceeded       = "io.konveyor.forklift.plan.succeeded"
+	PlanFailed          = "io.konveyor.forklift.plan.failed"
+	PlanCanceled        = "io.konveyor.forklift.plan.canceled"
+	VMStarted           = "io.konveyor.forklift.vm.started"
+	VMWaitingForCutover = "io.konveyor.forklift.vm.cutover.waiting"
+	VMReadyForCutover   = "io.konveyor.forklift.vm.cutover.ready"
+	VMSucceeded         = "io.konveyor.forklift.vm.succeeded"
+	VMFailed            = "io.konveyor.forklift.vm.failed"
+	VMCanceled          = "io.konveyor.forklift.vm.canceled"
+)
+
+// Configuration.
+const (
+	// Label selecting the ConfigMaps that define
+	// the notification targets in a namespace.
+	ConfigLabel = "forklift.konveyor.io/notifications"
+	// ConfigMap key containing the (yaml) list of targets.
+	TargetsKey = "targets"
+	// Secret key containing the HMAC signing key.
+	SecretKey = "key"
+	// Header containing the HMAC-SHA256 payload signature.
+	SignatureHeader = "X-Forklift-Signature"
+	// CloudEvents (structured mode) content type.
+	ContentType = "application/cloudevents+json"
+	// CloudEvents spec version.
+	SpecVersion = "1.0"
+	// Default number of delivery retries.
+	DefaultRetries = 3
+	// Delivery queue size.
+	QueueSize = 1000
+	// Number of delivery workers.
+	Workers = 4
+	// Request timeout.
+	Timeout = 10 * time.Second
+	// Targets cache time to live.
+	CacheTTL = time.Minute
+)
+
+// Delivery backoff.
+var (
+	Backoff    = time.Second
+	MaxBackoff = time.Minute
+)
+
+// Notification target.
+type Target struct {
+	// Target name.
+	Name string `yaml:"name"`
+	// Webhook URL.
+	URL string `yaml:"url"`
+	// Event types. Trailing `*` matches by prefix.
+	// Empty matches all events.
+	Events []string `yaml:"events,omitempty"`
+	// Name of the Secret (in the same namespace)
+	// containing the HMAC signing key.
+	Secret string `yaml:"secret,omitempty"`
+	// Number of delivery retries.
+	Retries *int `yaml:"retries,omitempty"`
+	// HMAC signing key (resolved).
+	key []byte
+}
+
+// Match the event type.
+func (r *Target) Match(eventType string) (matched bool) {
+	if len(r.Events) == 0 {
+		matched = true
+		return
+	}
+	for _, filter := range r.Events {
+		if strings.HasSuffix(filter, "*") {
+			matched = strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*"))
+		} else {
+			matched = filter == eventType
+		}
+		if matched {
+			break
+		}
+	}
+
+	return
+}
+
+// Number of delivery retries.
+func (r *Target) retries() (n int) {
+	n = DefaultRetries
+	if r.Retries != nil && *r.Retries >= 0 {
+		n = *r.Retries
+	}
+	return
+}
+
+// CloudEvent (structured mode).
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+// Event data.
+type Data struct {
+	// Plan.
+	Plan Resource `json:"plan"`
+	// Migration.
+	Migration *Resource `json:"migration,omitempty"`
+	// VM.
+	VM *VM `json:"vm,omitempty"`
+	// Message.
+	Message string `json:"message,omitempty"`
+}
+
+// Resource reference.
+type Resource struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// VM details.
+type VM struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Phase string `json:"phase,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Build a new event.
+func New(eventType string, data Data) (event Event) {
+	event = Event{
+		SpecVersion: SpecVersion,
+		ID:          uuid.New().String(),
+		Source: fmt.Sprintf(
+			"/apis/forklift.konveyor.io/v1beta1/namespaces/%s/plans/%s",
+			data.Plan.Namespace,
+			data.Plan.Name),
+		Type:            eventType,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	if data.VM != nil {
+		event.Subject = data.VM.ID
+	}
+
+	return
+}
+
+// Resolved notification targets.
+type Resolved struct {
+	// Targets.
+	Targets []Target
+	// Targets skipped because the signing
+	// Secret was not found.
+	NotFound []string
+}
+
+// Find the notification targets defined in the namespace.
+// The HMAC signing keys are resolved.
+func Targets(cl client.Client, namespace string) (resolved Resolved, err error) {
+	list := &core.ConfigMapList{}
+	err = cl.List(
+		context.TODO(),
+		list,
+		client.InNamespace(namespace),
+		client.MatchingLabels{ConfigLabel: "true"})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		cm := &list.Items[i]
+		parsed, pErr := Parse(cm.Data[TargetsKey])
+		if pErr != nil {
+			log.Error(
+				pErr,
+				"Notification targets not valid.",
+				"configMap",
+				cm.Namespace+"/"+cm.Name)
+			continue
+		}
+		for _, target := range parsed {
+			if target.Secret != "" {
+				secret := &core.Secret{}
+				err = cl.Get(
+					context.TODO(),
+					client.ObjectKey{
+						Namespace: namespace,
+						Name:      target.Secret,
+					},
+					secret)
+				if err != nil {
+					if k8serr.IsNotFound(err) {
+						err = nil
+						resolved.NotFound = append(resolved.NotFound, target.Name)
+						continue
+					}
+					err = liberr.Wrap(err)
+					return
+				}
+				target.key = secret.Data[SecretKey]
+			}
+			resolved.Targets = append(resolved.Targets, target)
+		}
+	}
+
+	return
+}
+
+// Notification targets cached by namespace.
+type Cache struct {
+	// Time to live.
+	TTL time.Duration
+	// Cached targets by namespace.
+	entries map[string]cached
+	// Protect the entries.
+	mutex sync.Mutex
+}
+
+// Cached targets.
+type cached struct {
+	resolved Resolved
+	loaded   time.Time
+}
+
+// Default cache.
+var DefaultCache = &Cache{TTL: CacheTTL}
+
+// Find the (cached) notification targets defined in the
+// namespace. Loaded reports the targets were (re)loaded.
+func (r *Cache) Targets(cl client.Client, namespace string) (resolved Resolved, loaded bool, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if entry, found := r.entries[namespace]; found && time.Since(entry.loaded) < r.TTL {
+		resolved = entry.resolved
+		return
+	}
+	resolved, err = Targets(cl, namespace)
+	if err != nil {
+		return
+	}
+	if r.entries == nil {
+		r.entries = make(map[string]cached)
+	}
+	r.entries[namespace] = cached{
+		resolved: resolved,
+		loaded:   time.Now(),
+	}
+	loaded = true
+	return
+}
+
+// Parse the (yaml) list of targets.
+func Parse(document string) (targets []Target, err error) {
+	err = yaml.Unmarshal([]byte(document), &targets)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, target := range targets {
+		if target.URL == "" {
+			err = liberr.New(
+				"Target URL not specified.",
+				"target",
+				target.Name)
+			return
+		}
+	}
+
+	return
+}
+
+// Sign the payload using HMAC-SHA256.
+func Sign(key, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Pending delivery.
+type delivery struct {
+	target  Target
+	event   Event
+	payload []byte
+}
+
+// Asynchronous event dispatcher.
+type Dispatcher struct {
+	// HTTP client.
+	Client *http.Client
+	// Delivery queue.
+	queue chan delivery
+	// Start once.
+	once sync.Once
+}
+
+// Default dispatcher.
+var Default = &Dispatcher{}
+
+// Queue the event for delivery to each matching target.
+// Delivery is best-effort; events are dropped when the
+// queue is full.
+func (r *Dispatcher) Send(targets []Target, event Event) {
+	r.once.Do(r.start)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error(err, "Event could not be encoded.", "type", event.Type)
+		return
+	}
+	for _, target := range targets {
+		if !target.Match(event.Type) {
+			continue
+		}
+		select {
+		case r.queue <- delivery{target: target, event: event, payload: payload}:
+		default:
+			log.Info(
+				"Notification queue full, event dropped.",
+				"target",
+				target.Name,
+				"type",
+				event.Type)
+		}
+	}
+}
+
+// Start the workers.
+func (r *Dispatcher) start() {
+	if r.Client == nil {
+		r.Client = &http.Client{Timeout: Timeout}
+	}
+	r.queue = make(chan delivery, QueueSize)
+	for i := 0; i < Workers; i++ {
+		go func() {
+			for d := range r.queue {
+				r.deliver(d)
+			}
+		}()
+	}
+}
+
+// Deliver the event with retries.
+func (r *Dispatcher) deliver(d delivery) {
+	backoff := Backoff
+	retries := d.target.retries()
+	for attempt := 0; ; attempt++ {
+		err := r.post(d)
+		if err == nil {
+			log.V(1).Info(
+				"Event delivered.",
+				"target",
+				d.target.Name,
+				"type",
+				d.event.Type,
+				"id",
+				d.event.ID)
+			return
+		}
+		if attempt >= retries {
+			log.Error(
+				err,
+				"Event delivery failed.",
+				"target",
+				d.target.Name,
+				"type",
+				d.event.Type,
+				"id",
+				d.event.ID)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > MaxBackoff {
+			backoff = MaxBackoff
+		}
+	}
+}
+
+// Post the event to the target.
+func (r *Dispatcher) post(d delivery) (err error) {
+	request, err := http.NewRequest(http.MethodPost, d.target.URL, bytes.NewReader(d.payload))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", ContentType)
+	if len(d.target.key) > 0 {
+		request.Header.Set(SignatureHeader, Sign(d.target.key, d.payload))
+	}
+	response, err := r.Client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Target responded: %s.",
+				response.Status))
+	}
+
+	return
+}
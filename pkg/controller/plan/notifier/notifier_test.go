@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	target := Target{}
+	g.Expect(target.Match(VMFailed)).To(gomega.BeTrue())
+
+	target.Events = []string{PlanFailed, "io.konveyor.forklift.vm.*"}
+	g.Expect(target.Match(PlanFailed)).To(gomega.BeTrue())
+	g.Expect(target.Match(VMSucceeded)).To(gomega.BeTrue())
+	g.Expect(target.Match(PlanSucceeded)).To(gomega.BeFalse())
+}
+
+func TestParse(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	targets, err := Parse(`
+- name: ops
+  url: http://ops.example.com/hook
+  events:
+  - io.konveyor.forklift.plan.failed
+  secret: ops
+  retries: 0
+`)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(targets).To(gomega.HaveLen(1))
+	g.Expect(targets[0].Name).To(gomega.Equal("ops"))
+	g.Expect(targets[0].Secret).To(gomega.Equal("ops"))
+	g.Expect(targets[0].retries()).To(gomega.Equal(0))
+
+	_, err = Parse(`[{name: missing}]`)
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestDeliver(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	Backoff = time.Millisecond
+
+	var attempts int32
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt.
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign([]byte("secret"), body) ||
+			r.Header.Get("Content-Type") != ContentType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		event := Event{}
+		_ = json.Unmarshal(body, &event)
+		received <- event
+	}))
+	defer server.Close()
+
+	dispatcher := &Dispatcher{}
+	targets := []Target{
+		{Name: "ignored", URL: server.URL, Events: []string{PlanFailed}},
+		{Name: "matched", URL: server.URL, key: []byte("secret")},
+	}
+	dispatcher.Send(
+		targets,
+		New(VMFailed, Data{
+			Plan: Resource{Namespace: "ns", Name: "plan"},
+			VM:   &VM{ID: "vm-1"},
+		}))
+
+	var event Event
+	g.Eventually(received, 5*time.Second).Should(gomega.Receive(&event))
+	g.Expect(event.Type).To(gomega.Equal(VMFailed))
+	g.Expect(event.SpecVersion).To(gomega.Equal(SpecVersion))
+	g.Expect(event.Subject).To(gomega.Equal("vm-1"))
+	g.Expect(event.Source).To(gomega.Equal("/apis/forklift.konveyor.io/v1beta1/namespaces/ns/plans/plan"))
+	g.Expect(atomic.LoadInt32(&attempts)).To(gomega.Equal(int32(2)))
+}
+
+func TestCache(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&core.ConfigMap{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace: "test",
+					Name:      "notifications",
+					Labels:    map[string]string{ConfigLabel: "true"},
+				},
+				Data: map[string]string{
+					TargetsKey: `
+- name: ops
+  url: http://ops.example.com/hook
+  secret: ops
+- name: audit
+  url: http://audit.example.com/hook
+  secret: missing
+`,
+				},
+			},
+			&core.Secret{
+				ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "ops"},
+				Data:       map[string][]byte{SecretKey: []byte("key")},
+			}).
+		Build()
+	cache := &Cache{TTL: time.Hour}
+	resolved, loaded, err := cache.Targets(cl, "test")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(loaded).To(gomega.BeTrue())
+	g.Expect(resolved.Targets).To(gomega.HaveLen(1))
+	g.Expect(resolved.Targets[0].key).To(gomega.Equal([]byte("key")))
+	g.Expect(resolved.NotFound).To(gomega.Equal([]string{"audit"}))
+
+	// Cached.
+	resolved, loaded, err = cache.Targets(cl, "test")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(loaded).To(gomega.BeFalse())
+	g.Expect(resolved.Targets).To(gomega.HaveLen(1))
+
+	// Expired.
+	cache.TTL = 0
+	_, loaded, err = cache.Targets(cl, "test")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(loaded).To(gomega.BeTrue())
+}
@@ -0,0 +1,141 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/notifier"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	core "k8s.io/api/core/v1"
+)
+
+// The effective precopy policy for the VM.
+// Settings are overridden by the plan policy which is
+// overridden by the VM policy. Intervals and thresholds
+// not set (zero) are inherited.
+func (r *Migration) precopyPolicy(vm *plan.VMStatus) (policy plan.PrecopyPolicy) {
+	policy = plan.PrecopyPolicy{
+		Interval:       Settings.PrecopyInterval,
+		MinInterval:    Settings.PrecopyIntervalMin,
+		MaxInterval:    Settings.PrecopyIntervalMax,
+		ReadyThreshold: int64(Settings.PrecopyReadyThreshold),
+	}
+	override := func(p *plan.PrecopyPolicy) {
+		if p == nil {
+			return
+		}
+		policy.Adaptive = p.Adaptive
+		if p.Interval > 0 {
+			policy.Interval = p.Interval
+		}
+		if p.MinInterval > 0 {
+			policy.MinInterval = p.MinInterval
+		}
+		if p.MaxInterval > 0 {
+			policy.MaxInterval = p.MaxInterval
+		}
+		if p.ReadyThreshold > 0 {
+			policy.ReadyThreshold = p.ReadyThreshold
+		}
+	}
+	override(r.Plan.Spec.Precopy)
+	if planned, found := r.Plan.Spec.FindVM(vm.Ref); found {
+		override(planned.Precopy)
+	}
+
+	return
+}
+
+// Measure the data changed since the previous precopy and
+// update the ReadyForCutover condition.
+// Measurement is best-effort; the precopy delta is not
+// set when it cannot be measured.
+func (r *Migration) measurePrecopy(vm *plan.VMStatus) {
+	n := len(vm.Warm.Precopies)
+	if n < 2 || vm.Warm.Precopies[n-1].Delta != nil {
+		return
+	}
+	delta, err := r.provider.PrecopyDelta(vm.Ref, vm.Warm.Precopies)
+	if err != nil {
+		if errors.As(err, &adapter.PrecopyDeltaNotSupportedError{}) {
+			return
+		}
+		r.Log.Info(
+			"Precopy delta not measured.",
+			"vm",
+			vm.String(),
+			"reason",
+			err.Error())
+		return
+	}
+	vm.Warm.Precopies[n-1].Delta = &delta
+	policy := r.precopyPolicy(vm)
+	if delta > policy.ReadyThreshold {
+		vm.DeleteCondition(ReadyForCutover)
+		return
+	}
+	if vm.HasCondition(ReadyForCutover) {
+		return
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     ReadyForCutover,
+			Status:   True,
+			Category: Advisory,
+			Message: fmt.Sprintf(
+				"The precopy delta (%d MB) is within the threshold (%d MB).",
+				delta,
+				policy.ReadyThreshold),
+		})
+	r.record(
+		core.EventTypeNormal,
+		ReadyForCutover,
+		fmt.Sprintf(
+			"VM %s is ready for cutover.",
+			vm.String()))
+	r.notifyVM(notifier.VMReadyForCutover, vm, "The VM is ready for cutover.")
+}
+
+// Compute the next precopy interval (minutes).
+// When adaptive, the interval is the time expected for the
+// source disks to accumulate the ready threshold of changed
+// data at the rate measured by the latest precopy. The interval
+// is never shorter than the latest copy and is bounded by the
+// policy. The fixed interval is used until a rate is measured.
+func nextInterval(policy plan.PrecopyPolicy, precopies []plan.Precopy) (interval int) {
+	interval = policy.Interval
+	n := len(precopies)
+	if !policy.Adaptive || n < 2 {
+		return
+	}
+	latest := precopies[n-1]
+	previous := precopies[n-2]
+	if latest.Delta == nil ||
+		latest.Start == nil ||
+		latest.End == nil ||
+		previous.Start == nil {
+		return
+	}
+	elapsed := latest.Start.Sub(previous.Start.Time).Minutes()
+	if *latest.Delta > 0 && elapsed > 0 {
+		rate := float64(*latest.Delta) / elapsed
+		interval = int(math.Round(float64(policy.ReadyThreshold) / rate))
+	} else {
+		interval = policy.MaxInterval
+	}
+	copied := int(math.Ceil(latest.End.Sub(latest.Start.Time).Minutes()))
+	if interval < copied {
+		interval = copied
+	}
+	if interval < policy.MinInterval {
+		interval = policy.MinInterval
+	}
+	if policy.MaxInterval > 0 && interval > policy.MaxInterval {
+		interval = policy.MaxInterval
+	}
+
+	return
+}
@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextInterval(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	at := func(minutes int) *meta.Time {
+		t := meta.NewTime(time.Date(2023, 1, 1, 0, minutes, 0, 0, time.UTC))
+		return &t
+	}
+	delta := func(mb int64) *int64 {
+		return &mb
+	}
+	policy := plan.PrecopyPolicy{
+		Interval:       60,
+		MinInterval:    5,
+		MaxInterval:    240,
+		ReadyThreshold: 1000,
+	}
+	precopies := []plan.Precopy{
+		{Start: at(0), End: at(30)},
+		{Start: at(60), End: at(70), Delta: delta(2000)},
+	}
+
+	// Fixed.
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(60))
+
+	// Heavy churn: 2000 MB in 60 minutes.
+	policy.Adaptive = true
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(30))
+
+	// Not shorter than the copy.
+	precopies[1].End = at(100)
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(40))
+
+	// Bounded.
+	precopies[1].Delta = delta(10)
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(240))
+	precopies[1].Delta = delta(100000)
+	precopies[1].End = at(61)
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(5))
+
+	// Not measured.
+	precopies[1].Delta = nil
+	g.Expect(nextInterval(policy, precopies)).To(gomega.Equal(60))
+}
+
+func TestValidatePrecopy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	reconciler := &Reconciler{}
+	newPlan := func(policy, vmPolicy *plan.PrecopyPolicy) *api.Plan {
+		p := &api.Plan{}
+		p.Spec.Precopy = policy
+		p.Spec.VMs = []plan.VM{{Precopy: vmPolicy}}
+		p.Spec.VMs[0].ID = "vm-1"
+		return p
+	}
+
+	// Valid.
+	p := newPlan(&plan.PrecopyPolicy{MinInterval: 5, MaxInterval: 60}, &plan.PrecopyPolicy{MaxInterval: 30})
+	reconciler.validatePrecopy(p)
+	g.Expect(p.Status.HasCondition(PrecopyNotValid)).To(gomega.BeFalse())
+
+	// Negative.
+	p = newPlan(&plan.PrecopyPolicy{Interval: -1}, nil)
+	reconciler.validatePrecopy(p)
+	g.Expect(p.Status.HasCondition(PrecopyNotValid)).To(gomega.BeTrue())
+
+	// Minimum exceeds the maximum.
+	p = newPlan(&plan.PrecopyPolicy{MinInterval: 90, MaxInterval: 60}, nil)
+	reconciler.validatePrecopy(p)
+	g.Expect(p.Status.HasCondition(PrecopyNotValid)).To(gomega.BeTrue())
+
+	// Minimum exceeds the maximum inherited from the plan.
+	p = newPlan(&plan.PrecopyPolicy{MaxInterval: 60}, &plan.PrecopyPolicy{MinInterval: 90})
+	reconciler.validatePrecopy(p)
+	g.Expect(p.Status.HasCondition(PrecopyNotValid)).To(gomega.BeTrue())
+
+	// Adaptive.
+	source := func(p *api.Plan, providerType api.ProviderType) {
+		p.Referenced.Provider.Source = &api.Provider{}
+		p.Referenced.Provider.Source.Spec.Type = &providerType
+	}
+	p = newPlan(nil, &plan.PrecopyPolicy{Adaptive: true})
+	source(p, api.VSphere)
+	reconciler.validatePrecopy(p)
+	g.Expect(p.Status.HasCondition(PrecopyNotAdaptive)).To(gomega.BeFalse())
+	p = newPlan(nil, &plan.PrecopyPolicy{Adaptive: true})
+	source(p, api.OVirt)
+	reconciler.validatePrecopy(p)
+	cnd := p.Status.FindCondition(PrecopyNotAdaptive)
+	g.Expect(cnd).ToNot(gomega.BeNil())
+	g.Expect(cnd.Category).To(gomega.Equal(Warn))
+	g.Expect(cnd.Items).To(gomega.HaveLen(1))
+	g.Expect(p.Status.HasCondition(PrecopyNotValid)).To(gomega.BeFalse())
+}
@@ -0,0 +1,61 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/report"
+	core "k8s.io/api/core/v1"
+)
+
+// Event reasons.
+const (
+	ReportGenerated = "ReportGenerated"
+)
+
+// Capture the disks to be transferred for the report.
+// Captured when the migration starts since the source VM
+// may be decommissioned before the report is generated.
+func (r *Migration) captureDisks(vm *plan.VMStatus) {
+	vm.Disks = nil
+	requests, err := r.validator.Requests(vm.Ref)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Disks not captured.",
+			"vm",
+			vm.String())
+		return
+	}
+	for _, disk := range requests.Disks {
+		vm.Disks = append(
+			vm.Disks,
+			plan.Disk{
+				Storage:      disk.Storage,
+				StorageClass: disk.StorageClass,
+				Size:         disk.Size,
+			})
+	}
+}
+
+// Generate the report of the completed plan execution.
+// The report is stored in a ConfigMap owned by the plan.
+func (r *Migration) report() {
+	rpt := report.New(r.Plan, r.Migration)
+	err := report.Save(r.Client, r.Plan, r.Migration, rpt)
+	if err != nil {
+		r.Log.Error(err, "Report not generated.")
+		return
+	}
+	name := report.Name(r.Plan, r.Migration)
+	r.Log.Info(
+		"Report generated.",
+		"configMap",
+		name)
+	r.record(
+		core.EventTypeNormal,
+		ReportGenerated,
+		fmt.Sprintf(
+			"The migration report has been generated in ConfigMap %s.",
+			name))
+}
@@ -0,0 +1,515 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Labels.
+const (
+	// Plan UID.
+	PlanLabel = "plan"
+	// Migration UID.
+	MigrationLabel = "migration"
+	// Report ConfigMap.
+	ReportLabel = "forklift.konveyor.io/report"
+)
+
+// ConfigMap keys.
+const (
+	JSONKey = "report.json"
+	CSVKey  = "report.csv"
+	HTMLKey = "report.html"
+	// Suffix of (binary) keys containing gzip
+	// compressed renderings.
+	GzipSuffix = ".gz"
+)
+
+// Annotations.
+const (
+	// Renderings omitted (comma separated keys) because
+	// the ConfigMap size limit would be exceeded.
+	AnnOmitted = "forklift.konveyor.io/omitted"
+)
+
+// Maximum size of the ConfigMap content (bytes).
+// The API server rejects ConfigMaps larger than 1MiB so
+// room is left for the metadata.
+const MaxSize = 1024*1024 - 64*1024
+
+// Results.
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+	Canceled  = "Canceled"
+	Unknown   = "Unknown"
+)
+
+// Referenced resource.
+type Resource struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// Build the resource from the object.
+func (r *Resource) With(m meta.Object) {
+	r.Namespace = m.GetNamespace()
+	r.Name = m.GetName()
+	r.UID = string(m.GetUID())
+}
+
+// Migration.
+type Migration struct {
+	Resource `json:",inline"`
+	// User that created the migration.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// Created timestamp.
+	Created meta.Time `json:"created"`
+}
+
+// Pipeline step.
+type Phase struct {
+	plan.Timed `json:",inline"`
+	// Name.
+	Name string `json:"name"`
+	// Description.
+	Description string `json:"description,omitempty"`
+	// Phase.
+	Phase string `json:"phase,omitempty"`
+	// Progress.
+	Progress libitr.Progress `json:"progress"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Migrated VM.
+type VM struct {
+	plan.Timed `json:",inline"`
+	// Source VM.
+	Source ref.Ref `json:"source"`
+	// Destination VM.
+	Target Resource `json:"target"`
+	// Result.
+	Result string `json:"result"`
+	// Disks.
+	Disks []plan.Disk `json:"disks,omitempty"`
+	// Pipeline steps.
+	Phases []Phase `json:"phases"`
+	// Warm precopies.
+	Precopies []plan.Precopy `json:"precopies,omitempty"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+	// Concerns reported when the migration started.
+	Concerns []plan.Concern `json:"concerns,omitempty"`
+}
+
+// Total disk size (bytes).
+func (r *VM) DiskSize() (size int64) {
+	for _, disk := range r.Disks {
+		size += disk.Size
+	}
+	return
+}
+
+// Plan execution report.
+type Report struct {
+	plan.Timed `json:",inline"`
+	// Plan.
+	Plan Resource `json:"plan"`
+	// Migration.
+	Migration Migration `json:"migration"`
+	// Source provider.
+	Source Resource `json:"source"`
+	// Destination provider.
+	Destination Resource `json:"destination"`
+	// Target namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Warm migration.
+	Warm bool `json:"warm"`
+	// Result.
+	Result string `json:"result"`
+	// Generated timestamp.
+	Generated meta.Time `json:"generated"`
+	// VMs.
+	VMs []VM `json:"vms"`
+}
+
+// Build the report for the plan execution.
+func New(p *api.Plan, migration *api.Migration) (report *Report) {
+	report = &Report{
+		Timed: plan.Timed{
+			Started:   p.Status.Migration.Started,
+			Completed: p.Status.Migration.Completed,
+		},
+		Migration: Migration{
+			CreatedBy: migration.Annotations[api.AnnCreatedBy],
+			Created:   migration.CreationTimestamp,
+		},
+		TargetNamespace: p.Spec.TargetNamespace,
+		Warm:            p.Spec.Warm,
+		Result:          Unknown,
+		Generated:       meta.Now(),
+	}
+	report.Plan.With(p)
+	report.Migration.With(migration)
+	if provider := p.Referenced.Provider.Source; provider != nil {
+		report.Source.With(provider)
+	}
+	if provider := p.Referenced.Provider.Destination; provider != nil {
+		report.Destination.With(provider)
+	}
+	if snapshot := p.Status.Migration.ActiveSnapshot(); snapshot != nil {
+		report.Result = result(&snapshot.Conditions)
+	}
+	for _, vm := range p.Status.Migration.VMs {
+		report.VMs = append(report.VMs, newVM(p, vm))
+	}
+
+	return
+}
+
+// Build the VM report.
+func newVM(p *api.Plan, vm *plan.VMStatus) (report VM) {
+	report = VM{
+		Timed:    vm.Timed,
+		Source:   vm.Ref,
+		Result:   result(&vm.Conditions),
+		Concerns: vm.Concerns,
+		Disks:    vm.Disks,
+		Target: Resource{
+			Namespace: p.Spec.TargetNamespace,
+			Name:      vm.Name,
+		},
+	}
+	if vm.Error != nil {
+		report.Errors = append(report.Errors, vm.Error.Reasons...)
+	}
+	if vm.Warm != nil {
+		report.Precopies = vm.Warm.Precopies
+	}
+	for _, step := range vm.Pipeline {
+		phase := Phase{
+			Timed:       step.Timed,
+			Name:        step.Name,
+			Description: step.Description,
+			Phase:       step.Phase,
+			Progress:    step.Progress,
+		}
+		if step.Error != nil {
+			phase.Errors = append(phase.Errors, step.Error.Reasons...)
+		}
+		for _, task := range step.Tasks {
+			if task.Error != nil {
+				phase.Errors = append(phase.Errors, task.Error.Reasons...)
+			}
+		}
+		report.Phases = append(report.Phases, phase)
+	}
+
+	return
+}
+
+// Determine the result reflected by the conditions.
+func result(conditions *libcnd.Conditions) string {
+	switch {
+	case conditions.HasCondition(Canceled):
+		return Canceled
+	case conditions.HasCondition(Failed):
+		return Failed
+	case conditions.HasCondition(Succeeded):
+		return Succeeded
+	default:
+		return Unknown
+	}
+}
+
+// Render the report as JSON.
+func (r *Report) JSON() (content []byte, err error) {
+	content, err = json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Render the report as CSV.
+// One row for each VM pipeline step.
+func (r *Report) CSV() (content []byte, err error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	rows := [][]string{
+		{
+			"plan",
+			"migration",
+			"createdBy",
+			"vmId",
+			"vmName",
+			"targetNamespace",
+			"targetName",
+			"result",
+			"vmStarted",
+			"vmCompleted",
+			"diskCount",
+			"diskSize",
+			"precopies",
+			"concerns",
+			"errors",
+			"step",
+			"stepPhase",
+			"stepStarted",
+			"stepCompleted",
+			"stepErrors",
+		},
+	}
+	for _, vm := range r.VMs {
+		concerns := []string{}
+		for _, concern := range vm.Concerns {
+			concerns = append(concerns, concern.Category+": "+concern.Label)
+		}
+		row := []string{
+			r.Plan.Namespace + "/" + r.Plan.Name,
+			r.Migration.Name,
+			r.Migration.CreatedBy,
+			vm.Source.ID,
+			vm.Source.Name,
+			vm.Target.Namespace,
+			vm.Target.Name,
+			vm.Result,
+			timestamp(vm.Started),
+			timestamp(vm.Completed),
+			strconv.Itoa(len(vm.Disks)),
+			strconv.FormatInt(vm.DiskSize(), 10),
+			strconv.Itoa(len(vm.Precopies)),
+			strings.Join(concerns, "; "),
+			strings.Join(vm.Errors, "; "),
+		}
+		if len(vm.Phases) == 0 {
+			rows = append(rows, append(row, "", "", "", "", ""))
+			continue
+		}
+		for _, phase := range vm.Phases {
+			rows = append(
+				rows,
+				append(
+					append([]string{}, row...),
+					phase.Name,
+					phase.Phase,
+					timestamp(phase.Started),
+					timestamp(phase.Completed),
+					strings.Join(phase.Errors, "; ")))
+		}
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content = buffer.Bytes()
+	return
+}
+
+// Render the report as HTML.
+func (r *Report) HTML() (content []byte, err error) {
+	buffer := &bytes.Buffer{}
+	err = page.Execute(buffer, r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content = buffer.Bytes()
+	return
+}
+
+// Format a timestamp.
+func timestamp(t *meta.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// HTML page template.
+var page = template.Must(template.New("report").Funcs(template.FuncMap{"timestamp": timestamp}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Migration report: {{.Plan.Namespace}}/{{.Plan.Name}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>Migration report: {{.Plan.Namespace}}/{{.Plan.Name}}</h1>
+<table>
+<tr><th>Migration</th><td>{{.Migration.Name}}</td></tr>
+<tr><th>Created by</th><td>{{.Migration.CreatedBy}}</td></tr>
+<tr><th>Source provider</th><td>{{.Source.Namespace}}/{{.Source.Name}}</td></tr>
+<tr><th>Destination provider</th><td>{{.Destination.Namespace}}/{{.Destination.Name}}</td></tr>
+<tr><th>Target namespace</th><td>{{.TargetNamespace}}</td></tr>
+<tr><th>Warm</th><td>{{.Warm}}</td></tr>
+<tr><th>Started</th><td>{{timestamp .Started}}</td></tr>
+<tr><th>Completed</th><td>{{timestamp .Completed}}</td></tr>
+<tr><th>Result</th><td>{{.Result}}</td></tr>
+</table>
+{{range .VMs}}
+<h2>{{.Source.Name}} ({{.Source.ID}})</h2>
+<table>
+<tr><th>Target</th><td>{{.Target.Namespace}}/{{.Target.Name}}</td></tr>
+<tr><th>Result</th><td>{{.Result}}</td></tr>
+<tr><th>Started</th><td>{{timestamp .Started}}</td></tr>
+<tr><th>Completed</th><td>{{timestamp .Completed}}</td></tr>
+<tr><th>Disk size (bytes)</th><td>{{.DiskSize}}</td></tr>
+{{range .Errors}}<tr><th>Error</th><td>{{.}}</td></tr>
+{{end}}{{range .Concerns}}<tr><th>Concern</th><td>{{.Category}}: {{.Label}}</td></tr>
+{{end}}</table>
+<table>
+<tr><th>Step</th><th>Phase</th><th>Started</th><th>Completed</th><th>Errors</th></tr>
+{{range .Phases}}<tr><td>{{.Name}}</td><td>{{.Phase}}</td><td>{{timestamp .Started}}</td><td>{{timestamp .Completed}}</td><td>{{range .Errors}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>
+{{if .Precopies}}<table>
+<tr><th>Precopy</th><th>Start</th><th>End</th><th>Snapshot</th></tr>
+{{range $i, $p := .Precopies}}<tr><td>{{$i}}</td><td>{{timestamp $p.Start}}</td><td>{{timestamp $p.End}}</td><td>{{$p.Snapshot}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// Name of the ConfigMap containing the report.
+func Name(p *api.Plan, migration *api.Migration) string {
+	return strings.Join([]string{p.Name, migration.Name, "report"}, "-")
+}
+
+// Save the report in a ConfigMap owned by the plan.
+// Renderings exceeding the ConfigMap size limit are compressed
+// or omitted and listed in the `omitted` annotation.
+func Save(cl client.Client, p *api.Plan, migration *api.Migration, report *Report) (err error) {
+	data, binary, omitted, err := report.pack(MaxSize)
+	if err != nil {
+		return
+	}
+	annotations := map[string]string{}
+	if len(omitted) > 0 {
+		annotations[AnnOmitted] = strings.Join(omitted, ",")
+	}
+	cm := &core.ConfigMap{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: p.Namespace,
+			Name:      Name(p, migration),
+		},
+		cm)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		cm = &core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: p.Namespace,
+				Name:      Name(p, migration),
+				Labels: map[string]string{
+					ReportLabel:    "true",
+					PlanLabel:      string(p.UID),
+					MigrationLabel: string(migration.UID),
+				},
+				OwnerReferences: []meta.OwnerReference{
+					{
+						APIVersion: api.SchemeGroupVersion.String(),
+						Kind:       "Plan",
+						Name:       p.Name,
+						UID:        p.UID,
+					},
+				},
+			},
+			Data:       data,
+			BinaryData: binary,
+		}
+		cm.Annotations = annotations
+		err = cl.Create(context.TODO(), cm)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	cm.Data = data
+	cm.BinaryData = binary
+	cm.Annotations = annotations
+	err = cl.Update(context.TODO(), cm)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Pack the renderings within the size limit.
+// Renderings are added in order of precedence (JSON, CSV, HTML).
+// A rendering that does not fit is gzip compressed and stored
+// as binary data, or omitted when it still does not fit.
+func (r *Report) pack(limit int) (data map[string]string, binary map[string][]byte, omitted []string, err error) {
+	data = map[string]string{}
+	size := 0
+	for _, rendering := range []struct {
+		key    string
+		render func() ([]byte, error)
+	}{
+		{key: JSONKey, render: r.JSON},
+		{key: CSVKey, render: r.CSV},
+		{key: HTMLKey, render: r.HTML},
+	} {
+		content, rErr := rendering.render()
+		if rErr != nil {
+			err = rErr
+			return
+		}
+		if size+len(content) <= limit {
+			data[rendering.key] = string(content)
+			size += len(content)
+			continue
+		}
+		compressed := &bytes.Buffer{}
+		writer := gzip.NewWriter(compressed)
+		_, err = writer.Write(content)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if size+compressed.Len() <= limit {
+			if binary == nil {
+				binary = map[string][]byte{}
+			}
+			binary[rendering.key+GzipSuffix] = compressed.Bytes()
+			size += compressed.Len()
+			continue
+		}
+		omitted = append(omitted, rendering.key)
+	}
+
+	return
+}
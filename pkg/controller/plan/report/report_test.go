@@ -0,0 +1,103 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan"},
+	}
+	p.Spec.TargetNamespace = "target"
+	vm := &plan.VMStatus{
+		VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "db"}},
+		Pipeline: []*plan.Step{
+			{Task: plan.Task{Name: "Initialize"}},
+			{Task: plan.Task{Name: "DiskTransfer", Error: &plan.Error{Reasons: []string{"failed"}}}},
+		},
+		Concerns: []plan.Concern{{Label: "Shared disk", Category: "Warning"}},
+		Disks:    []plan.Disk{{Size: 10}, {Size: 20}},
+	}
+	vm.SetCondition(libcnd.Condition{Type: Failed, Status: libcnd.True})
+	p.Status.Migration.VMs = []*plan.VMStatus{vm}
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        "migration",
+			Annotations: map[string]string{api.AnnCreatedBy: "admin"},
+		},
+	}
+	report := New(p, migration)
+	g.Expect(report.VMs[0].Result).To(gomega.Equal(Failed))
+	g.Expect(report.VMs[0].Target.Name).To(gomega.Equal("db"))
+	g.Expect(report.VMs[0].Phases[1].Errors).To(gomega.Equal([]string{"failed"}))
+
+	content, err := report.JSON()
+	g.Expect(err).To(gomega.BeNil())
+	decoded := Report{}
+	g.Expect(json.Unmarshal(content, &decoded)).To(gomega.Succeed())
+	g.Expect(decoded.Migration.CreatedBy).To(gomega.Equal("admin"))
+
+	content, err = report.CSV()
+	g.Expect(err).To(gomega.BeNil())
+	rows, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rows).To(gomega.HaveLen(3))
+	g.Expect(rows[2][11]).To(gomega.Equal("30"))
+	g.Expect(rows[2][15]).To(gomega.Equal("DiskTransfer"))
+
+	content, err = report.HTML()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(string(content)).To(gomega.ContainSubstring("Warning: Shared disk"))
+}
+
+func TestReportPack(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan"},
+	}
+	for i := 0; i < 50; i++ {
+		p.Status.Migration.VMs = append(
+			p.Status.Migration.VMs,
+			&plan.VMStatus{
+				VM: plan.VM{Ref: ref.Ref{ID: "vm-" + strconv.Itoa(i), Name: "db"}},
+			})
+	}
+	report := New(p, &api.Migration{})
+	content, err := report.JSON()
+	g.Expect(err).To(gomega.BeNil())
+
+	// All renderings fit.
+	data, binary, omitted, err := report.pack(MaxSize)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(data).To(gomega.HaveLen(3))
+	g.Expect(binary).To(gomega.BeEmpty())
+	g.Expect(omitted).To(gomega.BeEmpty())
+
+	// Only the JSON fits, the others are compressed or omitted.
+	data, binary, omitted, err = report.pack(len(content) + 100)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(data).To(gomega.HaveKey(JSONKey))
+	g.Expect(data).To(gomega.HaveLen(1))
+	g.Expect(len(binary) + len(omitted)).To(gomega.Equal(2))
+
+	// Nothing fits.
+	data, binary, omitted, err = report.pack(10)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(data).To(gomega.BeEmpty())
+	g.Expect(binary).To(gomega.BeEmpty())
+	g.Expect(omitted).To(gomega.Equal([]string{JSONKey, CSVKey, HTMLKey}))
+}
@@ -3,6 +3,7 @@ package scheduler
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/ovirt"
@@ -19,16 +20,18 @@ type Scheduler interface {
 }
 
 // Scheduler factory.
-func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
+func New(ctx *plancontext.Context, builder planbase.Builder) (scheduler Scheduler, err error) {
 	switch ctx.Source.Provider.Type() {
 	case api.VSphere:
 		scheduler = &vsphere.Scheduler{
 			Context:     ctx,
+			Builder:     builder,
 			MaxInFlight: settings.Settings.MaxInFlight,
 		}
 	case api.OVirt:
 		scheduler = &ovirt.Scheduler{
 			Context:     ctx,
+			Builder:     builder,
 			MaxInFlight: settings.Settings.MaxInFlight,
 		}
 	case api.OpenStack:
@@ -4,6 +4,7 @@ import (
 	"context"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	"sync"
@@ -18,6 +19,8 @@ var mutex sync.Mutex
 // Scheduler for migrations from oVirt.
 type Scheduler struct {
 	*plancontext.Context
+	// Builder.
+	Builder planbase.Builder
 	// Maximum number of VMs that can be
 	// migrated at once per provider.
 	MaxInFlight int
@@ -27,6 +30,11 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	mutex.Lock()
 	defer mutex.Unlock()
 
+	shared, err := r.Builder.SharedDisks()
+	if err != nil {
+		return
+	}
+
 	planList := &api.PlanList{}
 	err = r.List(context.TODO(), planList)
 	if err != nil {
@@ -58,7 +66,24 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+	vm, hasNext = r.next(shared)
+
+	return
+}
+
+// Select the next VM.
+// VMs sharing disks with running VMs are preferred
+// and VMs attached to shared disks wait for the owners.
+func (r *Scheduler) next(shared planbase.SharedDisks) (vm *plan.VMStatus, hasNext bool) {
+	vms := r.Plan.Status.Migration.VMs
+	vm, hasNext = shared.NextPeer(vms, vms)
+	if hasNext {
+		return
+	}
+	for _, vmStatus := range vms {
+		if shared.Waiting(vmStatus.ID, vms) {
+			continue
+		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
 			vm = vmStatus
 			hasNext = true
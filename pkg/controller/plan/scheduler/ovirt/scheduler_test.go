@@ -0,0 +1,101 @@
+package ovirt
+
+import (
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Builder reporting the shared disks.
+type builder struct {
+	planbase.Builder
+	shared planbase.SharedDisks
+}
+
+func (r *builder) SharedDisks() (planbase.SharedDisks, error) {
+	return r.shared, nil
+}
+
+func TestScheduler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vmStatus := func(id string) *plan.VMStatus {
+		vm := &plan.VMStatus{}
+		vm.ID = id
+		vm.Pipeline = []*plan.Step{
+			{Task: plan.Task{Name: planbase.DiskTransfer}},
+		}
+		return vm
+	}
+	owner := vmStatus("vm1")
+	peer := vmStatus("vm2")
+	other := vmStatus("vm3")
+	p := &api.Plan{}
+	p.Namespace = "ns"
+	p.Name = "plan"
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "ns", Name: "ovirt"}
+	p.Status.Migration.VMs = []*plan.VMStatus{owner, peer, other}
+	p.Status.Migration.NewSnapshot(plan.Snapshot{})
+	p.Status.Migration.ActiveSnapshot().SetCondition(
+		libcnd.Condition{
+			Type:   "Executing",
+			Status: libcnd.True,
+		})
+	s := runtime.NewScheme()
+	g.Expect(apis.AddToScheme(s)).To(gomega.Succeed())
+	scheduler := func(maxInFlight int) *Scheduler {
+		running := p.DeepCopy()
+		return &Scheduler{
+			Context: &plancontext.Context{
+				Client: fake.NewClientBuilder().
+					WithScheme(s).
+					WithObjects(running).
+					Build(),
+				Plan: p,
+			},
+			Builder: &builder{
+				shared: planbase.SharedDisks{"d1": {"vm1", "vm2"}},
+			},
+			MaxInFlight: maxInFlight,
+		}
+	}
+
+	// The owner is started before the peer.
+	vm, hasNext, err := scheduler(1).Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(vm).To(gomega.BeIdenticalTo(owner))
+	owner.MarkStarted()
+	owner.Pipeline[0].MarkStarted()
+
+	// No capacity.
+	_, hasNext, err = scheduler(1).Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeFalse())
+
+	// The peer waits for the owner to transfer the disks.
+	vm, hasNext, err = scheduler(2).Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(vm).To(gomega.BeIdenticalTo(other))
+
+	// The peer is preferred once the disks are transferred.
+	owner.Pipeline[0].MarkCompleted()
+	vm, hasNext, err = scheduler(2).Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeTrue())
+	g.Expect(vm).To(gomega.BeIdenticalTo(peer))
+
+	// The peer respects the capacity.
+	_, hasNext, err = scheduler(1).Next()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(hasNext).To(gomega.BeFalse())
+}
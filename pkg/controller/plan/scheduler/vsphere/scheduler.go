@@ -8,6 +8,7 @@ import (
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -22,6 +23,8 @@ var mutex sync.Mutex
 // Scheduler for migrations from ESX hosts.
 type Scheduler struct {
 	*plancontext.Context
+	// Builder.
+	Builder planbase.Builder
 	// Maximum number of disks per host that can be
 	// migrated at once.
 	MaxInFlight int
@@ -31,6 +34,8 @@ type Scheduler struct {
 	// Mapping of hosts by ID to lists of VMs
 	// that are waiting to be migrated.
 	pending map[string][]*pendingVM
+	// Disks shared between VMs in the plan.
+	shared planbase.SharedDisks
 }
 
 // Convenience struct to package a
@@ -49,10 +54,22 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	if err != nil {
 		return
 	}
-	for _, vms := range r.schedulable() {
-		if len(vms) > 0 {
-			vm = vms[0].status
-			hasNext = true
+	// VMs sharing disks with running VMs are
+	// preferred within the host capacity.
+	schedulable := r.schedulable()
+	candidates := []*plan.VMStatus{}
+	for _, vms := range schedulable {
+		for _, pending := range vms {
+			candidates = append(candidates, pending.status)
+		}
+	}
+	vm, hasNext = r.shared.NextPeer(r.Plan.Status.Migration.VMs, candidates)
+	if !hasNext {
+		for _, vms := range schedulable {
+			if len(vms) > 0 {
+				vm = vms[0].status
+				hasNext = true
+			}
 		}
 	}
 
@@ -71,6 +88,11 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 // the same provider, and determine which
 // VMs are still waiting to be started.
 func (r *Scheduler) buildSchedule() (err error) {
+	r.shared, err = r.Builder.SharedDisks()
+	if err != nil {
+		return
+	}
+
 	err = r.buildInFlight()
 	if err != nil {
 		return
@@ -165,6 +187,10 @@ func (r *Scheduler) buildPending() (err error) {
 			return
 		}
 
+		// VMs attached to shared disks wait for the owners.
+		if r.shared.Waiting(vmStatus.ID, r.Plan.Status.Migration.VMs) {
+			continue
+		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
 			pending := &pendingVM{
 				status: vmStatus,
@@ -0,0 +1,82 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	"github.com/onsi/gomega"
+	auth "k8s.io/api/authorization/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client answering subject access reviews.
+type reviewer struct {
+	client.Client
+	// Reviews allowed.
+	allowed bool
+}
+
+func (r *reviewer) Create(ctx context.Context, object client.Object, options ...client.CreateOption) error {
+	if review, cast := object.(*auth.SubjectAccessReview); cast {
+		review.Status.Allowed = r.allowed
+		return nil
+	}
+	return r.Client.Create(ctx, object, options...)
+}
+
+func TestInventoryScope(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cl := &reviewer{Client: fakeClient()}
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: cl,
+		},
+	}
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "konveyor-forklift", Name: "vcenter"},
+	}
+	plan := &api.Plan{}
+	plan.Referenced.Provider.Source = provider
+	// Not recorded.
+	scope, err := r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).To(gomega.BeNil())
+	// No scopes defined.
+	plan.Annotations = map[string]string{
+		api.AnnRequester: `{"username":"alice","groups":["dev"]}`,
+	}
+	scope, err = r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).To(gomega.BeNil())
+	// Resolved against the current provider scopes.
+	provider.Spec.Scopes = []api.ProviderScope{
+		{Groups: []string{"dev"}, Folders: []string{"/dc/vm/dev"}},
+		{Users: []string{"bob"}, Clusters: []string{"prod"}},
+	}
+	scope, err = r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).ToNot(gomega.BeNil())
+	g.Expect(scope.Folders).To(gomega.Equal([]string{"/dc/vm/dev"}))
+	g.Expect(scope.Clusters).To(gomega.BeEmpty())
+	// Permitted to update the provider.
+	cl.allowed = true
+	scope, err = r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).To(gomega.BeNil())
+	cl.allowed = false
+	// Not matched.
+	provider.Spec.Scopes[0].Groups = []string{"ops"}
+	scope, err = r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).ToNot(gomega.BeNil())
+	g.Expect(scope.Folders).To(gomega.BeEmpty())
+	// Malformed.
+	plan.Annotations[api.AnnRequester] = "{"
+	scope, err = r.inventoryScope(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(scope).ToNot(gomega.BeNil())
+	g.Expect(scope.Folders).To(gomega.BeEmpty())
+}
@@ -0,0 +1,92 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	"github.com/onsi/gomega"
+)
+
+func TestPlanVMs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	plan := &api.Plan{}
+	plan.Spec.VMs = []planapi.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{Name: "db"}},
+	}
+	plan.Status.SelectedVMs = []ref.Ref{
+		{ID: "vm-1", Name: "web"},
+		{ID: "vm-2", Name: "db"},
+		{ID: "vm-3", Name: "app"},
+	}
+	// Listed first, selected when not listed.
+	g.Expect(plan.VMs()).To(gomega.Equal([]planapi.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{Name: "db"}},
+		{Ref: ref.Ref{ID: "vm-3", Name: "app"}},
+	}))
+	// Persisted spec not changed.
+	g.Expect(plan.Spec.VMs).To(gomega.HaveLen(2))
+}
+
+func TestSelectorFreeze(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	reconciler := &Reconciler{}
+	newPlan := func() *api.Plan {
+		plan := &api.Plan{}
+		plan.Spec.Selector = &planapi.VMSelector{
+			Name:   "(",
+			Freeze: true,
+		}
+		plan.Status.SelectedVMs = []ref.Ref{{ID: "vm-1", Name: "web"}}
+		plan.Status.Migration.NewSnapshot(planapi.Snapshot{
+			Migration: planapi.SnapshotRef{UID: "1"},
+		})
+		return plan
+	}
+
+	// Frozen while the migration is active: the selection
+	// is not resolved and is merged into the VMs.
+	plan := newPlan()
+	err := reconciler.validateSelector(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(plan.Status.HasCondition(SelectorNotValid)).To(gomega.BeFalse())
+	g.Expect(plan.Status.SelectedVMs).To(gomega.HaveLen(1))
+	g.Expect(plan.Spec.VMs).To(gomega.Equal([]planapi.VM{
+		{Ref: ref.Ref{ID: "vm-1", Name: "web"}},
+	}))
+
+	// Not frozen once the migration has completed.
+	plan = newPlan()
+	plan.Status.Migration.ActiveSnapshot().SetCondition(libcnd.Condition{
+		Type:   Succeeded,
+		Status: True,
+	})
+	err = reconciler.validateSelector(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(plan.Status.HasCondition(SelectorNotValid)).To(gomega.BeTrue())
+
+	// Not frozen without a migration.
+	plan = newPlan()
+	plan.Status.Migration.History = nil
+	err = reconciler.validateSelector(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(plan.Status.HasCondition(SelectorNotValid)).To(gomega.BeTrue())
+
+	// Not frozen unless requested.
+	plan = newPlan()
+	plan.Spec.Selector.Freeze = false
+	err = reconciler.validateSelector(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(plan.Status.HasCondition(SelectorNotValid)).To(gomega.BeTrue())
+
+	// No selector.
+	plan = newPlan()
+	plan.Spec.Selector = nil
+	err = reconciler.validateSelector(plan)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(plan.Status.SelectedVMs).To(gomega.BeNil())
+}
@@ -0,0 +1,218 @@
+package throughput
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Configuration.
+const (
+	// Label on the ConfigMap containing the history.
+	HistoryLabel = "forklift.konveyor.io/throughput"
+	// ConfigMap key containing the (json) history.
+	HistoryKey = "history"
+	// Weight of the latest sample in the moving average.
+	Weight = 0.3
+	// Megabyte.
+	MB = 1024 * 1024
+)
+
+// Build the key for a source host.
+func HostKey(id string) string {
+	return "host/" + id
+}
+
+// Build the key for a source storage (datastore, storage domain).
+func StorageKey(id string) string {
+	return "storage/" + id
+}
+
+// Build the key for a destination storage class.
+func StorageClassKey(name string) string {
+	return "storageClass/" + name
+}
+
+// Disk to be transferred.
+type Disk struct {
+	// Source storage ID.
+	Storage string
+	// Destination storage class.
+	StorageClass string
+	// Size (bytes).
+	Size int64
+}
+
+// Observed throughput.
+type Throughput struct {
+	// Moving average (MB/s).
+	Rate float64 `json:"rate"`
+	// Number of samples.
+	Samples int `json:"samples"`
+	// Last updated.
+	Updated time.Time `json:"updated"`
+}
+
+// Throughput history observed for a source provider
+// keyed by source host, source storage and destination
+// storage class.
+type History struct {
+	Throughput map[string]Throughput `json:"throughput"`
+}
+
+// Record an observed rate (MB/s).
+// The rate is averaged with the previous samples
+// using an exponentially weighted moving average.
+func (r *History) Record(key string, rate float64) {
+	if r.Throughput == nil {
+		r.Throughput = map[string]Throughput{}
+	}
+	t, found := r.Throughput[key]
+	if found && t.Samples > 0 {
+		t.Rate = Weight*rate + (1-Weight)*t.Rate
+	} else {
+		t.Rate = rate
+	}
+	t.Samples++
+	t.Updated = time.Now().UTC()
+	r.Throughput[key] = t
+}
+
+// Find the expected rate (MB/s).
+// The lowest of the rates observed for the keys is the
+// expected bottleneck. Keys without history are ignored.
+func (r *History) Rate(keys ...string) (rate float64, found bool) {
+	rate = math.MaxFloat64
+	for _, key := range keys {
+		t, hasKey := r.Throughput[key]
+		if !hasKey || t.Rate <= 0 {
+			continue
+		}
+		found = true
+		rate = math.Min(rate, t.Rate)
+	}
+	if !found {
+		rate = 0
+	}
+	return
+}
+
+// Estimate the duration of the disk transfer.
+// Not found when the rate of any disk is unknown.
+func (r *History) Duration(host string, disks []Disk) (duration time.Duration, found bool) {
+	seconds := float64(0)
+	for _, disk := range disks {
+		rate, hasRate := r.Rate(
+			HostKey(host),
+			StorageKey(disk.Storage),
+			StorageClassKey(disk.StorageClass))
+		if !hasRate {
+			return
+		}
+		seconds += float64(disk.Size) / MB / rate
+	}
+	duration = time.Duration(seconds) * time.Second
+	found = true
+	return
+}
+
+// Name of the ConfigMap containing the history of the provider.
+func Name(provider *api.Provider) string {
+	return provider.Name + "-throughput"
+}
+
+// Load the history of the provider.
+func Load(cl client.Client, provider *api.Provider) (history *History, err error) {
+	history = &History{}
+	cm := &core.ConfigMap{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: provider.Namespace,
+			Name:      Name(provider),
+		},
+		cm)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if document, found := cm.Data[HistoryKey]; found {
+		err = json.Unmarshal([]byte(document), history)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+// Save the history of the provider.
+// The ConfigMap is owned by the provider.
+func Save(cl client.Client, provider *api.Provider, history *History) (err error) {
+	document, err := json.Marshal(history)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	cm := &core.ConfigMap{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: provider.Namespace,
+			Name:      Name(provider),
+		},
+		cm)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		cm = &core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: provider.Namespace,
+				Name:      Name(provider),
+				Labels: map[string]string{
+					HistoryLabel: "true",
+				},
+				OwnerReferences: []meta.OwnerReference{
+					{
+						APIVersion: api.SchemeGroupVersion.String(),
+						Kind:       "Provider",
+						Name:       provider.Name,
+						UID:        provider.UID,
+					},
+				},
+			},
+			Data: map[string]string{
+				HistoryKey: string(document),
+			},
+		}
+		err = cl.Create(context.TODO(), cm)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[HistoryKey] = string(document)
+	err = cl.Update(context.TODO(), cm)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package throughput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestHistory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	history := History{}
+	history.Record(HostKey("host-1"), 100)
+	history.Record(HostKey("host-1"), 200)
+	g.Expect(history.Throughput[HostKey("host-1")].Rate).To(gomega.BeNumerically("~", 130, 0.001))
+	g.Expect(history.Throughput[HostKey("host-1")].Samples).To(gomega.Equal(2))
+
+	// Lowest known rate.
+	history.Record(StorageClassKey("slow"), 50)
+	rate, found := history.Rate(HostKey("host-1"), StorageClassKey("slow"), StorageKey("unknown"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(rate).To(gomega.Equal(float64(50)))
+	_, found = history.Rate(StorageKey("unknown"))
+	g.Expect(found).To(gomega.BeFalse())
+
+	// Duration.
+	duration, found := history.Duration(
+		"host-1",
+		[]Disk{
+			{StorageClass: "slow", Size: 500 * MB},
+			{StorageClass: "fast", Size: 1300 * MB},
+		})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(duration).To(gomega.Equal(20 * time.Second))
+	_, found = history.Duration("host-2", []Disk{{StorageClass: "other", Size: MB}})
+	g.Expect(found).To(gomega.BeFalse())
+}
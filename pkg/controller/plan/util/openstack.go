@@ -24,3 +24,26 @@ func OpenstackVolumePopulator(image *openstack.Image, sourceUrl *url.URL, transf
 		},
 	}
 }
+
+// Find the storage map entry for a volume by volume type.
+// Volumes not matching an entry use the first entry.
+func OpenstackVolumeStorage(storageMap []api.StoragePair, vm *openstack.Workload, volume *openstack.Volume) (mapped *api.StoragePair) {
+	if len(storageMap) == 0 {
+		return
+	}
+	mapped = &storageMap[0]
+	for _, volumeType := range vm.VolumeTypes {
+		if volumeType.Name != volume.VolumeType {
+			continue
+		}
+		for i := range storageMap {
+			source := storageMap[i].Source
+			if (source.ID != "" && source.ID == volumeType.ID) ||
+				(source.ID == "" && source.Name == volumeType.Name) {
+				mapped = &storageMap[i]
+				return
+			}
+		}
+	}
+	return
+}
@@ -5,16 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"regexp"
+	"strings"
+	"unicode"
 
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	libref "github.com/konveyor/forklift-controller/pkg/lib/ref"
+	auth "k8s.io/api/authorization/v1"
+	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,16 +39,42 @@ const (
 	DsRefNotValid                = "StorageRefNotValid"
 	VMRefNotValid                = "VMRefNotValid"
 	VMNotFound                   = "VMNotFound"
+	VMNotPermitted               = "VMNotPermitted"
 	VMAlreadyExists              = "VMAlreadyExists"
 	VMNetworksNotMapped          = "VMNetworksNotMapped"
 	VMStorageNotMapped           = "VMStorageNotMapped"
+	VMLUNsNotMapped              = "VMLUNsNotMapped"
+	VMDisksEncrypted             = "VMDisksEncrypted"
+	VMCDROMsNotMapped            = "VMCDROMsNotMapped"
 	VMMultiplePodNetworkMappings = "VMMultiplePodNetworkMappings"
+	VMStateNotPersisted          = "VMStateNotPersisted"
+	VMNVRAMNotCopied             = "VMNVRAMNotCopied"
 	HostNotReady                 = "HostNotReady"
 	DuplicateVM                  = "DuplicateVM"
 	NameNotValid                 = "TargetNameNotValid"
 	HookNotValid                 = "HookNotValid"
 	HookNotReady                 = "HookNotReady"
 	HookStepNotValid             = "HookStepNotValid"
+	NetMapProviderNotValid       = "NetworkMapProviderNotValid"
+	DsMapProviderNotValid        = "StorageMapProviderNotValid"
+	VMInActivePlan               = "VMInActivePlan"
+	SelectorNotValid             = "SelectorNotValid"
+	MetadataMappingNotValid      = "MetadataMappingNotValid"
+	ReadyForCutover              = "ReadyForCutover"
+	PrecopyNotValid              = "PrecopyNotValid"
+	PrecopyNotAdaptive           = "PrecopyNotAdaptive"
+	VerificationFailed           = "VerificationFailed"
+	VerificationNotValid         = "VerificationNotValid"
+	DecommissionNotValid         = "DecommissionNotValid"
+	DevicesNotValid              = "DevicesNotValid"
+	LUNsNotValid                 = "LUNsNotValid"
+	LUKSNotValid                 = "LUKSNotValid"
+	CustomizationNotValid        = "CustomizationNotValid"
+	ConversionNotValid           = "ConversionNotValid"
+	QuotaExceeded                = "QuotaExceeded"
+	LimitRangeExceeded           = "LimitRangeExceeded"
+	StorageCapacityExceeded      = "StorageCapacityExceeded"
+	StorageCapacityNotChecked    = "StorageCapacityNotChecked"
 	Executing                    = "Executing"
 	Succeeded                    = "Succeeded"
 	Failed                       = "Failed"
@@ -73,6 +107,8 @@ const (
 	Modified          = "Modified"
 	UserRequested     = "UserRequested"
 	InMaintenanceMode = "InMaintenanceMode"
+	Exceeded          = "Exceeded"
+	NotPermitted      = "NotPermitted"
 )
 
 // Statuses
@@ -84,17 +120,10 @@ const (
 // Validate the plan resource.
 func (r *Reconciler) validate(plan *api.Plan) error {
 	// Provider.
-	pv := validation.ProviderPair{Client: r}
-	conditions, err := pv.Validate(plan.Spec.Provider)
-	if err != nil {
-		return liberr.Wrap(err)
-	}
-	plan.Status.SetCondition(conditions.List...)
-	if plan.Status.HasCondition(validation.SourceProviderNotReady) {
-		return nil
+	ready, err := r.validateProvider(plan)
+	if err != nil || !ready {
+		return err
 	}
-	plan.Referenced.Provider.Source = pv.Referenced.Source
-	plan.Referenced.Provider.Destination = pv.Referenced.Destination
 	//
 	// Target namespace
 	err = r.validateTargetNamespace(plan)
@@ -111,6 +140,16 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	if err != nil {
 		return err
 	}
+	err = r.validateMapProvider(plan)
+	if err != nil {
+		return err
+	}
+	//
+	// VM selector.
+	err = r.validateSelector(plan)
+	if err != nil {
+		return err
+	}
 	//
 	// Warm migration
 	err = r.validateWarmMigration(plan)
@@ -119,16 +158,66 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	}
 	//
 	// VM list.
+	r.validateVMRefs(plan)
 	err = r.validateVM(plan)
 	if err != nil {
 		return err
 	}
 	//
+	// Destination capacity.
+	err = r.validateCapacity(plan)
+	if err != nil {
+		return err
+	}
+	err = r.validateActivePlans(plan)
+	if err != nil {
+		return err
+	}
+	//
 	// Transfer network
 	err = r.validateTransferNetwork(plan)
 	if err != nil {
 		return err
 	}
+	//
+	// Metadata mapping.
+	r.validateMetadataMapping(plan)
+	//
+	// Precopy policy.
+	r.validatePrecopy(plan)
+	//
+	// Verification.
+	r.validateVerification(plan)
+	//
+	// Decommission.
+	r.validateDecommission(plan)
+	//
+	// Devices.
+	err = r.validateDevices(plan)
+	if err != nil {
+		return err
+	}
+	//
+	// LUNs.
+	err = r.validateLUNs(plan)
+	if err != nil {
+		return err
+	}
+	//
+	// LUKS keys.
+	err = r.validateLUKS(plan)
+	if err != nil {
+		return err
+	}
+	//
+	// Guest customization.
+	err = r.validateCustomization(plan)
+	if err != nil {
+		return err
+	}
+	//
+	// Guest conversion.
+	r.validateConversion(plan)
 	// VM Hooks.
 	err = r.validateHooks(plan)
 	if err != nil {
@@ -138,6 +227,95 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	return nil
 }
 
+// Validate the plan using only the cluster API.
+// The provider inventory is not consulted which permits
+// the validation to be performed by the admission webhook.
+func Validate(client client.Client, plan *api.Plan) (err error) {
+	r := Reconciler{
+		Reconciler: base.Reconciler{
+			Client: client,
+			Log:    log,
+		},
+	}
+	ready, err := r.validateProvider(plan)
+	if err != nil || !ready {
+		return
+	}
+	err = r.validateTargetNamespace(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateNetworkMap(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateStorageMap(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateMapProvider(plan)
+	if err != nil {
+		return
+	}
+	r.validateSelectorName(plan)
+	r.validateVMRefs(plan)
+	err = r.validateActivePlans(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateTransferNetwork(plan)
+	if err != nil {
+		return
+	}
+	r.validateMetadataMapping(plan)
+	r.validatePrecopy(plan)
+	r.validateVerification(plan)
+	r.validateDecommission(plan)
+	err = r.validateDevices(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateLUNs(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateLUKS(plan)
+	if err != nil {
+		return
+	}
+	err = r.validateCustomization(plan)
+	if err != nil {
+		return
+	}
+	r.validateConversion(plan)
+	err = r.validateHooks(plan)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Validate the provider pair.
+// Returns false when the source provider is not ready.
+func (r *Reconciler) validateProvider(plan *api.Plan) (ready bool, err error) {
+	pv := validation.ProviderPair{Client: r}
+	conditions, err := pv.Validate(plan.Spec.Provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	plan.Status.SetCondition(conditions.List...)
+	if plan.Status.HasCondition(validation.SourceProviderNotReady) {
+		return
+	}
+	plan.Referenced.Provider.Source = pv.Referenced.Source
+	plan.Referenced.Provider.Destination = pv.Referenced.Destination
+	ready = true
+
+	return
+}
+
 // Validate that warm migration is supported from the source provider.
 func (r *Reconciler) validateWarmMigration(plan *api.Plan) (err error) {
 	if !plan.Spec.Warm {
@@ -275,6 +453,337 @@ func (r *Reconciler) validateStorageMap(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate that the maps reference providers of the
+// same type as the plan.
+func (r *Reconciler) validateMapProvider(plan *api.Plan) (err error) {
+	if mp := plan.Referenced.Map.Network; mp != nil {
+		matched, mErr := r.providerMatched(plan, mp.Spec.Provider)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		if !matched {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     NetMapProviderNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Map.Network providers do not match the plan providers.",
+			})
+		}
+	}
+	if mp := plan.Referenced.Map.Storage; mp != nil {
+		matched, mErr := r.providerMatched(plan, mp.Spec.Provider)
+		if mErr != nil {
+			err = mErr
+			return
+		}
+		if !matched {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     DsMapProviderNotValid,
+				Status:   True,
+				Reason:   NotValid,
+				Category: Critical,
+				Message:  "Map.Storage providers do not match the plan providers.",
+			})
+		}
+	}
+
+	return
+}
+
+// Determine whether the provider pair (of a map) matches
+// the plan providers. Providers referenced by name are
+// matched; otherwise, the provider types must match.
+func (r *Reconciler) providerMatched(plan *api.Plan, pair provider.Pair) (matched bool, err error) {
+	matched = true
+	for _, p := range []struct {
+		ref        core.ObjectReference
+		referenced *api.Provider
+	}{
+		{ref: pair.Source, referenced: plan.Referenced.Provider.Source},
+		{ref: pair.Destination, referenced: plan.Referenced.Provider.Destination},
+	} {
+		if p.referenced == nil {
+			continue
+		}
+		if p.ref.Namespace == p.referenced.Namespace && p.ref.Name == p.referenced.Name {
+			continue
+		}
+		other := &api.Provider{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: p.ref.Namespace,
+				Name:      p.ref.Name,
+			},
+			other)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				matched = false
+				return
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		if other.Type() != p.referenced.Type() {
+			matched = false
+			return
+		}
+	}
+
+	return
+}
+
+// Validate that the listed VMs are referenced by either `ID` or `Name`.
+func (r *Reconciler) validateVMRefs(plan *api.Plan) {
+	for i := range plan.Spec.VMs {
+		ref := &plan.Spec.VMs[i].Ref
+		if ref.NotSet() {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     VMRefNotValid,
+				Status:   True,
+				Reason:   NotSet,
+				Category: Critical,
+				Message:  "Either `ID` or `Name` required.",
+			})
+			return
+		}
+	}
+}
+
+// Validate that the listed VMs are not being migrated
+// by another (executing) plan.
+func (r *Reconciler) validateActivePlans(plan *api.Plan) (err error) {
+	if plan.Status.HasCondition(Executing) {
+		return
+	}
+	inActivePlan := libcnd.Condition{
+		Type:     VMInActivePlan,
+		Status:   True,
+		Reason:   NotUnique,
+		Category: Critical,
+		Message:  "VM is being migrated by another plan.",
+		Items:    []string{},
+	}
+	// Plans in all namespaces using the same source
+	// provider are considered.
+	source := plan.Spec.Provider.Source
+	list := &api.PlanList{}
+	err = r.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Namespace == plan.Namespace && other.Name == plan.Name {
+			continue
+		}
+		if !other.Status.HasCondition(Executing) {
+			continue
+		}
+		otherSource := other.Spec.Provider.Source
+		if otherSource.Namespace != source.Namespace || otherSource.Name != source.Name {
+			continue
+		}
+		otherVMs := other.VMs()
+		for _, vm := range plan.VMs() {
+			if vm.Ref.NotSet() {
+				continue
+			}
+			for _, otherVM := range otherVMs {
+				if !vm.Ref.Same(otherVM.Ref) {
+					continue
+				}
+				description := fmt.Sprintf(
+					"VM: %s plan: %s",
+					vm.String(),
+					path.Join(other.Namespace, other.Name))
+				inActivePlan.Items = append(
+					inActivePlan.Items,
+					description)
+				break
+			}
+		}
+	}
+	if len(inActivePlan.Items) > 0 {
+		plan.Status.SetCondition(inActivePlan)
+	}
+
+	return
+}
+
+// Validate the VM selector and resolve it against the
+// source inventory. The selected VMs are recorded in the
+// status and merged into the (in-memory) list of VMs so they
+// are validated and migrated as though they were listed.
+// When frozen, the selection is not resolved while a
+// migration is active.
+func (r *Reconciler) validateSelector(plan *api.Plan) (err error) {
+	selector := plan.Spec.Selector
+	if selector == nil {
+		plan.Status.SelectedVMs = nil
+		return
+	}
+	newCnd := libcnd.Condition{
+		Type:     SelectorNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "The VM selector is not valid.",
+	}
+	frozen := selector.Freeze && migrationActive(plan)
+	if !frozen {
+		if !r.validateSelectorName(plan) {
+			return
+		}
+		provider := plan.Referenced.Provider.Source
+		if provider == nil {
+			return
+		}
+		pAdapter, pErr := adapter.New(provider)
+		if pErr != nil {
+			err = liberr.Wrap(pErr)
+			return
+		}
+		validator, pErr := pAdapter.Validator(plan)
+		if pErr != nil {
+			err = liberr.Wrap(pErr)
+			return
+		}
+		selected, pErr := validator.SelectVMs(selector)
+		if pErr != nil {
+			notSupported := adapter.SelectorNotSupportedError{}
+			if errors.As(pErr, &notSupported) {
+				newCnd.Reason = NotSupported
+				newCnd.Message = notSupported.Error()
+				plan.Status.SetCondition(newCnd)
+				return
+			}
+			notFound := web.NotFoundError{}
+			if errors.As(pErr, &notFound) {
+				newCnd.Reason = NotFound
+				newCnd.Message = "The VM selector references a resource not found in the inventory."
+				newCnd.Items = []string{notFound.Ref.String()}
+				plan.Status.SetCondition(newCnd)
+				return
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+		plan.Status.SelectedVMs = selected
+	}
+	plan.Spec.VMs = plan.VMs()
+
+	return
+}
+
+// Validate that the VM selector `name` is a valid
+// regular expression.
+func (r *Reconciler) validateSelectorName(plan *api.Plan) (valid bool) {
+	selector := plan.Spec.Selector
+	if selector == nil {
+		valid = true
+		return
+	}
+	_, err := regexp.Compile(selector.Name)
+	if err != nil {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     SelectorNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "The VM selector `name` is not a valid regular expression.",
+		})
+		return
+	}
+	valid = true
+
+	return
+}
+
+// Determine whether a migration of the plan is active.
+// The active snapshot references a migration that has
+// not reached a terminal state.
+func migrationActive(plan *api.Plan) bool {
+	snapshot := plan.Status.Migration.ActiveSnapshot()
+	if snapshot.Migration.UID == "" {
+		return false
+	}
+	return !snapshot.HasAnyCondition(Canceled, Failed, Succeeded)
+}
+
+// Resolve the inventory scope of the user that created or
+// last updated the plan spec on the source provider as
+// currently defined. Nil is unrestricted. Users permitted
+// to update the provider are not restricted.
+func (r *Reconciler) inventoryScope(plan *api.Plan) (scope *api.ProviderScope, err error) {
+	user := plan.Requester()
+	provider := plan.Referenced.Provider.Source
+	if user == nil || provider == nil || len(provider.Spec.Scopes) == 0 {
+		return
+	}
+	group, resource, err := api.GetGroupResource(provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	extra := map[string]auth.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			auth.ExtraValue{},
+			v...)
+	}
+	review := &auth.SubjectAccessReview{
+		Spec: auth.SubjectAccessReviewSpec{
+			ResourceAttributes: &auth.ResourceAttributes{
+				Group:     group,
+				Resource:  resource,
+				Namespace: provider.Namespace,
+				Name:      provider.Name,
+				Verb:      "update",
+			},
+			Extra:  extra,
+			Groups: user.Groups,
+			User:   user.Username,
+			UID:    user.UID,
+		},
+	}
+	err = r.Create(context.TODO(), review)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if review.Status.Allowed {
+		return
+	}
+	scope = provider.Spec.Scope(user.Username, user.Groups)
+	if scope == nil {
+		// Nothing permitted.
+		scope = &api.ProviderScope{}
+	}
+
+	return
+}
+
+// Determine whether the source VM has been migrated
+// or decommissioned by a previous migration and may
+// have been renamed or deleted.
+func migrated(plan *api.Plan, vmRef refapi.Ref) bool {
+	for _, vm := range plan.Status.Migration.VMs {
+		if vmRef.ID != "" && vm.ID != vmRef.ID {
+			continue
+		}
+		if vmRef.ID == "" && vm.Name != vmRef.Name {
+			continue
+		}
+		return vm.HasCondition(Succeeded) || len(vm.Decommission) > 0
+	}
+	return false
+}
+
 // Validate listed VMs.
 func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if plan.Status.HasCondition(Executing) {
@@ -336,12 +845,28 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has unmapped storage.",
 		Items:    []string{},
 	}
-	maintenanceMode := libcnd.Condition{
-		Type:     HostNotReady,
+	unmappedLUNs := libcnd.Condition{
+		Type:     VMLUNsNotMapped,
 		Status:   True,
-		Reason:   InMaintenanceMode,
-		Category: Warn,
-		Message:  "VM host is in maintenance mode.",
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "VM has RDM (direct LUN) disks that are neither copied nor mapped to a persistent volume.",
+		Items:    []string{},
+	}
+	encryptedDisks := libcnd.Condition{
+		Type:     VMDisksEncrypted,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Critical,
+		Message:  "VM has disks encrypted by vSphere VM encryption. VDDK cannot read vSphere-encrypted disks.",
+		Items:    []string{},
+	}
+	maintenanceMode := libcnd.Condition{
+		Type:     HostNotReady,
+		Status:   True,
+		Reason:   InMaintenanceMode,
+		Category: Warn,
+		Message:  "VM host is in maintenance mode.",
 		Items:    []string{},
 	}
 	multiplePodNetworkMappings := libcnd.Condition{
@@ -352,20 +877,49 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has more than one interface mapped to the pod network.",
 		Items:    []string{},
 	}
+	notPersisted := libcnd.Condition{
+		Type:     VMStateNotPersisted,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM has secure boot or a vTPM but the " + PersistentStateGate + " feature gate is not enabled on the destination cluster. The EFI variables and vTPM state will not be persisted.",
+		Items:    []string{},
+	}
+	unmappedCDROMs := libcnd.Condition{
+		Type:     VMCDROMsNotMapped,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Warn,
+		Message:  "VM has CD-ROMs without a mapped ISO. The CD-ROMs are not created on the target VM.",
+		Items:    []string{},
+	}
+	nvramNotCopied := libcnd.Condition{
+		Type:     VMNVRAMNotCopied,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM has secure boot but the EFI variables (NVRAM) are not copied from the source provider. The VM starts with the default secure boot keys enrolled.",
+		Items:    []string{},
+	}
+	notPermitted := libcnd.Condition{
+		Type:     VMNotPermitted,
+		Status:   True,
+		Reason:   NotPermitted,
+		Category: Critical,
+		Message:  "VM is not within the inventory scope of the user that created or last updated the plan.",
+		Items:    []string{},
+	}
 
+	scope, err := r.inventoryScope(plan)
+	if err != nil {
+		return err
+	}
 	setOf := map[string]bool{}
 	//
 	// Referenced VMs.
 	for i := range plan.Spec.VMs {
 		ref := &plan.Spec.VMs[i].Ref
 		if ref.NotSet() {
-			plan.Status.SetCondition(libcnd.Condition{
-				Type:     VMRefNotValid,
-				Status:   True,
-				Reason:   NotSet,
-				Category: Critical,
-				Message:  "Either `ID` or `Name` required.",
-			})
 			continue
 		}
 		// Source.
@@ -380,6 +934,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		_, pErr = inventory.VM(ref)
 		if pErr != nil {
 			if errors.As(pErr, &web.NotFoundError{}) {
+				if migrated(plan, *ref) {
+					continue
+				}
 				notFound.Items = append(notFound.Items, ref.String())
 				continue
 			}
@@ -430,7 +987,48 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				unmappedStorage.Items = append(unmappedStorage.Items, ref.String())
 			}
 		}
-		ok, err := validator.MaintenanceMode(*ref)
+		ok, err := validator.LUNs(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unmappedLUNs.Items = append(unmappedLUNs.Items, ref.String())
+		}
+		ok, err = validator.EncryptedDisks(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			encryptedDisks.Items = append(encryptedDisks.Items, ref.String())
+		}
+		ok, err = validator.CDROMs(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unmappedCDROMs.Items = append(unmappedCDROMs.Items, ref.String())
+		}
+		state, err := validator.PersistentState(*ref)
+		if err != nil {
+			return err
+		}
+		if state.EFI || state.TPM {
+			notPersisted.Items = append(notPersisted.Items, ref.String())
+		}
+		// The NVRAM is copied by the guest conversion.
+		if state.EFI && !provider.RequiresConversion() {
+			nvramNotCopied.Items = append(nvramNotCopied.Items, ref.String())
+		}
+		if scope != nil {
+			ok, err := validator.Scoped(*ref, scope)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				notPermitted.Items = append(notPermitted.Items, ref.String())
+			}
+		}
+		ok, err = validator.MaintenanceMode(*ref)
 		if err != nil {
 			return err
 		}
@@ -467,6 +1065,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(notFound.Items) > 0 {
 		plan.Status.SetCondition(notFound)
 	}
+	if len(notPermitted.Items) > 0 {
+		plan.Status.SetCondition(notPermitted)
+	}
 	if len(notUnique.Items) > 0 {
 		plan.Status.SetCondition(notUnique)
 	}
@@ -485,16 +1086,48 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(unmappedStorage.Items) > 0 {
 		plan.Status.SetCondition(unmappedStorage)
 	}
+	if len(unmappedLUNs.Items) > 0 {
+		plan.Status.SetCondition(unmappedLUNs)
+	}
+	if len(encryptedDisks.Items) > 0 {
+		plan.Status.SetCondition(encryptedDisks)
+	}
 	if len(maintenanceMode.Items) > 0 {
 		plan.Status.SetCondition(maintenanceMode)
 	}
 	if len(multiplePodNetworkMappings.Items) > 0 {
 		plan.Status.SetCondition(multiplePodNetworkMappings)
 	}
+	if len(notPersisted.Items) > 0 {
+		persistent, err := r.persistentStateEnabled(plan)
+		if err != nil {
+			return err
+		}
+		if !persistent {
+			plan.Status.SetCondition(notPersisted)
+		}
+	}
+	if len(unmappedCDROMs.Items) > 0 {
+		plan.Status.SetCondition(unmappedCDROMs)
+	}
+	if len(nvramNotCopied.Items) > 0 {
+		plan.Status.SetCondition(nvramNotCopied)
+	}
 
 	return nil
 }
 
+// Determine whether the firmware state can be persisted
+// on the destination cluster.
+func (r *Reconciler) persistentStateEnabled(plan *api.Plan) (enabled bool, err error) {
+	destination, err := r.destinationClient(plan.Referenced.Provider.Destination)
+	if err != nil {
+		return
+	}
+	enabled, err = persistentStateEnabled(destination)
+	return
+}
+
 // Validate transfer network selection.
 func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	if plan.Spec.TransferNetwork == nil {
@@ -525,6 +1158,604 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the device preservation.
+// The ISO mappings must be complete and the PVCs must
+// exist in the target namespace on the destination cluster.
+func (r *Reconciler) validateDevices(plan *api.Plan) (err error) {
+	devices := plan.Spec.Devices
+	if devices == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     DevicesNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "ISO mapping is not valid.",
+		Items:    []string{},
+	}
+	notFound := libcnd.Condition{
+		Type:     DevicesNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotFound,
+		Message:  "ISO PVC not found in the target namespace.",
+		Items:    []string{},
+	}
+	var destination client.Client
+	for i, iso := range devices.ISOs {
+		if iso.Source == "" || iso.ClaimName == "" {
+			notValid.Items = append(notValid.Items, fmt.Sprintf("[%d]", i))
+			continue
+		}
+		if destination == nil {
+			provider := plan.Referenced.Provider.Destination
+			if provider == nil {
+				// Reported by provider validation.
+				continue
+			}
+			destination, err = r.destinationClient(provider)
+			if err != nil {
+				return
+			}
+		}
+		pvc := &core.PersistentVolumeClaim{}
+		err = destination.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: plan.Spec.TargetNamespace,
+				Name:      iso.ClaimName,
+			},
+			pvc)
+		if k8serr.IsNotFound(err) {
+			err = nil
+			notFound.Items = append(notFound.Items, iso.ClaimName)
+			continue
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	} else if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+
+	return
+}
+
+// Validate the LUN handling.
+// The persistent volume mappings must be complete and the
+// persistent volumes must exist on the destination and be
+// claimable by the plan.
+func (r *Reconciler) validateLUNs(plan *api.Plan) (err error) {
+	luns := plan.Spec.LUNs
+	if luns == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     LUNsNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "LUN mapping is not valid.",
+		Items:    []string{},
+	}
+	notFound := libcnd.Condition{
+		Type:     LUNsNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotFound,
+		Message:  "LUN persistent volume not found.",
+		Items:    []string{},
+	}
+	var destination client.Client
+	for i, volume := range luns.Volumes {
+		if volume.LUN == "" || volume.PersistentVolume == "" {
+			notValid.Items = append(notValid.Items, fmt.Sprintf("[%d]", i))
+			continue
+		}
+		if destination == nil {
+			provider := plan.Referenced.Provider.Destination
+			if provider == nil {
+				// Reported by provider validation.
+				return
+			}
+			destination, err = r.destinationClient(provider)
+			if err != nil {
+				return
+			}
+		}
+		pv := &core.PersistentVolume{}
+		err = destination.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Name: volume.PersistentVolume,
+			},
+			pv)
+		if k8serr.IsNotFound(err) {
+			err = nil
+			notFound.Items = append(notFound.Items, volume.PersistentVolume)
+			continue
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		var claimable bool
+		claimable, err = lunVolumeClaimable(destination, plan, pv)
+		if err != nil {
+			return
+		}
+		if !claimable {
+			notValid.Items = append(notValid.Items, volume.PersistentVolume)
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	} else if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+
+	return
+}
+
+// Validate the LUKS keys.
+// The plan and VM secrets must exist in the target namespace.
+func (r *Reconciler) validateLUKS(plan *api.Plan) (err error) {
+	notFound := libcnd.Condition{
+		Type:     LUKSNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotFound,
+		Message:  "LUKS secret not found in the target namespace.",
+		Items:    []string{},
+	}
+	secrets := []*core.LocalObjectReference{plan.Spec.LUKS}
+	for _, vm := range plan.Spec.VMs {
+		secrets = append(secrets, vm.LUKS)
+	}
+	for _, ref := range secrets {
+		if ref == nil || ref.Name == "" {
+			continue
+		}
+		secret := &core.Secret{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: plan.Spec.TargetNamespace,
+				Name:      ref.Name,
+			},
+			secret)
+		if k8serr.IsNotFound(err) {
+			err = nil
+			notFound.Items = append(notFound.Items, ref.Name)
+			continue
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+
+	return
+}
+
+// Validate the guest customization.
+// The source provider must require guest conversion, the
+// ConfigMaps must exist in the target namespace and contain
+// the referenced keys.
+func (r *Reconciler) validateCustomization(plan *api.Plan) (err error) {
+	customization := append([]planapi.Customization{}, plan.Spec.Customization...)
+	for _, vm := range plan.Spec.VMs {
+		customization = append(customization, vm.Customization...)
+	}
+	if len(customization) == 0 {
+		return
+	}
+	notSupported := libcnd.Condition{
+		Type:     CustomizationNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotSupported,
+		Message:  "Guest customization is not supported by the source provider.",
+	}
+	notValid := libcnd.Condition{
+		Type:     CustomizationNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "Guest customization is not valid.",
+		Items:    []string{},
+	}
+	notFound := libcnd.Condition{
+		Type:     CustomizationNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotFound,
+		Message:  "Guest customization ConfigMap not found in the target namespace.",
+		Items:    []string{},
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider != nil && !provider.RequiresConversion() {
+		plan.Status.SetCondition(notSupported)
+		return
+	}
+	for i, c := range customization {
+		name := c.ConfigMap.Name
+		if name == "" {
+			notValid.Items = append(notValid.Items, fmt.Sprintf("[%d]", i))
+			continue
+		}
+		for _, upload := range c.Upload {
+			if !path.IsAbs(upload.Path) || hasControl(upload.Path) {
+				notValid.Items = append(notValid.Items, path.Join(name, upload.Key))
+			}
+		}
+		for j, pkg := range c.Install {
+			if pkg == "" || hasControl(pkg) || strings.Contains(pkg, ",") {
+				notValid.Items = append(notValid.Items, fmt.Sprintf("%s/install[%d]", name, j))
+			}
+		}
+		configMap := &core.ConfigMap{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: plan.Spec.TargetNamespace,
+				Name:      name,
+			},
+			configMap)
+		if k8serr.IsNotFound(err) {
+			err = nil
+			notFound.Items = append(notFound.Items, name)
+			continue
+		}
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, key := range c.Keys() {
+			_, inData := configMap.Data[key]
+			_, inBinary := configMap.BinaryData[key]
+			if !inData && !inBinary {
+				notValid.Items = append(notValid.Items, path.Join(name, key))
+			}
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	} else if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+
+	return
+}
+
+// Determine whether the string contains control characters.
+// The customization commands are newline separated.
+func hasControl(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
+
+// virt-v2v options permitted by the conversion configuration.
+// Maps the option to whether it takes a value. Options
+// controlling the input, output and credentials are set
+// by the conversion pod.
+var conversionOptions = map[string]bool{
+	"--root":         true,
+	"--mac":          true,
+	"-n":             true,
+	"--network":      true,
+	"-b":             true,
+	"--bridge":       true,
+	"--block-driver": true,
+	"-oa":            true,
+	"--compressed":   false,
+}
+
+// Validate the guest conversion configuration.
+// The source provider must require guest conversion, only
+// permitted virt-v2v options are accepted and the scratch
+// PVC must have a size.
+func (r *Reconciler) validateConversion(plan *api.Plan) {
+	conversion := []*planapi.Conversion{plan.Spec.Conversion}
+	for _, vm := range plan.Spec.VMs {
+		conversion = append(conversion, vm.Conversion)
+	}
+	notSupported := libcnd.Condition{
+		Type:     ConversionNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotSupported,
+		Message:  "Guest conversion is not performed for the source provider.",
+	}
+	notValid := libcnd.Condition{
+		Type:     ConversionNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "Guest conversion options or image not permitted or scratch volume not valid.",
+		Items:    []string{},
+	}
+	configured := false
+	for _, c := range conversion {
+		if c == nil {
+			continue
+		}
+		configured = true
+		notValid.Items = append(notValid.Items, unsafeOptions(c.Options)...)
+		if c.Image != "" && !Settings.Migration.VirtV2vPermittedImages[c.Image] {
+			notValid.Items = append(notValid.Items, c.Image)
+		}
+		if scratch := c.Scratch; scratch != nil {
+			if scratch.Size != nil && scratch.Size.Sign() <= 0 ||
+				scratch.Size == nil && scratch.StorageClass != "" {
+				notValid.Items = append(notValid.Items, "scratch")
+			}
+		}
+	}
+	if !configured {
+		return
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider != nil && !provider.RequiresConversion() {
+		plan.Status.SetCondition(notSupported)
+		return
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+}
+
+// Find the virt-v2v options that are not permitted.
+// Option values may be inline (`--option=value`) or
+// the next item.
+func unsafeOptions(options []string) (unsafe []string) {
+	for i := 0; i < len(options); i++ {
+		option := options[i]
+		name := strings.SplitN(option, "=", 2)[0]
+		value, permitted := conversionOptions[name]
+		if !permitted || strings.Contains(option, "\n") {
+			unsafe = append(unsafe, option)
+			continue
+		}
+		if !value {
+			if name != option {
+				unsafe = append(unsafe, option)
+			}
+			continue
+		}
+		if name == option {
+			i++
+			if i == len(options) || strings.Contains(options[i], "\n") {
+				unsafe = append(unsafe, option)
+			}
+		}
+	}
+
+	return
+}
+
+// Validate the metadata mapping.
+// Each mapping must have a known source kind, the name of
+// the tag category or custom attribute (as needed) and
+// exactly one valid label or annotation key.
+func (r *Reconciler) validateMetadataMapping(plan *api.Plan) {
+	notValid := libcnd.Condition{
+		Type:     MetadataMappingNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "Metadata mapping is not valid.",
+		Items:    []string{},
+	}
+	for i, mapping := range plan.Spec.MetadataMapping {
+		valid := true
+		switch mapping.Source {
+		case planapi.MetadataTag, planapi.MetadataAttribute:
+			valid = mapping.Name != ""
+		case planapi.MetadataAnnotation:
+		default:
+			valid = false
+		}
+		key := mapping.Label
+		if key == "" {
+			key = mapping.Annotation
+		} else if mapping.Annotation != "" {
+			valid = false
+		}
+		if len(k8svalidation.IsQualifiedName(key)) > 0 {
+			valid = false
+		}
+		if !valid {
+			notValid.Items = append(
+				notValid.Items,
+				fmt.Sprintf("[%d] source: %s name: %s", i, mapping.Source, mapping.Name))
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+}
+
+// Validate the precopy policies.
+// Intervals and thresholds must not be negative and the
+// effective minimum interval must not exceed the maximum.
+// Adaptive policies are reported when the source provider
+// cannot measure the precopy delta.
+func (r *Reconciler) validatePrecopy(plan *api.Plan) {
+	notValid := libcnd.Condition{
+		Type:     PrecopyNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Message:  "The precopy policy is not valid.",
+		Items:    []string{},
+	}
+	notAdaptive := libcnd.Condition{
+		Type:     PrecopyNotAdaptive,
+		Status:   True,
+		Category: Warn,
+		Reason:   NotSupported,
+		Message:  "Adaptive precopy is not supported by the source provider; the fixed interval is used.",
+		Items:    []string{},
+	}
+	measured := true
+	if provider := plan.Referenced.Provider.Source; provider != nil {
+		switch provider.Type() {
+		case api.OVirt, api.OpenStack:
+			measured = false
+		}
+	}
+	validate := func(name string, policy *planapi.PrecopyPolicy) {
+		if policy == nil {
+			return
+		}
+		if policy.Adaptive && !measured {
+			notAdaptive.Items = append(notAdaptive.Items, name)
+		}
+		if policy.Interval < 0 {
+			notValid.Items = append(notValid.Items, name+".interval")
+		}
+		if policy.MinInterval < 0 {
+			notValid.Items = append(notValid.Items, name+".minInterval")
+		}
+		if policy.MaxInterval < 0 {
+			notValid.Items = append(notValid.Items, name+".maxInterval")
+		}
+		if policy.ReadyThreshold < 0 {
+			notValid.Items = append(notValid.Items, name+".readyThreshold")
+		}
+		minInterval := Settings.PrecopyIntervalMin
+		maxInterval := Settings.PrecopyIntervalMax
+		for _, p := range []*planapi.PrecopyPolicy{plan.Spec.Precopy, policy} {
+			if p == nil {
+				continue
+			}
+			if p.MinInterval > 0 {
+				minInterval = p.MinInterval
+			}
+			if p.MaxInterval > 0 {
+				maxInterval = p.MaxInterval
+			}
+		}
+		if maxInterval > 0 && minInterval > maxInterval {
+			notValid.Items = append(notValid.Items, name+".minInterval > maxInterval")
+		}
+	}
+	validate("plan", plan.Spec.Precopy)
+	for _, vm := range plan.Spec.VMs {
+		validate(vm.String(), vm.Precopy)
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+	if len(notAdaptive.Items) > 0 {
+		plan.Status.SetCondition(notAdaptive)
+	}
+}
+
+// Validate the post-migration verification.
+func (r *Reconciler) validateVerification(plan *api.Plan) {
+	verification := plan.Spec.Verification
+	if verification == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     VerificationNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Items:    []string{},
+	}
+	checks := 0
+	if verification.TCPPort != 0 {
+		checks++
+		if len(k8svalidation.IsValidPortNum(int(verification.TCPPort))) > 0 {
+			notValid.Items = append(notValid.Items, "tcpPort")
+		}
+	}
+	if verification.HTTPGet != nil {
+		checks++
+		if len(k8svalidation.IsValidPortNum(int(verification.HTTPGet.Port))) > 0 {
+			notValid.Items = append(notValid.Items, "httpGet.port")
+		}
+		switch strings.ToUpper(verification.HTTPGet.Scheme) {
+		case "", "HTTP", "HTTPS":
+		default:
+			notValid.Items = append(notValid.Items, "httpGet.scheme")
+		}
+	}
+	if len(verification.Command) > 0 {
+		checks++
+	}
+	if verification.Timeout < 0 {
+		notValid.Items = append(notValid.Items, "timeout")
+	}
+	switch {
+	case checks > 1:
+		notValid.Message = "Verification may specify at most one health check."
+		plan.Status.SetCondition(notValid)
+	case len(notValid.Items) > 0:
+		notValid.Message = "Verification is not valid."
+		plan.Status.SetCondition(notValid)
+	}
+}
+
+// Validate the source VM decommission.
+func (r *Reconciler) validateDecommission(plan *api.Plan) {
+	decommission := plan.Spec.Decommission
+	if decommission == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     DecommissionNotValid,
+		Status:   True,
+		Category: Critical,
+		Reason:   NotValid,
+		Items:    []string{},
+	}
+	if decommission.Delete &&
+		(decommission.RenameSuffix != "" ||
+			decommission.Tag != "" ||
+			decommission.Annotation != "" ||
+			decommission.DisableAutostart ||
+			decommission.Folder != "") {
+		notValid.Message = "Decommission delete cannot be combined with other actions."
+		plan.Status.SetCondition(notValid)
+		return
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	switch provider.Type() {
+	case api.OVirt:
+		if decommission.Folder != "" {
+			notValid.Items = append(notValid.Items, "folder")
+		}
+	case api.OpenStack:
+		if decommission.Folder != "" {
+			notValid.Items = append(notValid.Items, "folder")
+		}
+		if decommission.Tag != "" {
+			notValid.Items = append(notValid.Items, "tag")
+		}
+		if decommission.DisableAutostart {
+			notValid.Items = append(notValid.Items, "disableAutostart")
+		}
+	}
+	if len(notValid.Items) > 0 {
+		notValid.Message = "Decommission actions not supported by the source provider."
+		plan.Status.SetCondition(notValid)
+	}
+}
+
 // Validate referenced hooks.
 func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 	notSet := libcnd.Condition{
@@ -590,6 +1821,7 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 				hook)
 			if err != nil {
 				if k8serr.IsNotFound(err) {
+					err = nil
 					description := fmt.Sprintf(
 						"VM: %s hook: %s",
 						vm.String(),
@@ -599,6 +1831,7 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 						description)
 					continue
 				} else {
+					err = liberr.Wrap(err)
 					return
 				}
 			} else {
@@ -618,7 +1851,7 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 			}
 		}
 	}
-	for _, cnd := range []libcnd.Condition{} {
+	for _, cnd := range []libcnd.Condition{notSet, notFound, notReady, stepNotValid} {
 		if len(cnd.Items) > 0 {
 			plan.Status.SetCondition(cnd)
 		}
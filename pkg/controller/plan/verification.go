@@ -0,0 +1,232 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+)
+
+// Default verification timeout (minutes).
+const (
+	DefaultVerificationTimeout = 10
+)
+
+// Start the target VM for verification.
+// The health check is performed by KubeVirt as a
+// readiness probe for the duration of the verification.
+func (r *Migration) startVerification(vm *plan.VMStatus) (err error) {
+	vmCr, err := r.targetVM(vm)
+	if err != nil {
+		return
+	}
+	probe := readinessProbe(r.Plan.Spec.Verification)
+	if probe != nil {
+		err = r.kubevirt.SetReadinessProbe(vmCr, probe)
+		if err != nil {
+			return
+		}
+	}
+	if vmCr.Spec.Running == nil || !*vmCr.Spec.Running {
+		err = r.kubevirt.SetRunning(vmCr, true)
+	}
+
+	return
+}
+
+// Verify the target VM.
+// The VM is verified when the VMI is running, the guest agent has
+// connected (when required) and the VMI is ready (when checked).
+// Returns the reason for the failure when the verification has failed.
+// Errors (such as transport errors) are transient; the verification
+// is retried until it succeeds, fails or times out.
+func (r *Migration) verifyVM(vm *plan.VMStatus, step *plan.Step) (verified bool, failure string, err error) {
+	verification := r.Plan.Spec.Verification
+	vmCr, found, err := r.findTargetVM(vm)
+	if err != nil {
+		return
+	}
+	if !found {
+		failure = "The VirtualMachine CR has been deleted."
+		return
+	}
+	vmi, found, err := r.kubevirt.GetVMI(vmCr)
+	if err != nil {
+		return
+	}
+	pending := []string{}
+	switch {
+	case !found:
+		pending = append(pending, "The VM instance has not been created.")
+	case vmi.Status.Phase == cnv.Failed:
+		failure = "The VM instance has failed."
+		return
+	case vmi.Status.Phase != cnv.Running:
+		pending = append(pending, "The VM is not running.")
+	default:
+		if verification.GuestAgent && !vmiCondition(vmi, cnv.VirtualMachineInstanceAgentConnected) {
+			pending = append(pending, "The guest agent has not connected.")
+		}
+		if verification.HasCheck() && !vmiCondition(vmi, cnv.VirtualMachineInstanceReady) {
+			pending = append(pending, "The health check has not passed.")
+		}
+	}
+	if len(pending) == 0 {
+		verified = true
+		return
+	}
+	step.Reason = strings.Join(pending, " ")
+	timeout := verification.Timeout
+	if timeout == 0 {
+		timeout = DefaultVerificationTimeout
+	}
+	if step.Started != nil && time.Since(step.Started.Time) > time.Duration(timeout)*time.Minute {
+		failure = fmt.Sprintf(
+			"Verification timed out after %d minutes. %s",
+			timeout,
+			step.Reason)
+	}
+
+	return
+}
+
+// End the verification.
+// The readiness probe is removed.
+func (r *Migration) endVerification(vm *plan.VMStatus) (err error) {
+	if !r.Plan.Spec.Verification.HasCheck() {
+		return
+	}
+	vmCr, err := r.targetVM(vm)
+	if err != nil {
+		return
+	}
+	err = r.kubevirt.SetReadinessProbe(vmCr, nil)
+	return
+}
+
+// Fail the verification.
+// The target VM is stopped; the VM and its disks are retained.
+// The verification is not failed until the VM has been stopped.
+func (r *Migration) verificationFailed(vm *plan.VMStatus, step *plan.Step, failure string) (err error) {
+	vmCr, found, err := r.findTargetVM(vm)
+	if err != nil {
+		return
+	}
+	if found {
+		if vmCr.Spec.Running == nil || *vmCr.Spec.Running {
+			err = r.kubevirt.SetRunning(vmCr, false)
+			if err != nil {
+				return
+			}
+		}
+		pErr := r.endVerification(vm)
+		if pErr != nil {
+			r.Log.Error(
+				pErr,
+				"Could not remove the verification readiness probe.",
+				"vm",
+				vm.String())
+		}
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     VerificationFailed,
+			Status:   True,
+			Category: Error,
+			Message:  failure,
+			Durable:  true,
+		})
+	step.AddError(failure)
+	r.record(
+		core.EventTypeWarning,
+		VerificationFailed,
+		fmt.Sprintf(
+			"VM %s verification has FAILED: %s",
+			vm.String(),
+			failure))
+
+	return
+}
+
+// Find the target VirtualMachine CR.
+func (r *Migration) targetVM(vm *plan.VMStatus) (vmCr *VirtualMachine, err error) {
+	vmCr, found, err := r.findTargetVM(vm)
+	if err != nil {
+		return
+	}
+	if !found {
+		err = liberr.New(
+			"VirtualMachine CR not found.",
+			"vm",
+			vm.String())
+	}
+	return
+}
+
+// Find the target VirtualMachine CR.
+// Returns: found=false when the CR does not exist.
+func (r *Migration) findTargetVM(vm *plan.VMStatus) (vmCr *VirtualMachine, found bool, err error) {
+	if r.vmMap == nil {
+		r.vmMap, err = r.kubevirt.VirtualMachineMap()
+		if err != nil {
+			return
+		}
+	}
+	object, found := r.vmMap[vm.ID]
+	if found {
+		vmCr = &object
+	}
+	return
+}
+
+// Build the readiness probe for the health check.
+func readinessProbe(verification *plan.Verification) (probe map[string]interface{}) {
+	switch {
+	case verification.TCPPort > 0:
+		probe = map[string]interface{}{
+			"tcpSocket": map[string]interface{}{
+				"port": verification.TCPPort,
+			},
+		}
+	case verification.HTTPGet != nil:
+		httpGet := map[string]interface{}{
+			"port": verification.HTTPGet.Port,
+		}
+		if verification.HTTPGet.Path != "" {
+			httpGet["path"] = verification.HTTPGet.Path
+		}
+		if verification.HTTPGet.Scheme != "" {
+			httpGet["scheme"] = strings.ToUpper(verification.HTTPGet.Scheme)
+		}
+		probe = map[string]interface{}{
+			"httpGet": httpGet,
+		}
+	case len(verification.Command) > 0:
+		probe = map[string]interface{}{
+			"exec": map[string]interface{}{
+				"command": verification.Command,
+			},
+		}
+	default:
+		return
+	}
+	probe["periodSeconds"] = 10
+	probe["timeoutSeconds"] = 5
+
+	return
+}
+
+// Determine whether the VMI condition is true.
+func vmiCondition(vmi *cnv.VirtualMachineInstance, cndType cnv.VirtualMachineInstanceConditionType) bool {
+	for _, cnd := range vmi.Status.Conditions {
+		if cnd.Type == cndType {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Client failing every Get.
+type unreachableClient struct {
+	client.Client
+}
+
+func (r *unreachableClient) Get(ctx context.Context, key client.ObjectKey, object client.Object) error {
+	return errors.New("connection refused")
+}
+
+func TestVerifyVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	running := true
+	vmCr := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+		Spec:       cnv.VirtualMachineSpec{Running: &running},
+	}
+	vmi := &cnv.VirtualMachineInstance{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+		Status: cnv.VirtualMachineInstanceStatus{
+			Phase: cnv.Running,
+			Conditions: []cnv.VirtualMachineInstanceCondition{
+				{Type: cnv.VirtualMachineInstanceReady, Status: core.ConditionTrue},
+			},
+		},
+	}
+	newMigration := func(objects ...client.Object) *Migration {
+		p := &api.Plan{}
+		p.Spec.Verification = &plan.Verification{TCPPort: 22}
+		ctx := &plancontext.Context{
+			Plan:      p,
+			Migration: &api.Migration{},
+			Log:       logging.WithName("test"),
+		}
+		ctx.Destination.Client = fakeClient(objects...)
+		m := &Migration{
+			Context:  ctx,
+			kubevirt: KubeVirt{Context: ctx},
+			vmMap: VirtualMachineMap{
+				"vm-1": VirtualMachine{VirtualMachine: vmCr.DeepCopy()},
+			},
+		}
+		return m
+	}
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	step := &plan.Step{}
+	step.MarkStarted()
+
+	// Verified.
+	m := newMigration(vmCr.DeepCopy(), vmi.DeepCopy())
+	verified, failure, err := m.verifyVM(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(failure).To(gomega.BeEmpty())
+	g.Expect(verified).To(gomega.BeTrue())
+
+	// Health check pending.
+	notReady := vmi.DeepCopy()
+	notReady.Status.Conditions = nil
+	m = newMigration(vmCr.DeepCopy(), notReady)
+	verified, failure, err = m.verifyVM(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(failure).To(gomega.BeEmpty())
+	g.Expect(verified).To(gomega.BeFalse())
+	g.Expect(step.Reason).To(gomega.ContainSubstring("health check"))
+
+	// VMI failed.
+	failed := vmi.DeepCopy()
+	failed.Status.Phase = cnv.Failed
+	m = newMigration(vmCr.DeepCopy(), failed)
+	_, failure, err = m.verifyVM(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(failure).ToNot(gomega.BeEmpty())
+
+	// VM CR deleted.
+	m = newMigration()
+	m.vmMap = VirtualMachineMap{}
+	_, failure, err = m.verifyVM(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(failure).ToNot(gomega.BeEmpty())
+
+	// Transport error is retried.
+	m = newMigration()
+	m.Destination.Client = &unreachableClient{}
+	_, failure, err = m.verifyVM(vm, step)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(failure).To(gomega.BeEmpty())
+}
+
+func TestVerificationFailed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	running := true
+	vmCr := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+		Spec:       cnv.VirtualMachineSpec{Running: &running},
+	}
+	p := &api.Plan{}
+	p.Spec.Verification = &plan.Verification{TCPPort: 22}
+	ctx := &plancontext.Context{
+		Plan:      p,
+		Migration: &api.Migration{},
+		Log:       logging.WithName("test"),
+	}
+	ctx.Destination.Client = fakeClient(vmCr.DeepCopy())
+	m := &Migration{
+		Context:  ctx,
+		kubevirt: KubeVirt{Context: ctx},
+		vmMap: VirtualMachineMap{
+			"vm-1": VirtualMachine{VirtualMachine: vmCr.DeepCopy()},
+		},
+	}
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	step := &plan.Step{}
+
+	// The VM is stopped.
+	err := m.verificationFailed(vm, step, "timed out")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.HasCondition(VerificationFailed)).To(gomega.BeTrue())
+	g.Expect(step.Error).ToNot(gomega.BeNil())
+	stopped := &cnv.VirtualMachine{}
+	err = ctx.Destination.Client.Get(context.TODO(), client.ObjectKeyFromObject(vmCr), stopped)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(*stopped.Spec.Running).To(gomega.BeFalse())
+
+	// Not failed until the VM is stopped.
+	vm = &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	step = &plan.Step{}
+	m.Destination.Client = &unreachableClient{Client: fakeClient()}
+	m.vmMap = VirtualMachineMap{
+		"vm-1": VirtualMachine{VirtualMachine: vmCr.DeepCopy()},
+	}
+	err = m.verificationFailed(vm, step, "timed out")
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(vm.HasCondition(VerificationFailed)).To(gomega.BeFalse())
+}
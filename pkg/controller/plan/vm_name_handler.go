@@ -7,12 +7,36 @@ import (
 	"strings"
 	"time"
 
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	"k8s.io/apimachinery/pkg/fields"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	cnv "kubevirt.io/client-go/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Ensure the target VM name is valid according to the DNS1123
+// labeling convention. An invalid name is changed once, before
+// the target VM or its persistent state is created, and the
+// source VM name is kept in the VM status.
+func (r *KubeVirt) ensureTargetName(vm *plan.VMStatus) (err error) {
+	if errs := k8svalidation.IsDNS1123Label(vm.Name); len(errs) == 0 {
+		return
+	}
+	originalName := vm.Name
+	vm.Name, err = r.changeVmNameDNS1123(vm.Name, r.Plan.Spec.TargetNamespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to update the VM name to meet DNS1123 protocol requirements.")
+		return
+	}
+	if vm.OriginalName == "" {
+		vm.OriginalName = originalName
+	}
+	r.Log.Info("VM name ", originalName, " was incompatible with DNS1123 RFC, changing to ",
+		vm.Name)
+	return
+}
+
 func (r *KubeVirt) changeVmNameDNS1123(vmName string, vmNamespace string) (generatedName string, err error) {
 	generatedName = changeVmName(vmName)
 	nameExist, errName := r.checkIfVmNameExistsInNamespace(generatedName, vmNamespace)
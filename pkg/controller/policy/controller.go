@@ -0,0 +1,201 @@
+/*
+Copyright 2019 Red Hat Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/controller/base"
+	agent "github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apiserver/pkg/storage/names"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// Name.
+	Name = "policy"
+	// Label identifying custom policy ConfigMaps.
+	PolicyLabel = "forklift.konveyor.io/validation-policy"
+	// Policy (module) key suffix.
+	ModuleSuffix = ".rego"
+	// Event reasons.
+	PolicyNotValid = "PolicyNotValid"
+)
+
+// Package logger.
+var log = logging.WithName(Name)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Creates a new custom policy controller and adds it to the Manager.
+// Custom (Rego) policies are loaded into the policy agent
+// from labeled ConfigMaps in the controller namespace.
+// Only the labeled ConfigMaps are watched and cached.
+func Add(mgr manager.Manager) error {
+	selected, err := Cache(mgr)
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+	reconciler := &Reconciler{
+		Reconciler: base.Reconciler{
+			EventRecorder: mgr.GetEventRecorderFor(Name),
+			Client:        mgr.GetClient(),
+			Log:           log,
+		},
+		selected: selected,
+	}
+	cnt, err := controller.New(
+		Name,
+		mgr,
+		controller.Options{
+			Reconciler: reconciler,
+		})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+	err = cnt.Watch(
+		source.NewKindWithCache(&core.ConfigMap{}, selected),
+		&handler.EnqueueRequestForObject{})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+
+	return nil
+}
+
+// Build the cache of (only) the labeled ConfigMaps in
+// the controller namespace and add it to the manager.
+func Cache(mgr manager.Manager) (selected cache.Cache, err error) {
+	labeled, err := labels.NewRequirement(PolicyLabel, selection.Exists, nil)
+	if err != nil {
+		return
+	}
+	selected, err = cache.New(
+		mgr.GetConfig(),
+		cache.Options{
+			Scheme:    mgr.GetScheme(),
+			Mapper:    mgr.GetRESTMapper(),
+			Namespace: Settings.PolicyAgent.Namespace,
+			SelectorsByObject: cache.SelectorsByObject{
+				&core.ConfigMap{}: {
+					Label: labels.NewSelector().Add(*labeled),
+				},
+			},
+		})
+	if err != nil {
+		return
+	}
+	err = mgr.Add(selected)
+	return
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciles custom policy ConfigMaps.
+type Reconciler struct {
+	base.Reconciler
+	// Labeled ConfigMaps.
+	selected client.Reader
+}
+
+// Reconcile custom policies.
+// All of the policy ConfigMaps are loaded on each reconcile
+// and periodically reloaded in case the agent restarted.
+// Note: Must not a pointer receiver to ensure that the
+// logger and other state is not shared.
+func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, err error) {
+	r.Log = logging.WithName(
+		names.SimpleNameGenerator.GenerateName(Name+"|"),
+		"configMap",
+		request)
+	r.Started()
+	defer func() {
+		result.RequeueAfter = r.Ended(
+			result.RequeueAfter,
+			err)
+		err = nil
+	}()
+	if !agent.Agent.Enabled() {
+		return
+	}
+	list := &core.ConfigMapList{}
+	err = r.selected.List(
+		context.TODO(),
+		list,
+		client.InNamespace(Settings.PolicyAgent.Namespace),
+		client.HasLabels{PolicyLabel})
+	if err != nil {
+		return
+	}
+	modules := map[string]string{}
+	owner := map[string]*core.ConfigMap{}
+	for i := range list.Items {
+		cm := &list.Items[i]
+		for key, module := range cm.Data {
+			if !strings.HasSuffix(key, ModuleSuffix) {
+				continue
+			}
+			id := path.Join(cm.Name, strings.TrimSuffix(key, ModuleSuffix))
+			modules[id] = module
+			owner[id] = cm
+		}
+	}
+	rejected, err := agent.Custom.Sync(&agent.Agent.Client, modules)
+	if err != nil {
+		return
+	}
+	for id, reason := range rejected {
+		r.Log.Info(
+			"Custom policy rejected.",
+			"id",
+			id,
+			"reason",
+			reason.Error())
+		r.EventRecorder.Event(
+			owner[id],
+			core.EventTypeWarning,
+			PolicyNotValid,
+			id+": "+reason.Error())
+	}
+	r.Log.V(1).Info(
+		"Custom policies loaded.",
+		"count",
+		agent.Custom.Len())
+
+	result.RequeueAfter = time.Second * time.Duration(
+		Settings.PolicyAgent.SearchInterval)
+
+	return
+}
@@ -161,6 +161,9 @@ func (r *VMEventHandler) run() {
 		case <-time.After(interval):
 			r.list()
 			r.reset()
+		case <-policy.Custom.Changed():
+			r.list()
+			r.reset()
 		case _, open := <-r.latch:
 			if open {
 				r.list()
@@ -199,6 +199,11 @@ type VM struct {
 		Version struct {
 			Full string `json:"full_version"`
 		} `json:"os"`
+		Boot struct {
+			Devices struct {
+				Device []string `json:"device"`
+			} `json:"devices"`
+		} `json:"boot"`
 	}
 	CPU struct {
 		Tune struct {
@@ -225,6 +230,7 @@ type VM struct {
 	} `json:"time_zone"`
 	Status       string `json:"status"`
 	Stateless    string `json:"stateless"`
+	TpmEnabled   string `json:"tpm_enabled"`
 	SerialNumber struct {
 		Value string `json:"value"`
 	} `json:"serial_number"`
@@ -302,6 +308,7 @@ type VM struct {
 			Name            string
 			Interface       string `json:"interface"`
 			SCSIReservation string `json:"uses_scsi_reservation"`
+			Bootable        string `json:"bootable"`
 			Disk            Ref    `json:"disk"`
 		} `json:"disk_attachment"`
 	} `json:"disk_attachments"`
@@ -338,6 +345,7 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.Guest.Distribution = r.Guest.Distribution
 	m.Guest.FullVersion = r.Guest.Version.Full
 	m.OSType = r.OS.Type
+	m.BootDevices = r.OS.Boot.Devices.Device
 	m.CpuSockets = r.int16(r.CPU.Topology.Sockets)
 	m.CpuCores = r.int16(r.CPU.Topology.Cores)
 	m.CpuThreads = r.int16(r.CPU.Topology.Threads)
@@ -347,6 +355,7 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.BIOS = r.BIOS.Type
 	m.UsbEnabled = r.bool(r.USB.Enabled)
 	m.BootMenuEnabled = r.bool(r.BIOS.BootMenu.Enabled)
+	m.TpmEnabled = r.bool(r.TpmEnabled)
 	m.PlacementPolicyAffinity = r.PlacementPolicy.Affinity
 	m.Timezone = r.Timezone.Name
 	m.Status = r.Status
@@ -417,6 +426,7 @@ func (r *VM) addDiskAttachment(m *model.VM) {
 				ID:              da.ID,
 				Interface:       da.Interface,
 				SCSIReservation: r.bool(da.SCSIReservation),
+				Bootable:        r.bool(da.Bootable),
 				Disk:            da.Disk.ID,
 			})
 	}
@@ -647,6 +657,18 @@ type Disk struct {
 	ActualSize  string `json:"actual_size"`
 	Backup      string `json:"backup"`
 	StorageType string `json:"storage_type"`
+	LunStorage  struct {
+		LogicalUnits struct {
+			List []LogicalUnit `json:"logical_unit"`
+		} `json:"logical_units"`
+	} `json:"lun_storage"`
+}
+
+// Direct LUN logical unit.
+type LogicalUnit struct {
+	ID     string `json:"id"`
+	Serial string `json:"serial"`
+	Size   string `json:"size"`
 }
 
 // Apply to (update) the model.
@@ -661,6 +683,7 @@ func (r *Disk) ApplyTo(m *model.Disk) {
 	m.StorageType = r.StorageType
 	m.ProvisionedSize = r.int64(r.ProvisionedSize)
 	r.setStorageDomain(m)
+	r.setLun(m)
 }
 
 func (r *Disk) setStorageDomain(m *model.Disk) {
@@ -670,6 +693,16 @@ func (r *Disk) setStorageDomain(m *model.Disk) {
 	}
 }
 
+func (r *Disk) setLun(m *model.Disk) {
+	m.Lun = model.Lun{}
+	for _, lu := range r.LunStorage.LogicalUnits.List {
+		m.Lun.ID = lu.ID
+		m.Lun.Serial = lu.Serial
+		m.Lun.Size = r.int64(lu.Size)
+		break
+	}
+}
+
 // Disk (list).
 type DiskList struct {
 	Items []Disk `json:"disk"`
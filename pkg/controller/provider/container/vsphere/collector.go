@@ -32,6 +32,8 @@ const (
 	RetryDelay = time.Second * 5
 	// Max object in each update.
 	MaxObjectUpdates = 10000
+	// Tags and custom field definitions refresh interval.
+	MetadataRefreshInterval = time.Minute * 5
 )
 
 // Types
@@ -98,6 +100,8 @@ const (
 	// VM
 	fUUID                = "config.uuid"
 	fFirmware            = "config.firmware"
+	fSecureBoot          = "config.bootOptions.efiSecureBootEnabled"
+	fBootOrder           = "config.bootOptions.bootOrder"
 	fFtInfo              = "config.ftInfo"
 	fCpuAffinity         = "config.cpuAffinity"
 	fCpuHotAddEnabled    = "config.cpuHotAddEnabled"
@@ -108,6 +112,7 @@ const (
 	fMemorySize          = "config.hardware.memoryMB"
 	fDevices             = "config.hardware.device"
 	fExtraConfig         = "config.extraConfig"
+	fVmPathName          = "config.files.vmPathName"
 	fChangeTracking      = "config.changeTrackingEnabled"
 	fGuestName           = "summary.config.guestFullName"
 	fGuestID             = "summary.guest.guestId"
@@ -119,6 +124,8 @@ const (
 	fConnectionState     = "runtime.connectionState"
 	fSnapshot            = "snapshot"
 	fIsTemplate          = "config.template"
+	fAnnotation          = "config.annotation"
+	fCustomValue         = "customValue"
 )
 
 // Selections
@@ -226,6 +233,8 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// Custom field definitions.
+	customFields *CustomFields
 }
 
 // New collector.
@@ -236,11 +245,12 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) *Collector
 			provider.GetNamespace(),
 			provider.GetName()))
 	return &Collector{
-		url:      provider.Spec.URL,
-		provider: provider,
-		secret:   secret,
-		db:       db,
-		log:      nlog,
+		url:          provider.Spec.URL,
+		provider:     provider,
+		secret:       secret,
+		db:           db,
+		log:          nlog,
+		customFields: &CustomFields{},
 	}
 }
 
@@ -365,6 +375,8 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	}
 	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
+	refreshCtx, refreshCancel := context.WithCancel(ctx)
+	defer refreshCancel()
 	defer func() {
 		r.parity = false
 		for _, w := range watchList {
@@ -419,6 +431,7 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 					"duration",
 					time.Since(mark))
 				watchList = r.watch()
+				go r.refreshMetadata(refreshCtx, r.client.Client)
 			}
 		}
 	}
@@ -682,6 +695,8 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fParent,
 				fUUID,
 				fFirmware,
+				fSecureBoot,
+				fBootOrder,
 				fFtInfo,
 				fCpuAffinity,
 				fCpuHotAddEnabled,
@@ -692,6 +707,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fMemorySize,
 				fDevices,
 				fExtraConfig,
+				fVmPathName,
 				fGuestName,
 				fGuestID,
 				fBalloonedMemory,
@@ -705,6 +721,8 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fIsTemplate,
 				fSnapshot,
 				fChangeTracking,
+				fAnnotation,
+				fCustomValue,
 			},
 		},
 	}
@@ -817,6 +835,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 					ID: u.Obj.Value,
 				},
 			},
+			fields: r.customFields,
 		}
 	default:
 		r.log.Info("Unknown", "kind", u.Obj.Type)
@@ -0,0 +1,178 @@
+package vsphere
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Custom field definitions.
+// Maps the custom field key to the name.
+type CustomFields struct {
+	mutex sync.RWMutex
+	names map[int32]string
+}
+
+// Find the name of the custom field.
+func (r *CustomFields) Name(key int32) (name string) {
+	if r == nil {
+		return
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	name = r.names[key]
+	return
+}
+
+// Replace the definitions.
+func (r *CustomFields) Set(names map[int32]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.names = names
+}
+
+// Refresh the VM tags and custom attribute names until canceled.
+// vSphere tags are not available through the property collector
+// and custom field definitions are not watched so both are
+// refreshed periodically.
+func (r *Collector) refreshMetadata(ctx context.Context, client *vim25.Client) {
+	for {
+		err := r.refresh(ctx, client)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.log.Error(
+				err,
+				"refresh metadata failed.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(MetadataRefreshInterval):
+		}
+	}
+}
+
+// Refresh the VM tags and custom attribute names.
+func (r *Collector) refresh(ctx context.Context, client *vim25.Client) (err error) {
+	err = r.loadCustomFields(ctx, client)
+	if err != nil {
+		return
+	}
+	tags, err := r.vmTags(ctx, client)
+	if err != nil {
+		// The vAPI is not available on all endpoints (ESXi).
+		r.log.V(1).Info(
+			"VM tags not collected.",
+			"reason",
+			err.Error())
+		tags = nil
+		err = nil
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	list := []model.VM{}
+	err = tx.List(&list, libmodel.ListOptions{Detail: libmodel.MaxDetail})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list {
+		vm := &list[i]
+		changed := false
+		if tags != nil {
+			vmTags := tags[vm.ID]
+			if len(vmTags) != len(vm.Tags) ||
+				(len(vmTags) > 0 && !reflect.DeepEqual(vmTags, vm.Tags)) {
+				vm.Tags = vmTags
+				changed = true
+			}
+		}
+		for i := range vm.CustomAttributes {
+			attribute := &vm.CustomAttributes[i]
+			name := r.customFields.Name(attribute.Key)
+			if name != attribute.Name {
+				attribute.Name = name
+				changed = true
+			}
+		}
+		if changed {
+			err = tx.Update(vm)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Load the custom field definitions.
+func (r *Collector) loadCustomFields(ctx context.Context, client *vim25.Client) (err error) {
+	ref := client.ServiceContent.CustomFieldsManager
+	if ref == nil {
+		return
+	}
+	manager := mo.CustomFieldsManager{}
+	pc := property.DefaultCollector(client)
+	err = pc.RetrieveOne(ctx, *ref, []string{"field"}, &manager)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	names := map[int32]string{}
+	for _, field := range manager.Field {
+		names[field.Key] = field.Name
+	}
+	r.customFields.Set(names)
+
+	return
+}
+
+// Find the tags attached to each VM using the vAPI.
+// Returns: map of tags keyed by VM ID.
+func (r *Collector) vmTags(ctx context.Context, client *vim25.Client) (tags map[string][]model.Tag, err error) {
+	tagging, err := NewTagging(ctx, client, r.user(), r.password())
+	if err != nil {
+		return
+	}
+	defer tagging.Close()
+	list := []model.VM{}
+	err = r.db.List(&list, libmodel.ListOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	refs := []types.ManagedObjectReference{}
+	for _, vm := range list {
+		refs = append(
+			refs,
+			types.ManagedObjectReference{
+				Type:  VirtualMachine,
+				Value: vm.ID,
+			})
+	}
+	tags, err = tagging.AttachedTags(ctx, refs)
+
+	return
+}
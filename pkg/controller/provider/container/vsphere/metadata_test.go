@@ -0,0 +1,109 @@
+package vsphere
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
+)
+
+func TestVmAdapterMetadata(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	fields := &CustomFields{}
+	fields.Set(map[int32]string{1: "owner"})
+	adapter := &VmAdapter{fields: fields}
+	adapter.Apply(types.ObjectUpdate{
+		ChangeSet: []types.PropertyChange{
+			{Op: Assign, Name: fAnnotation, Val: "Database server."},
+			{
+				Op:   Assign,
+				Name: fCustomValue,
+				Val: types.ArrayOfCustomFieldValue{
+					CustomFieldValue: []types.BaseCustomFieldValue{
+						&types.CustomFieldStringValue{
+							CustomFieldValue: types.CustomFieldValue{Key: 1},
+							Value:            "dba",
+						},
+						&types.CustomFieldStringValue{
+							CustomFieldValue: types.CustomFieldValue{Key: 2},
+							Value:            "unknown",
+						},
+						// Not a string value.
+						&types.CustomFieldValue{Key: 3},
+					},
+				},
+			},
+		},
+	})
+	vm := adapter.Model().(*model.VM)
+	g.Expect(vm.Annotation).To(gomega.Equal("Database server."))
+	g.Expect(vm.CustomAttributes).To(gomega.Equal([]model.CustomAttribute{
+		{Key: 1, Name: "owner", Value: "dba"},
+		{Key: 2, Name: "", Value: "unknown"},
+	}))
+
+	// Values removed.
+	adapter.Apply(types.ObjectUpdate{
+		ChangeSet: []types.PropertyChange{
+			{Op: Assign, Name: fCustomValue, Val: types.ArrayOfCustomFieldValue{}},
+		},
+	})
+	g.Expect(vm.CustomAttributes).To(gomega.BeEmpty())
+}
+
+func TestRefreshMetadata(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newVapiServer()
+	defer server.Close()
+	server.categories["c1"] = "env"
+	server.tags["t1"] = model.Tag{ID: "t1", Name: "prod", Category: "env"}
+	server.attached["vm-1"] = []string{"t1"}
+	db := libmodel.New(filepath.Join(t.TempDir(), "test.db"), model.All()...)
+	g.Expect(db.Open(true)).To(gomega.Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	tagged := &model.VM{
+		Base:             model.Base{ID: "vm-1"},
+		CustomAttributes: []model.CustomAttribute{{Key: 1, Value: "dba"}},
+	}
+	untagged := &model.VM{
+		Base: model.Base{ID: "vm-2"},
+		Tags: []model.Tag{{ID: "t2", Name: "old", Category: "env"}},
+	}
+	g.Expect(db.Insert(tagged)).To(gomega.Succeed())
+	g.Expect(db.Insert(untagged)).To(gomega.Succeed())
+	collector := &Collector{
+		db:           db,
+		log:          logging.WithName("test"),
+		secret:       &core.Secret{},
+		customFields: &CustomFields{},
+	}
+	collector.customFields.Set(map[int32]string{1: "owner"})
+
+	g.Expect(collector.refresh(context.TODO(), server.client())).To(gomega.Succeed())
+	vm := &model.VM{Base: model.Base{ID: "vm-1"}}
+	g.Expect(db.Get(vm)).To(gomega.Succeed())
+	g.Expect(vm.Tags).To(gomega.Equal([]model.Tag{server.tags["t1"]}))
+	g.Expect(vm.CustomAttributes).To(gomega.Equal([]model.CustomAttribute{
+		{Key: 1, Name: "owner", Value: "dba"},
+	}))
+	vm = &model.VM{Base: model.Base{ID: "vm-2"}}
+	g.Expect(db.Get(vm)).To(gomega.Succeed())
+	g.Expect(vm.Tags).To(gomega.BeEmpty())
+
+	// vAPI not available, the tags are kept.
+	server.Close()
+	collector.customFields.Set(map[int32]string{1: "team"})
+	g.Expect(collector.refresh(context.TODO(), server.client())).To(gomega.Succeed())
+	vm = &model.VM{Base: model.Base{ID: "vm-1"}}
+	g.Expect(db.Get(vm)).To(gomega.Succeed())
+	g.Expect(vm.Tags).To(gomega.Equal([]model.Tag{server.tags["t1"]}))
+	g.Expect(vm.CustomAttributes[0].Name).To(gomega.Equal("team"))
+}
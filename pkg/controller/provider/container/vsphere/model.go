@@ -487,6 +487,8 @@ type VmAdapter struct {
 	Base
 	// The adapter model.
 	model model.VM
+	// Custom field definitions.
+	fields *CustomFields
 }
 
 // The adapter model.
@@ -511,6 +513,14 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.Firmware = s
 				}
+			case fSecureBoot:
+				if b, cast := p.Val.(bool); cast {
+					v.model.SecureBoot = b
+				}
+			case fBootOrder:
+				if devices, cast := p.Val.(types.ArrayOfVirtualMachineBootOptionsBootableDevice); cast {
+					v.updateBootOrder(&devices)
+				}
 			case fPowerState:
 				if s, cast := p.Val.(types.VirtualMachinePowerState); cast {
 					v.model.PowerState = string(s)
@@ -596,8 +606,33 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				}
 			case fNetwork:
 				v.model.Networks = v.RefList(p.Val)
+			case fAnnotation:
+				if s, cast := p.Val.(string); cast {
+					v.model.Annotation = s
+				}
+			case fCustomValue:
+				if values, cast := p.Val.(types.ArrayOfCustomFieldValue); cast {
+					attributes := []model.CustomAttribute{}
+					for _, val := range values.CustomFieldValue {
+						if sv, cast := val.(*types.CustomFieldStringValue); cast {
+							attributes = append(
+								attributes,
+								model.CustomAttribute{
+									Key:   sv.Key,
+									Name:  v.fields.Name(sv.Key),
+									Value: sv.Value,
+								})
+						}
+					}
+					v.model.CustomAttributes = attributes
+				}
+			case fVmPathName:
+				if s, cast := p.Val.(string); cast {
+					v.model.ConfigFile = s
+				}
 			case fExtraConfig:
 				if options, cast := p.Val.(types.ArrayOfOptionValue); cast {
+					v.model.NVRAM = ""
 					for _, val := range options.OptionValue {
 						opt := val.GetOptionValue()
 						switch opt.Key {
@@ -605,6 +640,10 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 							if s, cast := opt.Value.(string); cast {
 								v.model.NumaNodeAffinity = strings.Split(s, ",")
 							}
+						case "nvram":
+							if s, cast := opt.Value.(string); cast {
+								v.model.NVRAM = s
+							}
 						}
 					}
 				}
@@ -612,6 +651,7 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if devArray, cast := p.Val.(types.ArrayOfVirtualDevice); cast {
 					devList := []model.Device{}
 					nicList := []model.NIC{}
+					tpmEnabled := false
 					for _, dev := range devArray.VirtualDevice {
 						var nic *types.VirtualEthernetCard
 						switch device := dev.(type) {
@@ -624,6 +664,8 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 								model.Device{
 									Kind: libref.ToKind(dev),
 								})
+						case *types.VirtualTPM:
+							tpmEnabled = true
 						case *types.VirtualE1000:
 							nic = &device.VirtualEthernetCard
 						case *types.VirtualE1000e:
@@ -666,7 +708,9 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 					}
 					v.model.Devices = devList
 					v.model.NICs = nicList
+					v.model.TpmEnabled = tpmEnabled
 					v.updateDisks(&devArray)
+					v.updateCDROMs(&devArray)
 				}
 			}
 		}
@@ -675,11 +719,13 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 
 // Update virtual disk devices.
 func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
+	buses := v.buses(devArray)
 	disks := []model.Disk{}
 	for _, dev := range devArray.VirtualDevice {
 		switch dev.(type) {
 		case *types.VirtualDisk:
 			disk := dev.(*types.VirtualDisk)
+			bus := buses[disk.ControllerKey]
 			switch disk.Backing.(type) {
 			case *types.VirtualDiskFlatVer1BackingInfo:
 				backing := disk.Backing.(*types.VirtualDiskFlatVer1BackingInfo)
@@ -687,6 +733,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Key:      disk.Key,
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
+					Bus:      bus,
 					Datastore: model.Ref{
 						Kind: model.DsKind,
 						ID:   backing.Datastore.Value,
@@ -699,11 +746,13 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Key:      disk.Key,
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
+					Bus:      bus,
 					Shared:   backing.Sharing != "sharingNone",
 					Datastore: model.Ref{
 						Kind: model.DsKind,
 						ID:   backing.Datastore.Value,
 					},
+					VMEncrypted: backing.KeyId != nil,
 				}
 				disks = append(disks, md)
 			case *types.VirtualDiskRawDiskMappingVer1BackingInfo:
@@ -712,12 +761,14 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Key:      disk.Key,
 					File:     backing.FileName,
 					Capacity: disk.CapacityInBytes,
+					Bus:      bus,
 					Shared:   backing.Sharing != "sharingNone",
 					Datastore: model.Ref{
 						Kind: model.DsKind,
 						ID:   backing.Datastore.Value,
 					},
 					RDM: true,
+					LUN: backing.LunUuid,
 				}
 				disks = append(disks, md)
 			case *types.VirtualDiskRawDiskVer2BackingInfo:
@@ -726,6 +777,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Key:      disk.Key,
 					File:     backing.DescriptorFileName,
 					Capacity: disk.CapacityInBytes,
+					Bus:      bus,
 					Shared:   backing.Sharing != "sharingNone",
 					RDM:      true,
 				}
@@ -736,3 +788,64 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 
 	v.model.Disks = disks
 }
+
+// Update virtual CD-ROM devices.
+func (v *VmAdapter) updateCDROMs(devArray *types.ArrayOfVirtualDevice) {
+	buses := v.buses(devArray)
+	cdroms := []model.CDROM{}
+	for _, dev := range devArray.VirtualDevice {
+		cdrom, cast := dev.(*types.VirtualCdrom)
+		if !cast {
+			continue
+		}
+		md := model.CDROM{
+			Key: cdrom.Key,
+			Bus: buses[cdrom.ControllerKey],
+		}
+		if backing, cast := cdrom.Backing.(*types.VirtualCdromIsoBackingInfo); cast {
+			md.ISO = backing.FileName
+		}
+		cdroms = append(cdroms, md)
+	}
+
+	v.model.CDROMs = cdroms
+}
+
+// Disk controller buses by (controller) device key.
+func (v *VmAdapter) buses(devArray *types.ArrayOfVirtualDevice) (buses map[int32]string) {
+	buses = map[int32]string{}
+	for _, dev := range devArray.VirtualDevice {
+		key := dev.GetVirtualDevice().Key
+		switch dev.(type) {
+		case types.BaseVirtualSCSIController:
+			buses[key] = model.BusSCSI
+		case types.BaseVirtualSATAController:
+			buses[key] = model.BusSATA
+		case *types.VirtualIDEController:
+			buses[key] = model.BusIDE
+		case *types.VirtualNVMEController:
+			buses[key] = model.BusNVME
+		}
+	}
+
+	return
+}
+
+// Update the boot order.
+func (v *VmAdapter) updateBootOrder(devices *types.ArrayOfVirtualMachineBootOptionsBootableDevice) {
+	order := []model.BootDevice{}
+	for _, dev := range devices.VirtualMachineBootOptionsBootableDevice {
+		switch device := dev.(type) {
+		case *types.VirtualMachineBootOptionsBootableDiskDevice:
+			order = append(order, model.BootDevice{Kind: model.BootDisk, Key: device.DeviceKey})
+		case *types.VirtualMachineBootOptionsBootableCdromDevice:
+			order = append(order, model.BootDevice{Kind: model.BootCDROM})
+		case *types.VirtualMachineBootOptionsBootableEthernetDevice:
+			order = append(order, model.BootDevice{Kind: model.BootEthernet, Key: device.DeviceKey})
+		case *types.VirtualMachineBootOptionsBootableFloppyDevice:
+			order = append(order, model.BootDevice{Kind: model.BootFloppy})
+		}
+	}
+
+	v.model.BootOrder = order
+}
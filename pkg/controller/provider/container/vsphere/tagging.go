@@ -0,0 +1,291 @@
+package vsphere
+
+import (
+	"context"
+	"net/http"
+	liburl "net/url"
+	"sort"
+	"strings"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vAPI tagging paths.
+const (
+	TagPath         = "/com/vmware/cis/tagging/tag"
+	CategoryPath    = "/com/vmware/cis/tagging/category"
+	AssociationPath = "/com/vmware/cis/tagging/tag-association"
+)
+
+// vAPI object ID.
+type objectID struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// vAPI tagging client.
+// Tags and categories are fetched once and cached
+// for the life of the client.
+type Tagging struct {
+	rc *rest.Client
+	// Cached tags keyed by ID.
+	tags map[string]model.Tag
+	// Cached category names keyed by ID.
+	categories map[string]string
+}
+
+// Build an authenticated vAPI tagging client.
+// The client must be closed.
+func NewTagging(ctx context.Context, client *vim25.Client, user, password string) (tagging *Tagging, err error) {
+	rc := rest.NewClient(client)
+	err = rc.Login(ctx, liburl.UserPassword(user, password))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	tagging = &Tagging{
+		rc:         rc,
+		tags:       map[string]model.Tag{},
+		categories: map[string]string{},
+	}
+
+	return
+}
+
+// Logout.
+func (r *Tagging) Close() {
+	_ = r.rc.Logout(context.Background())
+}
+
+// Find the tags attached to the objects using a single
+// list-attached-tags-on-objects request.
+// Returns: map of (sorted) tags keyed by object ID.
+func (r *Tagging) AttachedTags(ctx context.Context, refs []types.ManagedObjectReference) (tags map[string][]model.Tag, err error) {
+	tags = map[string][]model.Tag{}
+	if len(refs) == 0 {
+		return
+	}
+	request := struct {
+		ObjectIDs []objectID `json:"object_ids"`
+	}{}
+	for _, ref := range refs {
+		request.ObjectIDs = append(
+			request.ObjectIDs,
+			objectID{
+				ID:   ref.Value,
+				Type: ref.Type,
+			})
+	}
+	attached := []struct {
+		ObjectID objectID `json:"object_id"`
+		TagIDs   []string `json:"tag_ids"`
+	}{}
+	err = r.rc.Do(
+		ctx,
+		r.rc.Resource(AssociationPath).
+			WithAction("list-attached-tags-on-objects").
+			Request(http.MethodPost, request),
+		&attached)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, object := range attached {
+		for _, id := range object.TagIDs {
+			tag, tErr := r.Tag(ctx, id)
+			if tErr != nil {
+				err = tErr
+				return
+			}
+			tags[object.ObjectID.ID] = append(tags[object.ObjectID.ID], tag)
+		}
+	}
+	for id := range tags {
+		objectTags := tags[id]
+		sort.Slice(objectTags, func(i, j int) bool {
+			return objectTags[i].ID < objectTags[j].ID
+		})
+	}
+
+	return
+}
+
+// Find a tag by `category:name` or `name`.
+// When the category is specified, only the tags in the category
+// are listed using a single list-tags-for-category request.
+// Returns the tag ID.
+func (r *Tagging) FindTag(ctx context.Context, tag string) (id string, err error) {
+	category, name := "", tag
+	if n := strings.Index(tag, ":"); n != -1 {
+		category, name = tag[:n], tag[n+1:]
+	}
+	ids := []string{}
+	if category != "" {
+		categoryID, found, cErr := r.findCategory(ctx, category)
+		if cErr != nil {
+			err = cErr
+			return
+		}
+		if !found {
+			err = liberr.New("Tag category not found.", "tag", tag)
+			return
+		}
+		request := struct {
+			ID string `json:"category_id"`
+		}{
+			ID: categoryID,
+		}
+		err = r.rc.Do(
+			ctx,
+			r.rc.Resource(TagPath).
+				WithID(categoryID).
+				WithAction("list-tags-for-category").
+				Request(http.MethodPost, request),
+			&ids)
+	} else {
+		err = r.rc.Do(
+			ctx,
+			r.rc.Resource(TagPath).Request(http.MethodGet),
+			&ids)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, tagID := range ids {
+		found, tErr := r.Tag(ctx, tagID)
+		if tErr != nil {
+			err = tErr
+			return
+		}
+		if found.Name == name {
+			id = tagID
+			return
+		}
+	}
+	err = liberr.New("Tag not found.", "tag", tag)
+
+	return
+}
+
+// Get the tag (and category name).
+func (r *Tagging) Tag(ctx context.Context, id string) (tag model.Tag, err error) {
+	tag, cached := r.tags[id]
+	if cached {
+		return
+	}
+	object := struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		CategoryID string `json:"category_id"`
+	}{}
+	err = r.rc.Do(
+		ctx,
+		r.rc.Resource(TagPath).WithID(id).Request(http.MethodGet),
+		&object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	category, err := r.category(ctx, object.CategoryID)
+	if err != nil {
+		return
+	}
+	tag = model.Tag{
+		ID:       object.ID,
+		Name:     object.Name,
+		Category: category,
+	}
+	r.tags[id] = tag
+
+	return
+}
+
+// Attach the tag to the object.
+func (r *Tagging) Attach(ctx context.Context, id string, ref types.ManagedObjectReference) error {
+	return r.associate(ctx, id, ref, "attach")
+}
+
+// Detach the tag from the object.
+func (r *Tagging) Detach(ctx context.Context, id string, ref types.ManagedObjectReference) error {
+	return r.associate(ctx, id, ref, "detach")
+}
+
+// Attach or detach the tag.
+func (r *Tagging) associate(ctx context.Context, id string, ref types.ManagedObjectReference, action string) (err error) {
+	request := struct {
+		ObjectID objectID `json:"object_id"`
+	}{
+		ObjectID: objectID{
+			ID:   ref.Value,
+			Type: ref.Type,
+		},
+	}
+	err = r.rc.Do(
+		ctx,
+		r.rc.Resource(AssociationPath).
+			WithID(id).
+			WithAction(action).
+			Request(http.MethodPost, request),
+		nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Get the category name.
+func (r *Tagging) category(ctx context.Context, id string) (name string, err error) {
+	name, cached := r.categories[id]
+	if cached {
+		return
+	}
+	object := struct {
+		Name string `json:"name"`
+	}{}
+	err = r.rc.Do(
+		ctx,
+		r.rc.Resource(CategoryPath).WithID(id).Request(http.MethodGet),
+		&object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	name = object.Name
+	r.categories[id] = name
+
+	return
+}
+
+// Find a category by name.
+// Returns the category ID.
+func (r *Tagging) findCategory(ctx context.Context, name string) (id string, found bool, err error) {
+	ids := []string{}
+	err = r.rc.Do(
+		ctx,
+		r.rc.Resource(CategoryPath).Request(http.MethodGet),
+		&ids)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, categoryID := range ids {
+		categoryName, cErr := r.category(ctx, categoryID)
+		if cErr != nil {
+			err = cErr
+			return
+		}
+		if categoryName == name {
+			id = categoryID
+			found = true
+			return
+		}
+	}
+
+	return
+}
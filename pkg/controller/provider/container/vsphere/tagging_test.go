@@ -0,0 +1,209 @@
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Fake vAPI tagging endpoint.
+type vapiServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	// Tags keyed by ID.
+	tags map[string]model.Tag
+	// Category names keyed by ID.
+	categories map[string]string
+	// Attached tag IDs keyed by object ID.
+	attached map[string][]string
+	// Request count keyed by path.
+	requests map[string]int
+}
+
+// Build and start the fake vAPI endpoint.
+func newVapiServer() (s *vapiServer) {
+	s = &vapiServer{
+		tags:       map[string]model.Tag{},
+		categories: map[string]string{},
+		attached:   map[string][]string{},
+		requests:   map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return
+}
+
+// vim25 client of the fake endpoint.
+func (s *vapiServer) client() *vim25.Client {
+	u, _ := liburl.Parse(s.URL + "/sdk")
+	return &vim25.Client{Client: soap.NewClient(u, true)}
+}
+
+func (s *vapiServer) serve(w http.ResponseWriter, req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests[req.URL.Path]++
+	reply := func(value interface{}) {
+		_ = json.NewEncoder(w).Encode(
+			struct {
+				Value interface{} `json:"value"`
+			}{
+				Value: value,
+			})
+	}
+	path := strings.TrimPrefix(req.URL.Path, rest.Path)
+	switch {
+	case path == "/com/vmware/cis/session":
+		if req.Method == http.MethodPost {
+			reply("session")
+		}
+	case path == AssociationPath:
+		request := struct {
+			ObjectIDs []objectID `json:"object_ids"`
+		}{}
+		_ = json.NewDecoder(req.Body).Decode(&request)
+		type attached struct {
+			ObjectID objectID `json:"object_id"`
+			TagIDs   []string `json:"tag_ids"`
+		}
+		list := []attached{}
+		for _, object := range request.ObjectIDs {
+			if ids, found := s.attached[object.ID]; found {
+				list = append(list, attached{ObjectID: object, TagIDs: ids})
+			}
+		}
+		reply(list)
+	case path == TagPath:
+		ids := []string{}
+		for id := range s.tags {
+			ids = append(ids, id)
+		}
+		reply(ids)
+	case path == CategoryPath:
+		ids := []string{}
+		for id := range s.categories {
+			ids = append(ids, id)
+		}
+		reply(ids)
+	case req.URL.Query().Get("~action") == "list-tags-for-category":
+		category := s.categories[strings.TrimPrefix(path, TagPath+"/id:")]
+		ids := []string{}
+		for id, tag := range s.tags {
+			if tag.Category == category {
+				ids = append(ids, id)
+			}
+		}
+		reply(ids)
+	case strings.HasPrefix(path, TagPath+"/id:"):
+		tag, found := s.tags[strings.TrimPrefix(path, TagPath+"/id:")]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for categoryID, name := range s.categories {
+			if name == tag.Category {
+				reply(map[string]string{
+					"id":          tag.ID,
+					"name":        tag.Name,
+					"category_id": categoryID,
+				})
+			}
+		}
+	case strings.HasPrefix(path, CategoryPath+"/id:"):
+		name, found := s.categories[strings.TrimPrefix(path, CategoryPath+"/id:")]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(map[string]string{"name": name})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestAttachedTags(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newVapiServer()
+	defer server.Close()
+	server.categories["c1"] = "env"
+	server.tags["t1"] = model.Tag{ID: "t1", Name: "prod", Category: "env"}
+	server.tags["t2"] = model.Tag{ID: "t2", Name: "db", Category: "env"}
+	server.attached["vm-1"] = []string{"t2", "t1"}
+	server.attached["vm-2"] = []string{"t1"}
+	tagging, err := NewTagging(context.TODO(), server.client(), "user", "password")
+	g.Expect(err).To(gomega.BeNil())
+	defer tagging.Close()
+
+	refs := []types.ManagedObjectReference{
+		{Type: VirtualMachine, Value: "vm-1"},
+		{Type: VirtualMachine, Value: "vm-2"},
+		{Type: VirtualMachine, Value: "vm-3"},
+	}
+	tags, err := tagging.AttachedTags(context.TODO(), refs)
+	g.Expect(err).To(gomega.BeNil())
+	// Sorted by ID.
+	g.Expect(tags).To(gomega.Equal(map[string][]model.Tag{
+		"vm-1": {server.tags["t1"], server.tags["t2"]},
+		"vm-2": {server.tags["t1"]},
+	}))
+	// Single association request, tags and categories fetched once.
+	g.Expect(server.requests[rest.Path+AssociationPath]).To(gomega.Equal(1))
+	g.Expect(server.requests[rest.Path+TagPath+"/id:t1"]).To(gomega.Equal(1))
+	g.Expect(server.requests[rest.Path+CategoryPath+"/id:c1"]).To(gomega.Equal(1))
+
+	// Cached.
+	_, err = tagging.AttachedTags(context.TODO(), refs)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(server.requests[rest.Path+AssociationPath]).To(gomega.Equal(2))
+	g.Expect(server.requests[rest.Path+TagPath+"/id:t1"]).To(gomega.Equal(1))
+
+	// No objects.
+	tags, err = tagging.AttachedTags(context.TODO(), nil)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(tags).To(gomega.BeEmpty())
+	g.Expect(server.requests[rest.Path+AssociationPath]).To(gomega.Equal(2))
+}
+
+func TestFindTag(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newVapiServer()
+	defer server.Close()
+	server.categories["c1"] = "env"
+	server.categories["c2"] = "tier"
+	server.tags["t1"] = model.Tag{ID: "t1", Name: "prod", Category: "env"}
+	server.tags["t2"] = model.Tag{ID: "t2", Name: "db", Category: "tier"}
+	server.tags["t3"] = model.Tag{ID: "t3", Name: "prod", Category: "tier"}
+	tagging, err := NewTagging(context.TODO(), server.client(), "user", "password")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer tagging.Close()
+
+	// Qualified by category.
+	id, err := tagging.FindTag(context.TODO(), "env:prod")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal("t1"))
+	id, err = tagging.FindTag(context.TODO(), "tier:prod")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal("t3"))
+
+	// Name only.
+	id, err = tagging.FindTag(context.TODO(), "db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(id).To(gomega.Equal("t2"))
+
+	// Not found.
+	_, err = tagging.FindTag(context.TODO(), "env:db")
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = tagging.FindTag(context.TODO(), "owner:db")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -238,6 +238,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 			"Waiting connection tested or inventory created.")
 		result.RequeueAfter = base.SlowReQ
 	}
+	if provider.Status.HasCondition(ValidationFallback) {
+		result.RequeueAfter = policy.RetryDelay
+	}
 
 	// Done
 	return
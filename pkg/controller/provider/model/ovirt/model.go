@@ -131,12 +131,14 @@ type VM struct {
 	Memory                      int64            `sql:""`
 	BalloonedMemory             bool             `sql:""`
 	BIOS                        string           `sql:""`
+	TpmEnabled                  bool             `sql:""`
 	Display                     string           `sql:""`
 	IOThreads                   int16            `sql:""`
 	StorageErrorResumeBehaviour string           `sql:""`
 	HaEnabled                   bool             `sql:""`
 	UsbEnabled                  bool             `sql:""`
 	BootMenuEnabled             bool             `sql:""`
+	BootDevices                 []string         `sql:""`
 	PlacementPolicyAffinity     string           `sql:""`
 	Timezone                    string           `sql:""`
 	Status                      string           `sql:""`
@@ -173,6 +175,7 @@ type DiskAttachment struct {
 	ID              string `json:"id"`
 	Interface       string `json:"interface"`
 	SCSIReservation bool   `json:"scsiReservation"`
+	Bootable        bool   `json:"bootable"`
 	Disk            string `json:"disk"`
 }
 
@@ -233,4 +236,16 @@ type Disk struct {
 	Backup          string `sql:""`
 	StorageType     string `sql:""`
 	ProvisionedSize int64  `sql:""`
+	Lun             Lun    `sql:""`
+}
+
+// Direct LUN disk storage type.
+const LunStorage = "lun"
+
+// Direct LUN.
+type Lun struct {
+	// Logical unit ID (WWID).
+	ID     string `json:"id"`
+	Serial string `json:"serial"`
+	Size   int64  `json:"size"`
 }
@@ -15,6 +15,22 @@ const (
 	ComputeResource = "ComputeResource"
 )
 
+// Disk (controller) buses.
+const (
+	BusSCSI = "scsi"
+	BusSATA = "sata"
+	BusIDE  = "ide"
+	BusNVME = "nvme"
+)
+
+// Boot device kinds.
+const (
+	BootDisk     = "disk"
+	BootCDROM    = "cdrom"
+	BootEthernet = "ethernet"
+	BootFloppy   = "floppy"
+)
+
 // Errors
 var NotFound = libmodel.NotFound
 
@@ -231,36 +247,45 @@ type Datastore struct {
 
 type VM struct {
 	Base
-	Folder                string    `sql:"d0,index(folder)"`
-	Host                  string    `sql:"d0,index(host)"`
-	RevisionValidated     int64     `sql:"d0,index(revisionValidated)"`
-	PolicyVersion         int       `sql:"d0,index(policyVersion)"`
-	UUID                  string    `sql:""`
-	Firmware              string    `sql:""`
-	PowerState            string    `sql:""`
-	ConnectionState       string    `sql:""`
-	CpuAffinity           []int32   `sql:""`
-	CpuHotAddEnabled      bool      `sql:""`
-	CpuHotRemoveEnabled   bool      `sql:""`
-	MemoryHotAddEnabled   bool      `sql:""`
-	FaultToleranceEnabled bool      `sql:""`
-	CpuCount              int32     `sql:""`
-	CoresPerSocket        int32     `sql:""`
-	MemoryMB              int32     `sql:""`
-	GuestName             string    `sql:""`
-	GuestID               string    `sql:""`
-	BalloonedMemory       int32     `sql:""`
-	IpAddress             string    `sql:""`
-	NumaNodeAffinity      []string  `sql:""`
-	StorageUsed           int64     `sql:""`
-	Snapshot              Ref       `sql:""`
-	IsTemplate            bool      `sql:""`
-	ChangeTrackingEnabled bool      `sql:""`
-	Devices               []Device  `sql:""`
-	NICs                  []NIC     `sql:""`
-	Disks                 []Disk    `sql:""`
-	Networks              []Ref     `sql:""`
-	Concerns              []Concern `sql:""`
+	Folder                string            `sql:"d0,index(folder)"`
+	Host                  string            `sql:"d0,index(host)"`
+	RevisionValidated     int64             `sql:"d0,index(revisionValidated)"`
+	PolicyVersion         int               `sql:"d0,index(policyVersion)"`
+	UUID                  string            `sql:""`
+	Firmware              string            `sql:""`
+	SecureBoot            bool              `sql:""`
+	TpmEnabled            bool              `sql:""`
+	ConfigFile            string            `sql:""`
+	NVRAM                 string            `sql:""`
+	PowerState            string            `sql:""`
+	ConnectionState       string            `sql:""`
+	CpuAffinity           []int32           `sql:""`
+	CpuHotAddEnabled      bool              `sql:""`
+	CpuHotRemoveEnabled   bool              `sql:""`
+	MemoryHotAddEnabled   bool              `sql:""`
+	FaultToleranceEnabled bool              `sql:""`
+	CpuCount              int32             `sql:""`
+	CoresPerSocket        int32             `sql:""`
+	MemoryMB              int32             `sql:""`
+	GuestName             string            `sql:""`
+	GuestID               string            `sql:""`
+	BalloonedMemory       int32             `sql:""`
+	IpAddress             string            `sql:""`
+	NumaNodeAffinity      []string          `sql:""`
+	StorageUsed           int64             `sql:""`
+	Snapshot              Ref               `sql:""`
+	IsTemplate            bool              `sql:""`
+	ChangeTrackingEnabled bool              `sql:""`
+	Devices               []Device          `sql:""`
+	NICs                  []NIC             `sql:""`
+	Disks                 []Disk            `sql:""`
+	CDROMs                []CDROM           `sql:""`
+	BootOrder             []BootDevice      `sql:""`
+	Networks              []Ref             `sql:""`
+	Concerns              []Concern         `sql:""`
+	Annotation            string            `sql:""`
+	CustomAttributes      []CustomAttribute `sql:""`
+	Tags                  []Tag             `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -276,6 +301,26 @@ type Disk struct {
 	Capacity  int64  `json:"capacity"`
 	Shared    bool   `json:"shared"`
 	RDM       bool   `json:"rdm"`
+	// RDM LUN UUID.
+	LUN string `json:"lun,omitempty"`
+	Bus string `json:"bus"`
+	// Encrypted by vSphere VM encryption.
+	VMEncrypted bool `json:"vmEncrypted,omitempty"`
+}
+
+// Virtual CD-ROM.
+type CDROM struct {
+	Key int32  `json:"key"`
+	Bus string `json:"bus"`
+	// ISO image file. Empty when not backed by an ISO.
+	ISO string `json:"iso,omitempty"`
+}
+
+// Bootable device.
+type BootDevice struct {
+	Kind string `json:"kind"`
+	// Device key (disk, ethernet).
+	Key int32 `json:"key,omitempty"`
 }
 
 // Virtual Device.
@@ -288,3 +333,17 @@ type NIC struct {
 	Network Ref    `json:"network"`
 	MAC     string `json:"mac"`
 }
+
+// Custom attribute (custom field value).
+type CustomAttribute struct {
+	Key   int32  `json:"key"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// vSphere (vAPI) tag.
+type Tag struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/ovirt"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	libref "github.com/konveyor/forklift-controller/pkg/lib/ref"
@@ -31,6 +32,7 @@ const (
 	InventoryCreated        = "InventoryCreated"
 	LoadInventory           = "LoadInventory"
 	ConnectionInsecure      = "ConnectionInsecure"
+	ValidationFallback      = "ValidationFallback"
 )
 
 // Categories
@@ -53,6 +55,7 @@ const (
 	Tested              = "Tested"
 	Started             = "Started"
 	SkipTLSVerification = "SkipTLSVerification"
+	Unreachable         = "Unreachable"
 )
 
 // Phases
@@ -95,6 +98,7 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.validatePolicyAgent(provider)
 	if !provider.Status.HasBlockerCondition() {
 		provider.Status.SetCondition(
 			libcnd.Condition{
@@ -109,6 +113,23 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	return nil
 }
 
+// Validate the policy agent is reachable.
+// While unreachable, the VMs are validated by the
+// embedded engine and custom policies are not evaluated.
+func (r *Reconciler) validatePolicyAgent(provider *api.Provider) {
+	if !policy.Agent.Fallback() {
+		return
+	}
+	provider.Status.SetCondition(
+		libcnd.Condition{
+			Type:     ValidationFallback,
+			Status:   True,
+			Reason:   Unreachable,
+			Category: Warn,
+			Message:  "The validation service is unreachable. VMs are validated by the embedded engine without custom policies and are revalidated with the custom policies when the service is reachable.",
+		})
+}
+
 // Validate types.
 func (r *Reconciler) validateType(provider *api.Provider) error {
 	for _, p := range api.ProviderTypes {
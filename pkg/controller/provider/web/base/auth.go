@@ -27,6 +27,8 @@ var DefaultAuth = Auth{
 
 // Authorized by k8s bearer token SAR.
 // Token must have "*" on the provider CR.
+// Access may be further restricted by the
+// inventory scopes defined on the provider.
 type Auth struct {
 	// k8s API writer.
 	Writer client.Writer
@@ -35,17 +37,33 @@ type Auth struct {
 	// Mutex.
 	mutex sync.Mutex
 	// Token cache.
-	cache map[string]time.Time
+	cache map[string]Grant
+}
+
+// Cached grant.
+type Grant struct {
+	// Granted.
+	Time time.Time
+	// Inventory scope.
+	// Nil is unrestricted.
+	Scope *api.ProviderScope
 }
 
 // Authenticate token.
 func (r *Auth) Permit(ctx *gin.Context, p *api.Provider) (status int, err error) {
+	_, status, err = r.Scope(ctx, p)
+	return
+}
+
+// Authenticate token and resolve the inventory scope.
+// A nil scope is unrestricted.
+func (r *Auth) Scope(ctx *gin.Context, p *api.Provider) (scope *api.ProviderScope, status int, err error) {
 	r.mutex.Lock()
 	ns := ""
 	defer r.mutex.Unlock()
 	status = http.StatusOK
 	if r.cache == nil {
-		r.cache = make(map[string]time.Time)
+		r.cache = make(map[string]Grant)
 	}
 	r.prune()
 	token := r.token(ctx)
@@ -54,8 +72,9 @@ func (r *Auth) Permit(ctx *gin.Context, p *api.Provider) (status int, err error)
 		return
 	}
 	key := r.key(token, p)
-	if t, found := r.cache[key]; found {
-		if time.Since(t) <= r.TTL {
+	if grant, found := r.cache[key]; found {
+		if time.Since(grant.Time) <= r.TTL {
+			scope = grant.Scope
 			return
 		}
 	}
@@ -63,14 +82,17 @@ func (r *Auth) Permit(ctx *gin.Context, p *api.Provider) (status int, err error)
 		q := ctx.Request.URL.Query()
 		ns = q.Get(NsParam)
 	}
-	allowed, err := r.permit(token, ns, p)
+	allowed, scope, err := r.permit(token, ns, p)
 	if allowed && err != nil {
 		log.Error(err, "Authorization failed.")
 		status = http.StatusInternalServerError
 		return
 	}
 	if allowed {
-		r.cache[key] = time.Now()
+		r.cache[key] = Grant{
+			Time:  time.Now(),
+			Scope: scope,
+		}
 	} else {
 		status = http.StatusForbidden
 		delete(r.cache, token)
@@ -84,7 +106,7 @@ func (r *Auth) Permit(ctx *gin.Context, p *api.Provider) (status int, err error)
 }
 
 // Authenticate token.
-func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, err error) {
+func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, scope *api.ProviderScope, err error) {
 	allowed = true
 	tr := &auth.TokenReview{
 		Spec: auth.TokenReviewSpec{
@@ -105,12 +127,6 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, e
 		return
 	}
 	user := tr.Status.User
-	extra := map[string]auth2.ExtraValue{}
-	for k, v := range user.Extra {
-		extra[k] = append(
-			auth2.ExtraValue{},
-			v...)
-	}
 	// Users should be able to query information on providers from the inventory
 	// only if they have permissions for list/get 'providers' in the K8s API
 	group, resource, err := api.GetGroupResource(p)
@@ -126,35 +142,68 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, e
 		verb = "list"
 		namespace = ns
 	}
-	review := &auth2.SubjectAccessReview{
-		Spec: auth2.SubjectAccessReviewSpec{
-			ResourceAttributes: &auth2.ResourceAttributes{
-				Group:     group,
-				Resource:  resource,
-				Namespace: namespace,
-				Name:      p.Name,
-				Verb:      verb,
-			},
-			Extra:  extra,
-			Groups: user.Groups,
-			User:   user.Username,
-			UID:    user.UID,
-		},
+	attributes := &auth2.ResourceAttributes{
+		Group:     group,
+		Resource:  resource,
+		Namespace: namespace,
+		Name:      p.Name,
+		Verb:      verb,
 	}
-	err = w.Create(context.TODO(), review)
+	allowed, err = r.review(w, &user, attributes)
 	if err != nil {
-		err = liberr.Wrap(err)
 		return
 	}
-
-	if allowed = review.Status.Allowed; !allowed {
+	if !allowed {
 		groupResource := &schema.GroupResource{
 			Resource: resource,
 			Group:    group,
 		}
 		err = fmt.Errorf("%s is forbidden: User %q cannot %s resource %q in API group %q in the namespace %q",
 			groupResource, user.Username, verb, resource, group, namespace)
+		return
+	}
+	if p.ObjectMeta.UID == "" || len(p.Spec.Scopes) == 0 {
+		return
+	}
+	// Users permitted to update the provider
+	// are not restricted by inventory scopes.
+	attributes.Verb = "update"
+	unrestricted, err := r.review(w, &user, attributes)
+	if err != nil || unrestricted {
+		return
+	}
+	scope = p.Spec.Scope(user.Username, user.Groups)
+	if scope == nil {
+		allowed = false
+		err = fmt.Errorf("User %q is not within an inventory scope of provider %q in the namespace %q",
+			user.Username, p.Name, p.Namespace)
+	}
+	return
+}
+
+// Subject access review.
+func (r *Auth) review(w client.Writer, user *auth.UserInfo, attributes *auth2.ResourceAttributes) (allowed bool, err error) {
+	extra := map[string]auth2.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			auth2.ExtraValue{},
+			v...)
+	}
+	review := &auth2.SubjectAccessReview{
+		Spec: auth2.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes.DeepCopy(),
+			Extra:              extra,
+			Groups:             user.Groups,
+			User:               user.Username,
+			UID:                user.UID,
+		},
+	}
+	err = w.Create(context.TODO(), review)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
+	allowed = review.Status.Allowed
 	return
 }
 
@@ -172,8 +221,8 @@ func (r *Auth) token(ctx *gin.Context) (token string) {
 // Prune the cache.
 // Evacuate expired tokens.
 func (r *Auth) prune() {
-	for token, t := range r.cache {
-		if time.Since(t) > r.TTL {
+	for token, grant := range r.cache {
+		if time.Since(grant.Time) > r.TTL {
 			delete(r.cache, token)
 		}
 	}
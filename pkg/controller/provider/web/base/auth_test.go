@@ -18,6 +18,8 @@ import (
 
 type fakeWriter struct {
 	allowed bool
+	user    string
+	denied  map[string]bool
 	trCount int
 	arCount int
 }
@@ -29,11 +31,12 @@ func (r *fakeWriter) Create(
 	//
 	if tr, cast := object.(*auth.TokenReview); cast {
 		tr.Status.Authenticated = r.allowed
+		tr.Status.User.Username = r.user
 		r.trCount++
 		return
 	}
 	if ar, cast := object.(*auth2.SubjectAccessReview); cast {
-		ar.Status.Allowed = r.allowed
+		ar.Status.Allowed = r.allowed && !r.denied[ar.Spec.ResourceAttributes.Verb]
 		r.arCount++
 		return
 	}
@@ -125,3 +128,67 @@ func TestAuth(t *testing.T) {
 	auth.prune()
 	g.Expect(0).To(gomega.Equal(len(auth.cache)))
 }
+
+func TestAuthScope(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	writer := &fakeWriter{
+		allowed: true,
+		user:    "tenant",
+		denied:  map[string]bool{"update": true},
+	}
+	auth := Auth{
+		Writer: writer,
+		TTL:    time.Minute,
+	}
+	ctx := &gin.Context{
+		Request: &http.Request{
+			Header: map[string][]string{
+				"Authorization": {"Bearer 12345"},
+			},
+			URL: &url.URL{},
+		},
+	}
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor-forklift",
+			Name:      "test",
+			UID:       "1",
+		},
+		Spec: api.ProviderSpec{
+			Scopes: []api.ProviderScope{
+				{
+					Users:   []string{"tenant"},
+					Folders: []string{"/dc/vm/tenant"},
+				},
+				{
+					Users:    []string{"other"},
+					Clusters: []string{"other"},
+				},
+			},
+		},
+	}
+	// Restricted to the matched scope.
+	scope, status, _ := auth.Scope(ctx, provider)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(scope).ToNot(gomega.BeNil())
+	g.Expect(scope.HasFolder("/dc/vm/tenant/vm1")).To(gomega.BeTrue())
+	g.Expect(scope.HasFolder("/dc/vm/tenant2/vm1")).To(gomega.BeFalse())
+	g.Expect(scope.HasCluster("", "other")).To(gomega.BeFalse())
+	g.Expect(writer.arCount).To(gomega.Equal(2))
+	// Cached.
+	scope, status, _ = auth.Scope(ctx, provider)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(scope).ToNot(gomega.BeNil())
+	g.Expect(writer.arCount).To(gomega.Equal(2))
+	// Not matched.
+	auth.cache = nil
+	writer.user = "nobody"
+	_, status, _ = auth.Scope(ctx, provider)
+	g.Expect(status).To(gomega.Equal(http.StatusForbidden))
+	// Permitted to update the provider.
+	auth.cache = nil
+	writer.denied = nil
+	scope, status, _ = auth.Scope(ctx, provider)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(scope).To(gomega.BeNil())
+}
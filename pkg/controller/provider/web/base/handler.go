@@ -61,6 +61,9 @@ type Handler struct {
 	Collector libcontainer.Collector
 	// Resources detail level.
 	Detail int
+	// Inventory scope of the user.
+	// Nil is unrestricted.
+	Scope *api.ProviderScope
 }
 
 // Prepare to handle the request.
@@ -145,8 +148,9 @@ func (h *Handler) setDetail(ctx *gin.Context) (status int) {
 // Permit request - Authorization.
 func (h *Handler) permit(ctx *gin.Context) (status int, err error) {
 	status = http.StatusOK
+	h.Scope = nil
 	if Settings.AuthRequired {
-		return DefaultAuth.Permit(ctx, h.Provider)
+		h.Scope, status, err = DefaultAuth.Scope(ctx, h.Provider)
 	}
 
 	return
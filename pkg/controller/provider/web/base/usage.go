@@ -0,0 +1,44 @@
+package base
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+)
+
+// Source networks and storage used by a set of VMs.
+type Usage struct {
+	// Networks.
+	Networks []UsedNetwork `json:"networks"`
+	// Storage (datastores, storage domains, volume types).
+	Storage []ref.Ref `json:"storage"`
+	// VMs not found (or not permitted).
+	NotFound []string `json:"notFound,omitempty"`
+}
+
+// Used network.
+type UsedNetwork struct {
+	ref.Ref `json:",inline"`
+	// VLAN ID (tag). Empty when untagged or not known.
+	Vlan string `json:"vlan,omitempty"`
+}
+
+// Add a network.
+// Networks are reported once.
+func (r *Usage) AddNetwork(network UsedNetwork) {
+	for _, n := range r.Networks {
+		if n.ID == network.ID {
+			return
+		}
+	}
+	r.Networks = append(r.Networks, network)
+}
+
+// Add storage.
+// Storage is reported once.
+func (r *Usage) AddStorage(storage ref.Ref) {
+	for _, s := range r.Storage {
+		if s.ID == storage.ID {
+			return
+		}
+	}
+	r.Storage = append(r.Storage, storage)
+}
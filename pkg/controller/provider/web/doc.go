@@ -19,6 +19,16 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 				Container: container,
 			},
 		},
+		&ThroughputHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
+		&MappingHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
 	}
 	all = append(
 		all,
@@ -0,0 +1,498 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	MappingPath = "/mappings"
+)
+
+// Params.
+const (
+	VMParam          = "vm"
+	DestinationParam = "destination"
+	NamespaceParam   = "namespace"
+)
+
+// Namespace of the NADs shared by the cluster.
+const DefaultNamespace = "default"
+
+// Label identifying maps containing default (user) mappings.
+const DefaultMapLabel = "forklift.konveyor.io/defaults"
+
+// Storage class annotations.
+const (
+	DefaultClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	DefaultVirtClassAnnotation = "storageclass.kubevirt.io/is-default-virt-class"
+)
+
+// Mapping (suggestion) handler.
+// Proposes network and storage maps for a set of VMs using the
+// networks and storage the VMs use. The source is matched with:
+//   - default mappings (maps labeled forklift.konveyor.io/defaults).
+//   - NADs by VLAN then by name.
+//   - storage classes by name then the default storage class.
+type MappingHandler struct {
+	base.Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MappingHandler) AddRoutes(e *gin.Engine) {
+	e.GET(vsphere.ProviderRoot+MappingPath, h.Get)
+	e.GET(ovirt.ProviderRoot+MappingPath, h.Get)
+	e.GET(openstack.ProviderRoot+MappingPath, h.Get)
+}
+
+// Get the suggested maps.
+// Params:
+//
+//	vm: VM ID (repeated).
+//	destination: destination (openshift) provider UID.
+//	namespace: namespace containing the NADs and the default
+//	  maps. Default: any NAD and the maps in the provider namespace.
+func (h MappingHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.Provider.UID == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	q := ctx.Request.URL.Query()
+	ids := q[VMParam]
+	if len(ids) == 0 {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	destination, found := h.Container.Get(
+		&api.Provider{
+			ObjectMeta: meta.ObjectMeta{
+				UID: types.UID(q.Get(DestinationParam)),
+			},
+		})
+	if !found {
+		ctx.Header(base.ReasonHeader, base.UnknownProvider)
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	pair := provider.Pair{
+		Source: core.ObjectReference{
+			Namespace: h.Provider.Namespace,
+			Name:      h.Provider.Name,
+		},
+	}
+	if p, cast := destination.Owner().(*api.Provider); cast {
+		if base.Settings.AuthRequired {
+			status, err = base.DefaultAuth.Permit(ctx, p)
+			if status != http.StatusOK {
+				ctx.Status(status)
+				base.SetForkliftError(ctx, err)
+				return
+			}
+		}
+		pair.Destination = core.ObjectReference{
+			Namespace: p.Namespace,
+			Name:      p.Name,
+		}
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+		}
+	}()
+	db := h.Collector.DB()
+	var usage base.Usage
+	switch h.Provider.Type() {
+	case api.VSphere:
+		usage, err = vsphere.Handler{Handler: h.Handler}.Usage(db, ids)
+	case api.OVirt:
+		usage, err = ovirt.Handler{Handler: h.Handler}.Usage(db, ids)
+	case api.OpenStack:
+		usage, err = openstack.Handler{Handler: h.Handler}.Usage(db, ids)
+	default:
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		return
+	}
+	// NADs in the namespace, by default in the
+	// plan (provider) namespace and `default`.
+	namespace := q.Get(NamespaceParam)
+	matcher := &Matcher{
+		Namespaces: []string{namespace},
+	}
+	if namespace == "" {
+		namespace = h.Provider.Namespace
+		matcher.Namespaces = []string{namespace, DefaultNamespace}
+	}
+	ddb := destination.DB()
+	err = ddb.List(&matcher.Nads, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	err = ddb.List(&matcher.StorageClasses, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	cl, err := apiClient()
+	if err != nil {
+		return
+	}
+	err = matcher.loadDefaults(cl, namespace, pair)
+	if err != nil {
+		return
+	}
+	suggestion := matcher.Suggest(usage)
+	suggestion.Network.Provider = pair
+	suggestion.Storage.Provider = pair
+
+	ctx.JSON(http.StatusOK, suggestion)
+}
+
+// Suggested maps.
+type MappingSuggestion struct {
+	// Network map.
+	Network api.NetworkMapSpec `json:"network"`
+	// Storage map.
+	Storage api.StorageMapSpec `json:"storage"`
+	// Coverage gaps.
+	Gaps struct {
+		// Source networks not mapped.
+		Networks []ref.Ref `json:"networks"`
+		// Source storage not mapped.
+		Storage []ref.Ref `json:"storage"`
+		// VMs not found.
+		VMs []string `json:"vms"`
+	} `json:"gaps"`
+}
+
+// Source matcher.
+type Matcher struct {
+	// Namespaces containing the NADs in order of
+	// preference. Empty is any.
+	Namespaces []string
+	// Destination NADs.
+	Nads []model.NetworkAttachmentDefinition
+	// Destination storage classes.
+	StorageClasses []model.StorageClass
+	// Default network mappings.
+	Networks []api.NetworkPair
+	// Default storage mappings.
+	Storage []api.StoragePair
+}
+
+// Build the suggestion.
+// Only matched sources are mapped so that the maps are valid.
+// At most one source network is mapped to the pod network.
+func (r *Matcher) Suggest(usage base.Usage) (suggestion MappingSuggestion) {
+	suggestion.Network.Map = []api.NetworkPair{}
+	suggestion.Storage.Map = []api.StoragePair{}
+	suggestion.Gaps.Networks = []ref.Ref{}
+	suggestion.Gaps.Storage = []ref.Ref{}
+	suggestion.Gaps.VMs = usage.NotFound
+	if suggestion.Gaps.VMs == nil {
+		suggestion.Gaps.VMs = []string{}
+	}
+	podMapped := false
+	for _, network := range usage.Networks {
+		destination, matched := r.MatchNetwork(network)
+		if matched && destination.Type == api.PodNetwork {
+			if podMapped {
+				matched = false
+			}
+			podMapped = true
+		}
+		if !matched {
+			suggestion.Gaps.Networks = append(suggestion.Gaps.Networks, network.Ref)
+			continue
+		}
+		suggestion.Network.Map = append(
+			suggestion.Network.Map,
+			api.NetworkPair{
+				Source:      ref.Ref{ID: network.ID},
+				Destination: destination,
+			})
+	}
+	for _, storage := range usage.Storage {
+		destination, matched := r.MatchStorage(storage)
+		if !matched {
+			suggestion.Gaps.Storage = append(suggestion.Gaps.Storage, storage)
+			continue
+		}
+		suggestion.Storage.Map = append(
+			suggestion.Storage.Map,
+			api.StoragePair{
+				Source:      ref.Ref{ID: storage.ID},
+				Destination: destination,
+			})
+	}
+
+	return
+}
+
+// Match a source network.
+func (r *Matcher) MatchNetwork(network base.UsedNetwork) (destination api.DestinationNetwork, matched bool) {
+	for _, pair := range r.Networks {
+		if r.matchRef(pair.Source, network.Ref) {
+			destination = pair.Destination
+			matched = true
+			return
+		}
+	}
+	nads := r.nads()
+	vlan, err := strconv.Atoi(network.Vlan)
+	if err == nil && vlan > 0 {
+		for i := range nads {
+			nad := &nads[i]
+			if nadVlan(nad) == vlan {
+				destination = nadDestination(nad)
+				matched = true
+				return
+			}
+		}
+	}
+	name := k8sName(network.Name)
+	for i := range nads {
+		nad := &nads[i]
+		if nad.Name == name {
+			destination = nadDestination(nad)
+			matched = true
+			return
+		}
+	}
+
+	return
+}
+
+// Match source storage.
+func (r *Matcher) MatchStorage(storage ref.Ref) (destination api.DestinationStorage, matched bool) {
+	for _, pair := range r.Storage {
+		if r.matchRef(pair.Source, storage) {
+			destination = pair.Destination
+			matched = true
+			return
+		}
+	}
+	name := k8sName(storage.Name)
+	for _, sc := range r.StorageClasses {
+		if sc.Name == name {
+			destination.StorageClass = sc.Name
+			matched = true
+			return
+		}
+	}
+	for _, annotation := range []string{DefaultVirtClassAnnotation, DefaultClassAnnotation} {
+		for _, sc := range r.StorageClasses {
+			if sc.Object.Annotations[annotation] == "true" {
+				destination.StorageClass = sc.Name
+				matched = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// Load the default mappings.
+// Entries in maps (in the namespace) labeled as defaults
+// for the provider pair.
+func (r *Matcher) loadDefaults(cl client.Reader, namespace string, pair provider.Pair) (err error) {
+	networkMaps, storageMaps, err := defaultMaps.List(cl, namespace)
+	if err != nil {
+		return
+	}
+	for _, mp := range networkMaps {
+		if mp.Spec.Provider == pair {
+			r.Networks = append(r.Networks, mp.Spec.Map...)
+		}
+	}
+	for _, mp := range storageMaps {
+		if mp.Spec.Provider == pair {
+			r.Storage = append(r.Storage, mp.Spec.Map...)
+		}
+	}
+
+	return
+}
+
+// Default maps cache.
+var defaultMaps = DefaultMaps{
+	TTL: time.Second * 10,
+}
+
+// Cached default maps.
+// The labeled maps in a namespace are listed
+// at most once per TTL.
+type DefaultMaps struct {
+	// Cached list TTL.
+	TTL time.Duration
+	// Mutex.
+	mutex sync.Mutex
+	// Cached lists keyed by namespace.
+	cache map[string]defaultMapList
+}
+
+// Cached default maps in a namespace.
+type defaultMapList struct {
+	// Listed.
+	time time.Time
+	// Network maps.
+	network []api.NetworkMap
+	// Storage maps.
+	storage []api.StorageMap
+}
+
+// List the default maps in the namespace.
+func (r *DefaultMaps) List(cl client.Reader, namespace string) (network []api.NetworkMap, storage []api.StorageMap, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.cache == nil {
+		r.cache = make(map[string]defaultMapList)
+	}
+	r.prune()
+	if cached, found := r.cache[namespace]; found {
+		network = cached.network
+		storage = cached.storage
+		return
+	}
+	options := []client.ListOption{
+		client.InNamespace(namespace),
+		client.HasLabels{DefaultMapLabel},
+	}
+	networkMaps := &api.NetworkMapList{}
+	err = cl.List(context.TODO(), networkMaps, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	storageMaps := &api.StorageMapList{}
+	err = cl.List(context.TODO(), storageMaps, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	network = networkMaps.Items
+	storage = storageMaps.Items
+	r.cache[namespace] = defaultMapList{
+		time:    time.Now(),
+		network: network,
+		storage: storage,
+	}
+
+	return
+}
+
+// Prune the cache.
+// Evacuate expired lists.
+func (r *DefaultMaps) prune() {
+	for namespace, cached := range r.cache {
+		if time.Since(cached.time) > r.TTL {
+			delete(r.cache, namespace)
+		}
+	}
+}
+
+// The (default mapping) ref matches the source.
+func (r *Matcher) matchRef(mapped ref.Ref, source ref.Ref) bool {
+	if mapped.ID != "" {
+		return mapped.ID == source.ID
+	}
+	return mapped.Name != "" && mapped.Name == source.Name
+}
+
+// Candidate NADs sorted by namespace (preference) and name.
+func (r *Matcher) nads() (nads []model.NetworkAttachmentDefinition) {
+	rank := map[string]int{}
+	for i, namespace := range r.Namespaces {
+		if _, found := rank[namespace]; !found {
+			rank[namespace] = i
+		}
+	}
+	for _, nad := range r.Nads {
+		if _, found := rank[nad.Namespace]; found || len(rank) == 0 {
+			nads = append(nads, nad)
+		}
+	}
+	sort.Slice(nads, func(i, j int) bool {
+		if nads[i].Namespace != nads[j].Namespace {
+			if rank[nads[i].Namespace] != rank[nads[j].Namespace] {
+				return rank[nads[i].Namespace] < rank[nads[j].Namespace]
+			}
+			return nads[i].Namespace < nads[j].Namespace
+		}
+		return nads[i].Name < nads[j].Name
+	})
+	return
+}
+
+// NAD VLAN ID. Zero when not tagged.
+func nadVlan(nad *model.NetworkAttachmentDefinition) int {
+	for _, plugin := range api.NadPlugins(nad.Object.Spec.Config) {
+		if plugin.Vlan > 0 {
+			return plugin.Vlan
+		}
+		if plugin.VlanID > 0 {
+			return plugin.VlanID
+		}
+	}
+	return 0
+}
+
+// Destination network for the NAD.
+// The network type is determined by the NAD plugin.
+func nadDestination(nad *model.NetworkAttachmentDefinition) (destination api.DestinationNetwork) {
+	destination = api.DestinationNetwork{
+		Type:      api.MultusNetwork,
+		Namespace: nad.Namespace,
+		Name:      nad.Name,
+	}
+	for _, plugin := range api.NadPlugins(nad.Object.Spec.Config) {
+		if kind := plugin.NetworkType(); kind != api.MultusNetwork {
+			destination.Type = kind
+		}
+	}
+	return
+}
+
+// Invalid k8s name characters.
+var notK8sName = regexp.MustCompile("[^a-z0-9-]+")
+
+// Source name as a k8s (DNS-1123) name.
+func k8sName(name string) string {
+	name = strings.ToLower(name)
+	name = notK8sName.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
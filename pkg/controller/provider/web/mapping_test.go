@@ -0,0 +1,155 @@
+package web
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatcher(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	nad := func(ns, name, config string) (m model.NetworkAttachmentDefinition) {
+		m.Namespace = ns
+		m.Name = name
+		m.Object = net.NetworkAttachmentDefinition{
+			Spec: net.NetworkAttachmentDefinitionSpec{Config: config},
+		}
+		return
+	}
+	sc := model.StorageClass{}
+	sc.Name = "standard"
+	sc.Object = storage.StorageClass{
+		ObjectMeta: meta.ObjectMeta{
+			Annotations: map[string]string{DefaultClassAnnotation: "true"},
+		},
+	}
+	fast := model.StorageClass{}
+	fast.Name = "fast"
+	matcher := &Matcher{
+		Namespaces: []string{"ns", DefaultNamespace},
+		Nads: []model.NetworkAttachmentDefinition{
+			nad(DefaultNamespace, "vm-network", `{"type":"cnv-bridge"}`),
+			nad(DefaultNamespace, "storage-net", `{"cniVersion":"0.3.1","plugins":[{"type":"sriov"}]}`),
+			nad("ns", "br-10", `{"type":"cnv-bridge","vlan":10}`),
+			nad("ns", "vm-network", `{"type":"ovn-k8s-cni-overlay","topology":"layer2"}`),
+			nad("other", "lab", `{"type":"cnv-bridge"}`),
+		},
+		StorageClasses: []model.StorageClass{sc, fast},
+		Networks: []api.NetworkPair{
+			{
+				Source:      ref.Ref{Name: "Management"},
+				Destination: api.DestinationNetwork{Type: api.PodNetwork},
+			},
+		},
+	}
+	suggestion := matcher.Suggest(
+		base.Usage{
+			Networks: []base.UsedNetwork{
+				{Ref: ref.Ref{ID: "n1", Name: "VLAN 10"}, Vlan: "10"},
+				{Ref: ref.Ref{ID: "n2", Name: "VM Network"}},
+				{Ref: ref.Ref{ID: "n3", Name: "Management"}},
+				{Ref: ref.Ref{ID: "n4", Name: "lab"}},
+				{Ref: ref.Ref{ID: "n5", Name: "Storage Net"}},
+			},
+			Storage: []ref.Ref{
+				{ID: "d1", Name: "FAST"},
+				{ID: "d2", Name: "datastore1"},
+			},
+			NotFound: []string{"vm-9"},
+		})
+	g.Expect(suggestion.Network.Map).To(gomega.Equal([]api.NetworkPair{
+		{
+			Source:      ref.Ref{ID: "n1"},
+			Destination: api.DestinationNetwork{Type: api.MultusNetwork, Namespace: "ns", Name: "br-10"},
+		},
+		{
+			Source:      ref.Ref{ID: "n2"},
+			Destination: api.DestinationNetwork{Type: api.OvnNetwork, Namespace: "ns", Name: "vm-network"},
+		},
+		{
+			Source:      ref.Ref{ID: "n3"},
+			Destination: api.DestinationNetwork{Type: api.PodNetwork},
+		},
+		{
+			Source:      ref.Ref{ID: "n5"},
+			Destination: api.DestinationNetwork{Type: api.SriovNetwork, Namespace: DefaultNamespace, Name: "storage-net"},
+		},
+	}))
+	g.Expect(suggestion.Gaps.Networks).To(gomega.Equal([]ref.Ref{{ID: "n4", Name: "lab"}}))
+	g.Expect(suggestion.Storage.Map).To(gomega.Equal([]api.StoragePair{
+		{
+			Source:      ref.Ref{ID: "d1"},
+			Destination: api.DestinationStorage{StorageClass: "fast"},
+		},
+		{
+			Source:      ref.Ref{ID: "d2"},
+			Destination: api.DestinationStorage{StorageClass: "standard"},
+		},
+	}))
+	g.Expect(suggestion.Gaps.Storage).To(gomega.BeEmpty())
+	g.Expect(suggestion.Gaps.VMs).To(gomega.Equal([]string{"vm-9"}))
+}
+
+func TestLoadDefaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	s := runtime.NewScheme()
+	g.Expect(apis.AddToScheme(s)).To(gomega.Succeed())
+	pair := provider.Pair{
+		Source:      core.ObjectReference{Namespace: "ns", Name: "vmware"},
+		Destination: core.ObjectReference{Namespace: "ns", Name: "host"},
+	}
+	networkMap := func(namespace, name string, labeled bool) *api.NetworkMap {
+		mp := &api.NetworkMap{}
+		mp.Namespace = namespace
+		mp.Name = name
+		if labeled {
+			mp.Labels = map[string]string{DefaultMapLabel: "true"}
+		}
+		mp.Spec.Provider = pair
+		mp.Spec.Map = []api.NetworkPair{
+			{
+				Source:      ref.Ref{Name: name},
+				Destination: api.DestinationNetwork{Type: api.PodNetwork},
+			},
+		}
+		return mp
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(
+			networkMap("ns", "defaults", true),
+			networkMap("ns", "unlabeled", false),
+			networkMap("other", "elsewhere", true)).
+		Build()
+	matcher := &Matcher{}
+	g.Expect(matcher.loadDefaults(cl, "ns", pair)).To(gomega.Succeed())
+	g.Expect(matcher.Networks).To(gomega.HaveLen(1))
+	g.Expect(matcher.Networks[0].Source.Name).To(gomega.Equal("defaults"))
+	// Cached.
+	g.Expect(cl.Create(context.TODO(), networkMap("ns", "added", true))).To(gomega.Succeed())
+	matcher = &Matcher{}
+	g.Expect(matcher.loadDefaults(cl, "ns", pair)).To(gomega.Succeed())
+	g.Expect(matcher.Networks).To(gomega.HaveLen(1))
+	// Expired.
+	defaultMaps.TTL = 0
+	defer func() {
+		defaultMaps.TTL = time.Second * 10
+	}()
+	matcher = &Matcher{}
+	g.Expect(matcher.loadDefaults(cl, "ns", pair)).To(gomega.Succeed())
+	g.Expect(matcher.Networks).To(gomega.HaveLen(2))
+}
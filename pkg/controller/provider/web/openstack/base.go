@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
@@ -51,6 +52,65 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 	}
 }
 
+// Inventory scope filter.
+// Projects are loaded once per filter.
+type ScopeFilter struct {
+	// Scope. Nil is unrestricted.
+	Scope *api.ProviderScope
+	// Database.
+	DB libmodel.DB
+	// Cached project permitted by ID.
+	projects map[string]bool
+}
+
+// Determine whether the VM is within the scope.
+// Contained in a listed project.
+func (r *ScopeFilter) Permitted(m *model.VM) bool {
+	if r.Scope == nil {
+		return true
+	}
+	if r.projects == nil {
+		r.projects = map[string]bool{}
+	}
+	permitted, cached := r.projects[m.TenantID]
+	if !cached {
+		project := &model.Project{
+			Base: model.Base{ID: m.TenantID},
+		}
+		err := r.DB.Get(project)
+		if err != nil {
+			return false
+		}
+		permitted = r.Scope.HasProject(project.ID, project.Name)
+		r.projects[m.TenantID] = permitted
+	}
+
+	return permitted
+}
+
+// Filter the VM list.
+func (r *ScopeFilter) Filter(list *[]model.VM) {
+	if r.Scope == nil {
+		return
+	}
+	kept := []model.VM{}
+	for i := range *list {
+		m := &(*list)[i]
+		if r.Permitted(m) {
+			kept = append(kept, *m)
+		}
+	}
+	*list = kept
+}
+
+// Build the scope filter.
+func (h Handler) ScopeFilter(db libmodel.DB) *ScopeFilter {
+	return &ScopeFilter{
+		Scope: h.Scope,
+		DB:    db,
+	}
+}
+
 // Path builder.
 type PathBuilder struct {
 	// Database.
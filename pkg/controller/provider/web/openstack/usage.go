@@ -0,0 +1,53 @@
+package openstack
+
+import (
+	"errors"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+)
+
+// Networks and volume types used by the VMs.
+func (h Handler) Usage(db libmodel.DB, ids []string) (usage base.Usage, err error) {
+	filter := h.ScopeFilter(db)
+	for _, id := range ids {
+		m := &model.VM{
+			Base: model.Base{ID: id},
+		}
+		err = db.Get(m)
+		if errors.Is(err, model.NotFound) || (err == nil && !filter.Permitted(m)) {
+			err = nil
+			usage.NotFound = append(usage.NotFound, id)
+			continue
+		}
+		if err != nil {
+			return
+		}
+		vm := XVM{}
+		vm.VM.With(m)
+		err = vm.Expand(db)
+		if err != nil {
+			return
+		}
+		for _, network := range vm.Networks {
+			usage.AddNetwork(
+				base.UsedNetwork{
+					Ref: ref.Ref{
+						ID:   network.ID,
+						Name: network.Name,
+					},
+				})
+		}
+		for _, volumeType := range vm.VolumeTypes {
+			usage.AddStorage(
+				ref.Ref{
+					ID:   volumeType.ID,
+					Name: volumeType.Name,
+				})
+		}
+	}
+
+	return
+}
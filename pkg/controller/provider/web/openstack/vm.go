@@ -61,7 +61,13 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	scope := h.ScopeFilter(db)
+	options := h.ListOptions(ctx)
+	if scope.Scope != nil {
+		// Paged after the scope is applied.
+		options.Page = nil
+	}
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -70,6 +76,10 @@ func (h VMHandler) List(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	if scope.Scope != nil {
+		scope.Filter(&list)
+		h.Page.Slice(&list)
+	}
 	pb := PathBuilder{DB: db}
 	for _, m := range list {
 		r := &VM{}
@@ -112,6 +122,10 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	if !h.ScopeFilter(db).Permitted(m) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
 	pb := PathBuilder{DB: db}
 	r := &VM{}
 	r.With(m)
@@ -132,6 +146,9 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 		func(in libmodel.Model) (r interface{}) {
 			pb := PathBuilder{DB: db}
 			m := in.(*model.VM)
+			if !h.ScopeFilter(db).Permitted(m) {
+				return
+			}
 			vm := &VM{}
 			vm.With(m)
 			vm.Link(h.Provider)
@@ -2,6 +2,7 @@ package ovirt
 
 import (
 	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
@@ -50,6 +51,65 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 	}
 }
 
+// Inventory scope filter.
+// Clusters are loaded once per filter.
+type ScopeFilter struct {
+	// Scope. Nil is unrestricted.
+	Scope *api.ProviderScope
+	// Database.
+	DB libmodel.DB
+	// Cached cluster permitted by ID.
+	clusters map[string]bool
+}
+
+// Determine whether the VM is within the scope.
+// Contained in a listed cluster.
+func (r *ScopeFilter) Permitted(m *model.VM) bool {
+	if r.Scope == nil {
+		return true
+	}
+	if r.clusters == nil {
+		r.clusters = map[string]bool{}
+	}
+	permitted, cached := r.clusters[m.Cluster]
+	if !cached {
+		cluster := &model.Cluster{
+			Base: model.Base{ID: m.Cluster},
+		}
+		err := r.DB.Get(cluster)
+		if err != nil {
+			return false
+		}
+		permitted = r.Scope.HasCluster(cluster.ID, cluster.Name)
+		r.clusters[m.Cluster] = permitted
+	}
+
+	return permitted
+}
+
+// Filter the VM list.
+func (r *ScopeFilter) Filter(list *[]model.VM) {
+	if r.Scope == nil {
+		return
+	}
+	kept := []model.VM{}
+	for i := range *list {
+		m := &(*list)[i]
+		if r.Permitted(m) {
+			kept = append(kept, *m)
+		}
+	}
+	*list = kept
+}
+
+// Build the scope filter.
+func (h Handler) ScopeFilter(db libmodel.DB) *ScopeFilter {
+	return &ScopeFilter{
+		Scope: h.Scope,
+		DB:    db,
+	}
+}
+
 // Path builder.
 type PathBuilder struct {
 	// Database.
@@ -131,13 +131,14 @@ func (h *DiskHandler) watch(ctx *gin.Context) {
 // REST Resource.
 type Disk struct {
 	Resource
-	Shared          bool   `json:"shared"`
-	StorageDomain   string `json:"storageDomain"`
-	Profile         string `json:"profile"`
-	ProvisionedSize int64  `json:"provisionedSize"`
-	ActualSize      int64  `json:"actualSize"`
-	StorageType     string `json:"storageType"`
-	Status          string `json:"status"`
+	Shared          bool       `json:"shared"`
+	StorageDomain   string     `json:"storageDomain"`
+	Profile         string     `json:"profile"`
+	ProvisionedSize int64      `json:"provisionedSize"`
+	ActualSize      int64      `json:"actualSize"`
+	StorageType     string     `json:"storageType"`
+	Status          string     `json:"status"`
+	Lun             *model.Lun `json:"lun,omitempty"`
 }
 
 // Build the resource using the model.
@@ -150,6 +151,10 @@ func (r *Disk) With(m *model.Disk) {
 	r.ActualSize = m.ActualSize
 	r.Shared = m.Shared
 	r.StorageDomain = m.StorageDomain
+	if m.StorageType == model.LunStorage {
+		lun := m.Lun
+		r.Lun = &lun
+	}
 }
 
 // Build self link (URI).
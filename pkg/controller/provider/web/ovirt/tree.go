@@ -96,6 +96,7 @@ func (h TreeHandler) Tree(ctx *gin.Context) {
 			&BranchNavigator{
 				detail: h.Detail,
 				db:     db,
+				scope:  h.ScopeFilter(db),
 			})
 		if err != nil {
 			log.Trace(
@@ -121,6 +122,7 @@ func (h TreeHandler) Tree(ctx *gin.Context) {
 type BranchNavigator struct {
 	db     libmodel.DB
 	detail int
+	scope  *ScopeFilter
 }
 
 // Next (children) on the branch.
@@ -152,7 +154,9 @@ func (n *BranchNavigator) Next(p libmodel.Model) (r []model.Model, err error) {
 		if nErr == nil {
 			for i := range vmList {
 				m := &vmList[i]
-				r = append(r, m)
+				if n.scope.Permitted(m) {
+					r = append(r, m)
+				}
 			}
 		} else {
 			err = nErr
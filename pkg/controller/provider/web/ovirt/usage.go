@@ -0,0 +1,74 @@
+package ovirt
+
+import (
+	"errors"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+)
+
+// Networks and storage domains used by the VMs.
+func (h Handler) Usage(db libmodel.DB, ids []string) (usage base.Usage, err error) {
+	filter := h.ScopeFilter(db)
+	for _, id := range ids {
+		m := &model.VM{
+			Base: model.Base{ID: id},
+		}
+		err = db.Get(m)
+		if errors.Is(err, model.NotFound) || (err == nil && !filter.Permitted(m)) {
+			err = nil
+			usage.NotFound = append(usage.NotFound, id)
+			continue
+		}
+		if err != nil {
+			return
+		}
+		vm := XVM{}
+		vm.VM.With(m)
+		err = vm.Expand(db)
+		if err != nil {
+			return
+		}
+		for _, nic := range vm.NICs {
+			if nic.Profile.Network == "" {
+				continue
+			}
+			network := &model.Network{
+				Base: model.Base{ID: nic.Profile.Network},
+			}
+			err = db.Get(network)
+			if err != nil {
+				return
+			}
+			usage.AddNetwork(
+				base.UsedNetwork{
+					Ref: ref.Ref{
+						ID:   network.ID,
+						Name: network.Name,
+					},
+					Vlan: network.VLan,
+				})
+		}
+		for _, da := range vm.DiskAttachments {
+			if da.Disk.StorageDomain == "" {
+				continue
+			}
+			sd := &model.StorageDomain{
+				Base: model.Base{ID: da.Disk.StorageDomain},
+			}
+			err = db.Get(sd)
+			if err != nil {
+				return
+			}
+			usage.AddStorage(
+				ref.Ref{
+					ID:   sd.ID,
+					Name: sd.Name,
+				})
+		}
+	}
+
+	return
+}
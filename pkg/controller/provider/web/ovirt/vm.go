@@ -69,7 +69,13 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	scope := h.ScopeFilter(db)
+	options := h.ListOptions(ctx)
+	if scope.Scope != nil {
+		// Paged after the scope is applied.
+		options.Page = nil
+	}
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -78,6 +84,10 @@ func (h VMHandler) List(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	if scope.Scope != nil {
+		scope.Filter(&list)
+		h.Page.Slice(&list)
+	}
 	pb := PathBuilder{DB: db}
 	for _, m := range list {
 		r := &VM{}
@@ -118,6 +128,10 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	if !h.ScopeFilter(db).Permitted(m) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
 	pb := PathBuilder{DB: db}
 	r := &VM{}
 	r.With(m)
@@ -138,6 +152,9 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 		func(in libmodel.Model) (r interface{}) {
 			pb := PathBuilder{DB: db}
 			m := in.(*model.VM)
+			if !h.ScopeFilter(db).Permitted(m) {
+				return
+			}
 			vm := &VM{}
 			vm.With(m)
 			vm.Link(h.Provider)
@@ -234,6 +251,7 @@ type VM struct {
 	BalloonedMemory             bool             `json:"balloonedMemory"`
 	IOThreads                   int16            `json:"ioThreads"`
 	BIOS                        string           `json:"bios"`
+	TpmEnabled                  bool             `json:"tpmEnabled"`
 	Display                     string           `json:"display"`
 	HasIllegalImages            bool             `json:"hasIllegalImages"`
 	NumaNodeAffinity            []string         `json:"numaNodeAffinity"`
@@ -242,6 +260,7 @@ type VM struct {
 	HaEnabled                   bool             `json:"haEnabled"`
 	UsbEnabled                  bool             `json:"usbEnabled"`
 	BootMenuEnabled             bool             `json:"bootMenuEnabled"`
+	BootDevices                 []string         `json:"bootDevices"`
 	PlacementPolicyAffinity     string           `json:"placementPolicyAffinity"`
 	Timezone                    string           `json:"timezone"`
 	Stateless                   string           `json:"stateless"`
@@ -284,6 +303,7 @@ func (r *VM) With(m *model.VM) {
 	r.BalloonedMemory = m.BalloonedMemory
 	r.IOThreads = m.IOThreads
 	r.BIOS = m.BIOS
+	r.TpmEnabled = m.TpmEnabled
 	r.Display = m.Display
 	r.HasIllegalImages = m.HasIllegalImages
 	r.NumaNodeAffinity = m.NumaNodeAffinity
@@ -292,6 +312,7 @@ func (r *VM) With(m *model.VM) {
 	r.HaEnabled = m.HaEnabled
 	r.UsbEnabled = m.UsbEnabled
 	r.BootMenuEnabled = m.BootMenuEnabled
+	r.BootDevices = m.BootDevices
 	r.PlacementPolicyAffinity = m.PlacementPolicyAffinity
 	r.Timezone = m.Timezone
 	r.Stateless = m.Stateless
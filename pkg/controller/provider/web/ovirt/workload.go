@@ -63,6 +63,10 @@ func (h WorkloadHandler) Get(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	if !h.ScopeFilter(db).Permitted(m) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
 	h.Detail = model.MaxDetail
 	r := Workload{}
 	r.With(m)
@@ -0,0 +1,289 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/throughput"
+	openstackmodel "github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
+	ovirtmodel "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ovirt"
+	vspheremodel "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Routes.
+const (
+	ThroughputPath = "/throughput"
+	// Query parameter for the destination storage class.
+	StorageClassParam = "storageClass"
+)
+
+// VM transfer estimate.
+type VMEstimate struct {
+	// VM ID.
+	ID string `json:"id"`
+	// VM name.
+	Name string `json:"name"`
+	// Throughput history found for the VM disks.
+	Known bool `json:"known"`
+	// Estimated transfer duration (seconds).
+	Duration int64 `json:"duration,omitempty"`
+	// Estimated time of completion when started now.
+	ETA *time.Time `json:"eta,omitempty"`
+}
+
+// Shared k8s API reader.
+var reader struct {
+	sync.Mutex
+	client.Client
+}
+
+// Throughput handler.
+// Reports the transfer throughput history observed for
+// the provider and used to estimate migration durations.
+type ThroughputHandler struct {
+	base.Handler
+}
+
+// Add routes to the `gin` router.
+func (h *ThroughputHandler) AddRoutes(e *gin.Engine) {
+	e.GET(vsphere.ProviderRoot+ThroughputPath, h.Get)
+	e.GET(ovirt.ProviderRoot+ThroughputPath, h.Get)
+	e.GET(openstack.ProviderRoot+ThroughputPath, h.Get)
+	e.GET(vsphere.VMRoot+ThroughputPath, h.VM)
+	e.GET(ovirt.VMRoot+ThroughputPath, h.VM)
+	e.GET(openstack.VMRoot+ThroughputPath, h.VM)
+}
+
+// Get the throughput history.
+func (h ThroughputHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.Provider.UID == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	cl, err := apiClient()
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	history, err := throughput.Load(cl, h.Provider)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// Get the estimated transfer duration of a VM.
+// The optional `storageClass` query parameter is the
+// destination storage class of the disks.
+func (h ThroughputHandler) VM(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.Provider.UID == "" {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	r := VMEstimate{ID: ctx.Param(vsphere.VMParam)}
+	host, disks, err := h.disks(&r)
+	if errors.Is(err, libmodel.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	cl, err := apiClient()
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	history, err := throughput.Load(cl, h.Provider)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	storageClass := ctx.Query(StorageClassParam)
+	for i := range disks {
+		disks[i].StorageClass = storageClass
+	}
+	duration, known := history.Duration(host, disks)
+	if known {
+		eta := time.Now().Add(duration).UTC()
+		r.Known = true
+		r.Duration = int64(duration.Seconds())
+		r.ETA = &eta
+	}
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+// Find the source host and disks of the VM.
+// VMs outside the inventory scope are not found.
+func (h ThroughputHandler) disks(r *VMEstimate) (host string, disks []throughput.Disk, err error) {
+	db := h.Collector.DB()
+	switch h.Provider.Type() {
+	case api.VSphere:
+		m := &vspheremodel.VM{Base: vspheremodel.Base{ID: r.ID}}
+		err = db.Get(m)
+		if err != nil {
+			return
+		}
+		if !(vsphere.Handler{Handler: h.Handler}).ScopeFilter(db).Permitted(m) {
+			err = libmodel.NotFound
+			return
+		}
+		r.Name = m.Name
+		host = m.Host
+		for _, disk := range m.Disks {
+			disks = append(
+				disks,
+				throughput.Disk{
+					Storage: disk.Datastore.ID,
+					Size:    disk.Capacity,
+				})
+		}
+	case api.OVirt:
+		m := &ovirtmodel.VM{Base: ovirtmodel.Base{ID: r.ID}}
+		err = db.Get(m)
+		if err != nil {
+			return
+		}
+		if !(ovirt.Handler{Handler: h.Handler}).ScopeFilter(db).Permitted(m) {
+			err = libmodel.NotFound
+			return
+		}
+		r.Name = m.Name
+		host = m.Host
+		for _, da := range m.DiskAttachments {
+			disk := &ovirtmodel.Disk{Base: ovirtmodel.Base{ID: da.Disk}}
+			err = db.Get(disk)
+			if err != nil {
+				return
+			}
+			size := disk.ProvisionedSize
+			if disk.ActualSize > size {
+				size = disk.ActualSize
+			}
+			disks = append(
+				disks,
+				throughput.Disk{
+					Storage: disk.StorageDomain,
+					Size:    size,
+				})
+		}
+	case api.OpenStack:
+		m := &openstackmodel.VM{Base: openstackmodel.Base{ID: r.ID}}
+		err = db.Get(m)
+		if err != nil {
+			return
+		}
+		if !(openstack.Handler{Handler: h.Handler}).ScopeFilter(db).Permitted(m) {
+			err = libmodel.NotFound
+			return
+		}
+		r.Name = m.Name
+		host = m.HostID
+		for _, attached := range m.AttachedVolumes {
+			volume := &openstackmodel.Volume{Base: openstackmodel.Base{ID: attached.ID}}
+			err = db.Get(volume)
+			if err != nil {
+				return
+			}
+			disks = append(
+				disks,
+				throughput.Disk{
+					Storage: volume.VolumeType,
+					Size:    int64(volume.Size) * 1024 * 1024 * 1024,
+				})
+		}
+	default:
+		err = libmodel.NotFound
+	}
+
+	return
+}
+
+// Build the (shared) k8s API client.
+func apiClient() (cl client.Client, err error) {
+	reader.Lock()
+	defer reader.Unlock()
+	if reader.Client != nil {
+		cl = reader.Client
+		return
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		scheme.AddToScheme,
+		apis.AddToScheme,
+	} {
+		err = add(s)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	cl, err = client.New(
+		cfg,
+		client.Options{
+			Scheme: s,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	reader.Client = cl
+
+	return
+}
@@ -1,12 +1,14 @@
 package vsphere
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
-	"strings"
 )
 
 // Package logger.
@@ -49,6 +51,110 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 	}
 }
 
+// Inventory scope filter.
+// Hosts and clusters are loaded once per filter.
+type ScopeFilter struct {
+	// Scope. Nil is unrestricted.
+	Scope *api.ProviderScope
+	// Database.
+	DB libmodel.DB
+	// Path builder.
+	pathBuilder *PathBuilder
+	// Cached cluster (ID) by host ID.
+	hosts map[string]string
+	// Cached cluster permitted by ID.
+	clusters map[string]bool
+}
+
+// Determine whether the VM is within the scope.
+// Contained in a listed folder or on a host within
+// a listed cluster.
+func (r *ScopeFilter) Permitted(m *model.VM) bool {
+	if r.Scope == nil {
+		return true
+	}
+	if r.pathBuilder == nil {
+		r.pathBuilder = &PathBuilder{DB: r.DB}
+		r.hosts = map[string]string{}
+		r.clusters = map[string]bool{}
+	}
+	if r.Scope.HasFolder(r.pathBuilder.Path(m)) {
+		return true
+	}
+	clusterID, cached := r.hosts[m.Host]
+	if !cached {
+		host := &model.Host{
+			Base: model.Base{ID: m.Host},
+		}
+		err := r.DB.Get(host)
+		if err != nil {
+			return false
+		}
+		clusterID = host.Cluster
+		r.hosts[m.Host] = clusterID
+	}
+	permitted, cached := r.clusters[clusterID]
+	if !cached {
+		cluster := &model.Cluster{
+			Base: model.Base{ID: clusterID},
+		}
+		err := r.DB.Get(cluster)
+		if err != nil {
+			return false
+		}
+		permitted = r.Scope.HasCluster(cluster.ID, cluster.Name)
+		r.clusters[clusterID] = permitted
+	}
+
+	return permitted
+}
+
+// Filter the VM list.
+func (r *ScopeFilter) Filter(list *[]model.VM) {
+	if r.Scope == nil {
+		return
+	}
+	kept := []model.VM{}
+	for i := range *list {
+		m := &(*list)[i]
+		if r.Permitted(m) {
+			kept = append(kept, *m)
+		}
+	}
+	*list = kept
+}
+
+// Filter the VM references.
+// References to other kinds are kept.
+func (r *ScopeFilter) Refs(refs []model.Ref) []model.Ref {
+	if r.Scope == nil {
+		return refs
+	}
+	kept := []model.Ref{}
+	for _, ref := range refs {
+		if ref.Kind == model.VmKind {
+			m := &model.VM{
+				Base: model.Base{ID: ref.ID},
+			}
+			err := r.DB.Get(m)
+			if err != nil || !r.Permitted(m) {
+				continue
+			}
+		}
+		kept = append(kept, ref)
+	}
+
+	return kept
+}
+
+// Build the scope filter.
+func (h Handler) ScopeFilter(db libmodel.DB) *ScopeFilter {
+	return &ScopeFilter{
+		Scope: h.Scope,
+		DB:    db,
+	}
+}
+
 // Path builder.
 type PathBuilder struct {
 	// Database.
@@ -79,9 +79,12 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 	}
 	content := []interface{}{}
 	pb := PathBuilder{DB: db}
+	scope := h.ScopeFilter(db)
 	for _, m := range list {
 		r := &Cluster{}
 		r.With(&m)
+		r.DasVms = scope.Refs(r.DasVms)
+		r.DrsVms = scope.Refs(r.DrsVms)
 		r.Link(h.Provider)
 		r.Path = pb.Path(&m)
 		content = append(content, r.Content(h.Detail))
@@ -120,6 +123,9 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 	pb := PathBuilder{DB: db}
 	r := &Cluster{}
 	r.With(m)
+	scope := h.ScopeFilter(db)
+	r.DasVms = scope.Refs(r.DasVms)
+	r.DrsVms = scope.Refs(r.DrsVms)
 	r.Link(h.Provider)
 	r.Path = pb.Path(m)
 	content := r.Content(model.MaxDetail)
@@ -139,6 +145,9 @@ func (h *ClusterHandler) watch(ctx *gin.Context) {
 			m := in.(*model.Cluster)
 			cluster := &Cluster{}
 			cluster.With(m)
+			scope := h.ScopeFilter(db)
+			cluster.DasVms = scope.Refs(cluster.DasVms)
+			cluster.DrsVms = scope.Refs(cluster.DrsVms)
 			cluster.Link(h.Provider)
 			cluster.Path = pb.Path(m)
 			r = cluster
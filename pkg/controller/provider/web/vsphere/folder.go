@@ -61,9 +61,11 @@ func (h FolderHandler) List(ctx *gin.Context) {
 	}
 	content := []interface{}{}
 	pb := PathBuilder{DB: db}
+	scope := h.ScopeFilter(db)
 	for _, m := range list {
 		r := &Folder{}
 		r.With(&m)
+		r.Children = scope.Refs(r.Children)
 		r.Link(h.Provider)
 		r.Path = pb.Path(&m)
 		content = append(content, r.Content(h.Detail))
@@ -102,6 +104,7 @@ func (h FolderHandler) Get(ctx *gin.Context) {
 	pb := PathBuilder{DB: db}
 	r := &Folder{}
 	r.With(m)
+	r.Children = h.ScopeFilter(db).Refs(r.Children)
 	r.Link(h.Provider)
 	r.Path = pb.Path(m)
 	content := r.Content(model.MaxDetail)
@@ -121,6 +124,7 @@ func (h *FolderHandler) watch(ctx *gin.Context) {
 			m := in.(*model.Folder)
 			folder := &Folder{}
 			folder.With(m)
+			folder.Children = h.ScopeFilter(db).Refs(folder.Children)
 			folder.Link(h.Provider)
 			folder.Path = pb.Path(m)
 			r = folder
@@ -69,9 +69,11 @@ func (h HostHandler) List(ctx *gin.Context) {
 	}
 	content := []interface{}{}
 	pb := PathBuilder{DB: db}
+	scope := h.ScopeFilter(db)
 	for _, m := range list {
 		r := &Host{}
 		r.With(&m)
+		r.VMs = scope.Refs(r.VMs)
 		err = h.buildAdapters(r)
 		if err != nil {
 			return
@@ -115,6 +117,7 @@ func (h HostHandler) Get(ctx *gin.Context) {
 	pb := PathBuilder{DB: db}
 	r := &Host{}
 	r.With(m)
+	r.VMs = h.ScopeFilter(db).Refs(r.VMs)
 	err = h.buildAdapters(r)
 	if err != nil {
 		log.Trace(
@@ -143,6 +146,7 @@ func (h *HostHandler) watch(ctx *gin.Context) {
 			m := in.(*model.Host)
 			host := &Host{}
 			host.With(m)
+			host.VMs = h.ScopeFilter(db).Refs(host.VMs)
 			host.Link(h.Provider)
 			host.Path = pb.Path(m)
 			r = host
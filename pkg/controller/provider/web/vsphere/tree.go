@@ -114,6 +114,7 @@ func (h TreeHandler) VmTree(ctx *gin.Context) {
 			&VMNavigator{
 				detail: h.Detail,
 				db:     db,
+				scope:  h.ScopeFilter(db),
 			})
 		if err != nil {
 			log.Trace(
@@ -179,6 +180,7 @@ func (h TreeHandler) HostTree(ctx *gin.Context) {
 			&HostNavigator{
 				detail: h.Detail,
 				db:     db,
+				scope:  h.ScopeFilter(db),
 			})
 		if err != nil {
 			log.Trace(
@@ -206,6 +208,8 @@ type HostNavigator struct {
 	db libmodel.DB
 	// VM detail.
 	detail int
+	// Inventory scope filter.
+	scope *ScopeFilter
 }
 
 // Next (children) on the branch.
@@ -280,7 +284,9 @@ func (n *HostNavigator) Next(p libmodel.Model) (r []libmodel.Model, err error) {
 		if err == nil {
 			for i := range list {
 				m := &list[i]
-				r = append(r, m)
+				if n.scope.Permitted(m) {
+					r = append(r, m)
+				}
 			}
 		} else {
 			return
@@ -296,6 +302,8 @@ type VMNavigator struct {
 	db libmodel.DB
 	// VM detail.
 	detail int
+	// Inventory scope filter.
+	scope *ScopeFilter
 }
 
 // Next (children) on the branch.
@@ -340,7 +348,9 @@ func (n *VMNavigator) Next(p libmodel.Model) (r []libmodel.Model, err error) {
 		if err == nil {
 			for i := range vm {
 				m := &vm[i]
-				r = append(r, m)
+				if n.scope.Permitted(m) {
+					r = append(r, m)
+				}
 			}
 		} else {
 			return
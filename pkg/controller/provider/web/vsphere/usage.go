@@ -0,0 +1,65 @@
+package vsphere
+
+import (
+	"errors"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+)
+
+// Networks and datastores used by the VMs.
+func (h Handler) Usage(db libmodel.DB, ids []string) (usage base.Usage, err error) {
+	filter := h.ScopeFilter(db)
+	for _, id := range ids {
+		vm := &model.VM{
+			Base: model.Base{ID: id},
+		}
+		err = db.Get(vm)
+		if errors.Is(err, model.NotFound) || (err == nil && !filter.Permitted(vm)) {
+			err = nil
+			usage.NotFound = append(usage.NotFound, id)
+			continue
+		}
+		if err != nil {
+			return
+		}
+		for _, r := range vm.Networks {
+			network := &model.Network{
+				Base: model.Base{ID: r.ID},
+			}
+			err = db.Get(network)
+			if err != nil {
+				return
+			}
+			usage.AddNetwork(
+				base.UsedNetwork{
+					Ref: ref.Ref{
+						ID:   network.ID,
+						Name: network.Name,
+					},
+					Vlan: network.Tag,
+				})
+		}
+		for _, disk := range vm.Disks {
+			if disk.Datastore.ID == "" {
+				continue
+			}
+			ds := &model.Datastore{
+				Base: model.Base{ID: disk.Datastore.ID},
+			}
+			err = db.Get(ds)
+			if err != nil {
+				return
+			}
+			usage.AddStorage(
+				ref.Ref{
+					ID:   ds.ID,
+					Name: ds.Name,
+				})
+		}
+	}
+
+	return
+}
@@ -58,7 +58,13 @@ func (h VMHandler) List(ctx *gin.Context) {
 	}()
 	db := h.Collector.DB()
 	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	scope := h.ScopeFilter(db)
+	options := h.ListOptions(ctx)
+	if scope.Scope != nil {
+		// Paged after the scope is applied.
+		options.Page = nil
+	}
+	err = db.List(&list, options)
 	if err != nil {
 		return
 	}
@@ -67,6 +73,10 @@ func (h VMHandler) List(ctx *gin.Context) {
 	if err != nil {
 		return
 	}
+	if scope.Scope != nil {
+		scope.Filter(&list)
+		h.Page.Slice(&list)
+	}
 	pb := PathBuilder{DB: db}
 	for _, m := range list {
 		r := &VM{}
@@ -106,6 +116,10 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	if !h.ScopeFilter(db).Permitted(m) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
 	pb := PathBuilder{DB: db}
 	r := &VM{}
 	r.With(m)
@@ -126,6 +140,9 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 		func(in libmodel.Model) (r interface{}) {
 			pb := PathBuilder{DB: db}
 			m := in.(*model.VM)
+			if !h.ScopeFilter(db).Permitted(m) {
+				return
+			}
 			vm := &VM{}
 			vm.With(m)
 			vm.Link(h.Provider)
@@ -210,28 +227,37 @@ func (r *VM1) Content(detail int) interface{} {
 // VM full detail.
 type VM struct {
 	VM1
-	PolicyVersion         int            `json:"policyVersion"`
-	UUID                  string         `json:"uuid"`
-	Firmware              string         `json:"firmware"`
-	ConnectionState       string         `json:"connectionState"`
-	Snapshot              model.Ref      `json:"snapshot"`
-	ChangeTrackingEnabled bool           `json:"changeTrackingEnabled"`
-	CpuAffinity           []int32        `json:"cpuAffinity"`
-	CpuHotAddEnabled      bool           `json:"cpuHotAddEnabled"`
-	CpuHotRemoveEnabled   bool           `json:"cpuHotRemoveEnabled"`
-	MemoryHotAddEnabled   bool           `json:"memoryHotAddEnabled"`
-	FaultToleranceEnabled bool           `json:"faultToleranceEnabled"`
-	CpuCount              int32          `json:"cpuCount"`
-	CoresPerSocket        int32          `json:"coresPerSocket"`
-	MemoryMB              int32          `json:"memoryMB"`
-	GuestName             string         `json:"guestName"`
-	GuestID               string         `json:"guestId"`
-	BalloonedMemory       int32          `json:"balloonedMemory"`
-	IpAddress             string         `json:"ipAddress"`
-	StorageUsed           int64          `json:"storageUsed"`
-	NumaNodeAffinity      []string       `json:"numaNodeAffinity"`
-	Devices               []model.Device `json:"devices"`
-	NICs                  []model.NIC    `json:"nics"`
+	PolicyVersion         int                     `json:"policyVersion"`
+	UUID                  string                  `json:"uuid"`
+	Firmware              string                  `json:"firmware"`
+	SecureBoot            bool                    `json:"secureBoot"`
+	TpmEnabled            bool                    `json:"tpmEnabled"`
+	ConfigFile            string                  `json:"configFile"`
+	NVRAM                 string                  `json:"nvram"`
+	ConnectionState       string                  `json:"connectionState"`
+	Snapshot              model.Ref               `json:"snapshot"`
+	ChangeTrackingEnabled bool                    `json:"changeTrackingEnabled"`
+	CpuAffinity           []int32                 `json:"cpuAffinity"`
+	CpuHotAddEnabled      bool                    `json:"cpuHotAddEnabled"`
+	CpuHotRemoveEnabled   bool                    `json:"cpuHotRemoveEnabled"`
+	MemoryHotAddEnabled   bool                    `json:"memoryHotAddEnabled"`
+	FaultToleranceEnabled bool                    `json:"faultToleranceEnabled"`
+	CpuCount              int32                   `json:"cpuCount"`
+	CoresPerSocket        int32                   `json:"coresPerSocket"`
+	MemoryMB              int32                   `json:"memoryMB"`
+	GuestName             string                  `json:"guestName"`
+	GuestID               string                  `json:"guestId"`
+	BalloonedMemory       int32                   `json:"balloonedMemory"`
+	IpAddress             string                  `json:"ipAddress"`
+	StorageUsed           int64                   `json:"storageUsed"`
+	NumaNodeAffinity      []string                `json:"numaNodeAffinity"`
+	Devices               []model.Device          `json:"devices"`
+	NICs                  []model.NIC             `json:"nics"`
+	CDROMs                []model.CDROM           `json:"cdroms"`
+	BootOrder             []model.BootDevice      `json:"bootOrder"`
+	Annotation            string                  `json:"annotation"`
+	CustomAttributes      []model.CustomAttribute `json:"customAttributes"`
+	Tags                  []model.Tag             `json:"tags"`
 }
 
 // Build the resource using the model.
@@ -240,6 +266,10 @@ func (r *VM) With(m *model.VM) {
 	r.PolicyVersion = m.PolicyVersion
 	r.UUID = m.UUID
 	r.Firmware = m.Firmware
+	r.SecureBoot = m.SecureBoot
+	r.TpmEnabled = m.TpmEnabled
+	r.ConfigFile = m.ConfigFile
+	r.NVRAM = m.NVRAM
 	r.ConnectionState = m.ConnectionState
 	r.Snapshot = m.Snapshot
 	r.ChangeTrackingEnabled = m.ChangeTrackingEnabled
@@ -259,6 +289,11 @@ func (r *VM) With(m *model.VM) {
 	r.Devices = m.Devices
 	r.NumaNodeAffinity = m.NumaNodeAffinity
 	r.NICs = m.NICs
+	r.CDROMs = m.CDROMs
+	r.BootOrder = m.BootOrder
+	r.Annotation = m.Annotation
+	r.CustomAttributes = m.CustomAttributes
+	r.Tags = m.Tags
 }
 
 // Build self link (URI).
@@ -0,0 +1,277 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	liburl "net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Custom policy (module) ID prefix.
+const CustomRoot = "forklift/custom"
+
+// Agent policy API.
+const PoliciesPath = "/v1/policies"
+
+// Built-in policy packages.
+// Custom modules may not define rules in the built-in
+// packages. The concerns defined in the custom package
+// of the provider are reported by the built-in policies.
+var BuiltinPackages = map[string]string{
+	"io.konveyor.forklift.vmware":    "io.konveyor.forklift.custom.vmware",
+	"io.konveyor.forklift.ovirt":     "io.konveyor.forklift.custom.ovirt",
+	"io.konveyor.forklift.openstack": "io.konveyor.forklift.custom.openstack",
+}
+
+// Module package declaration.
+var packageRe = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)`)
+
+// Custom policies (singleton).
+var Custom = &Bundle{}
+
+// Custom policy bundle.
+// User-supplied Rego modules loaded into the policy agent
+// in addition to the built-in policies. The modules are
+// expected to define `concerns` rules in the custom provider
+// packages, for example: io.konveyor.forklift.custom.vmware.
+type Bundle struct {
+	// Mutex.
+	mutex sync.RWMutex
+	// Loaded modules (content) by ID.
+	loaded map[string]string
+	// Digest of the loaded modules.
+	digest uint32
+	// Closed when the loaded modules change.
+	changed chan struct{}
+}
+
+// Policy version.
+// The built-in rules version combined with the digest
+// of the custom modules. Changing the custom modules
+// changes the version and triggers VM (re)validation.
+func (r *Bundle) Version(builtin int) (version int) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	version = int(r.digest)<<32 | builtin
+	return
+}
+
+// Returns a channel that is closed when the
+// loaded modules change.
+func (r *Bundle) Changed() <-chan struct{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return r.changed
+}
+
+// Number of loaded modules.
+func (r *Bundle) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.loaded)
+}
+
+// Load the modules (by ID) into the policy agent.
+// All modules are (re)loaded to ensure the agent has not
+// lost them (restarted). Custom modules known to the agent
+// but not included are deleted. Modules rejected by the
+// agent or defined in a built-in package are returned and
+// excluded from the bundle.
+func (r *Bundle) Sync(client *Client, modules map[string]string) (rejected map[string]error, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rejected = map[string]error{}
+	loaded := map[string]string{}
+	for id, module := range modules {
+		pErr := r.builtin(module)
+		if pErr == nil {
+			pErr = client.putPolicy(r.path(id), module)
+		}
+		if pErr != nil {
+			if _, cast := pErr.(*ValidationError); cast {
+				rejected[id] = pErr
+				continue
+			}
+			err = pErr
+			return
+		}
+		loaded[id] = module
+	}
+	known, err := client.listPolicies()
+	if err != nil {
+		return
+	}
+	for _, policyID := range known {
+		if !strings.HasPrefix(policyID, CustomRoot+"/") {
+			continue
+		}
+		id := strings.TrimPrefix(policyID, CustomRoot+"/")
+		if _, found := loaded[id]; found {
+			continue
+		}
+		err = client.deletePolicy(r.path(id))
+		if err != nil {
+			return
+		}
+	}
+	r.loaded = loaded
+	digest := r.digestOf(loaded)
+	if digest != r.digest {
+		r.digest = digest
+		if r.changed != nil {
+			close(r.changed)
+			r.changed = nil
+		}
+		log.Info(
+			"Custom policies changed.",
+			"modules",
+			len(loaded))
+	}
+
+	return
+}
+
+// Reject modules defined in a built-in package.
+func (r *Bundle) builtin(module string) (err error) {
+	matched := packageRe.FindStringSubmatch(module)
+	if len(matched) < 2 {
+		return
+	}
+	if custom, found := BuiltinPackages[matched[1]]; found {
+		err = &ValidationError{
+			Errors: []string{
+				"package " + matched[1] + " is built-in; use package " + custom,
+			},
+		}
+	}
+
+	return
+}
+
+// Agent (policy) path.
+func (r *Bundle) path(id string) string {
+	return path.Join(PoliciesPath, CustomRoot, id)
+}
+
+// Digest of the modules.
+// Zero when no modules are loaded.
+func (r *Bundle) digestOf(modules map[string]string) (digest uint32) {
+	if len(modules) == 0 {
+		return
+	}
+	ids := []string{}
+	for id := range modules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	h := fnv.New32a()
+	for _, id := range ids {
+		_, _ = h.Write([]byte(id))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(modules[id]))
+		_, _ = h.Write([]byte{0})
+	}
+	digest = h.Sum32()
+	return
+}
+
+// Create or update a policy (module).
+func (r *Client) putPolicy(path string, module string) (err error) {
+	return r.send(http.MethodPut, path, []byte(module))
+}
+
+// List the IDs of the policies (modules) known to the agent.
+func (r *Client) listPolicies() (ids []string, err error) {
+	if !r.Enabled() {
+		return
+	}
+	out := &struct {
+		Result []struct {
+			ID string `json:"id"`
+		} `json:"result"`
+	}{}
+	_, err = r.get(PoliciesPath, out)
+	if err != nil {
+		return
+	}
+	for _, policy := range out.Result {
+		ids = append(ids, policy.ID)
+	}
+
+	return
+}
+
+// Delete a policy (module).
+func (r *Client) deletePolicy(path string) (err error) {
+	err = r.send(http.MethodDelete, path, nil)
+	return
+}
+
+// Send a (policy API) request.
+// Reported (compile) errors are returned as a ValidationError.
+func (r *Client) send(method string, path string, body []byte) (err error) {
+	if !r.Enabled() {
+		return
+	}
+	parsedURL, err := liburl.Parse(Settings.PolicyAgent.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.buildTransport()
+	if err != nil {
+		return
+	}
+	parsedURL.Path = path
+	request, err := http.NewRequest(method, parsedURL.String(), bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", "text/plain")
+	client := http.Client{Transport: r.Transport}
+	response, err := client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNotFound:
+	case http.StatusBadRequest:
+		reply := &struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}{}
+		content, _ := ioutil.ReadAll(response.Body)
+		_ = json.Unmarshal(content, reply)
+		vErr := &ValidationError{}
+		for _, e := range reply.Errors {
+			vErr.Errors = append(vErr.Errors, e.Message)
+		}
+		if len(vErr.Errors) == 0 {
+			vErr.Errors = append(vErr.Errors, reply.Message)
+		}
+		err = vErr
+	default:
+		err = liberr.New(http.StatusText(response.StatusCode))
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestBundle(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	policies := map[string]string{
+		"/v1/policies/forklift/custom/old/tags":    "package io.konveyor.forklift.custom.vmware",
+		"/v1/policies/io/konveyor/forklift/vmware": "package io.konveyor.forklift.vmware",
+	}
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				out := struct {
+					Result []map[string]string `json:"result"`
+				}{}
+				for p := range policies {
+					out.Result = append(
+						out.Result,
+						map[string]string{"id": strings.TrimPrefix(p, "/v1/policies/")})
+				}
+				b, _ := json.Marshal(out)
+				_, _ = w.Write(b)
+			case http.MethodPut:
+				body, _ := ioutil.ReadAll(r.Body)
+				if strings.Contains(string(body), "invalid") {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"errors":[{"message":"rego_parse_error"}]}`))
+					return
+				}
+				policies[r.URL.Path] = string(body)
+			case http.MethodDelete:
+				delete(policies, r.URL.Path)
+			}
+		}))
+	defer server.Close()
+	Settings.PolicyAgent.URL = server.URL
+	defer func() {
+		Settings.PolicyAgent.URL = ""
+	}()
+	client := &Client{}
+	bundle := &Bundle{}
+	g.Expect(bundle.Version(5)).To(gomega.Equal(5))
+	// Load.
+	changed := bundle.Changed()
+	rejected, err := bundle.Sync(
+		client,
+		map[string]string{
+			"a/tags":    "package io.konveyor.forklift.custom.vmware",
+			"a/bad":     "invalid",
+			"a/builtin": "# Override.\npackage io.konveyor.forklift.vmware",
+		})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rejected).To(gomega.HaveKey("a/bad"))
+	g.Expect(rejected).To(gomega.HaveKey("a/builtin"))
+	g.Expect(policies).To(gomega.HaveKey("/v1/policies/forklift/custom/a/tags"))
+	g.Expect(policies).ToNot(gomega.HaveKey("/v1/policies/forklift/custom/a/builtin"))
+	// Not accounted for.
+	g.Expect(policies).ToNot(gomega.HaveKey("/v1/policies/forklift/custom/old/tags"))
+	// Built-in.
+	g.Expect(policies).To(gomega.HaveKey("/v1/policies/io/konveyor/forklift/vmware"))
+	g.Expect(bundle.Len()).To(gomega.Equal(1))
+	g.Expect(changed).To(gomega.BeClosed())
+	version := bundle.Version(5)
+	g.Expect(version).ToNot(gomega.Equal(5))
+	g.Expect(version & 0xffffffff).To(gomega.Equal(5))
+	// Reload (unchanged).
+	changed = bundle.Changed()
+	_, err = bundle.Sync(
+		client,
+		map[string]string{
+			"a/tags": "package io.konveyor.forklift.custom.vmware",
+		})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(changed).ToNot(gomega.BeClosed())
+	g.Expect(bundle.Version(5)).To(gomega.Equal(version))
+	// Unload.
+	_, err = bundle.Sync(client, map[string]string{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(policies).To(gomega.HaveLen(1))
+	g.Expect(changed).To(gomega.BeClosed())
+	g.Expect(bundle.Version(5)).To(gomega.Equal(5))
+}
@@ -15,6 +15,7 @@ import (
 	"net"
 	"net/http"
 	liburl "net/url"
+	"sync"
 	"time"
 )
 
@@ -29,6 +30,16 @@ var Settings = &settings.Settings
 // Pool (singleton).
 var Agent Pool
 
+// Delay before an unreachable agent is retried.
+const RetryDelay = time.Second * 30
+
+// Agent availability.
+var availability struct {
+	sync.Mutex
+	// Last failed request.
+	failed time.Time
+}
+
 // Error reported by the service.
 type ValidationError struct {
 	Errors []string
@@ -49,8 +60,15 @@ func (r *Client) Enabled() bool {
 }
 
 // Policy version.
+// Reported by the embedded engine when the agent is
+// disabled or unreachable. The embedded engine does not
+// evaluate custom policies so its version excludes the
+// custom policies digest. When custom policies are loaded,
+// the VMs validated by the embedded engine are (re)validated
+// once the agent is reachable.
 func (r *Client) Version(path string) (version int, err error) {
-	if !r.Enabled() {
+	if !r.reachable() {
+		version, err = Embedded.Version(path)
 		return
 	}
 	out := &struct {
@@ -58,12 +76,17 @@ func (r *Client) Version(path string) (version int, err error) {
 			Version int `json:"rules_version"`
 		} `json:"result"`
 	}{}
-	err = r.get(path, out)
+	status, err := r.get(path, out)
 	if err != nil {
+		if unavailable(status) {
+			r.failed(err)
+			version, err = Embedded.Version(path)
+		}
 		return
 	}
 
-	version = out.Result.Version
+	r.succeeded()
+	version = Custom.Version(out.Result.Version)
 
 	log.V(3).Info(
 		"Policy version detected.",
@@ -76,11 +99,19 @@ func (r *Client) Version(path string) (version int, err error) {
 }
 
 // Validate the VM.
+// Validated by the embedded engine when the agent is
+// disabled or unreachable.
 func (r *Client) Validate(
 	path string,
 	workload interface{}) (version int, concerns []model.Concern, err error) {
 	//
-	if !r.Enabled() {
+	engine := EngineAgent
+	defer func() {
+		recordValidation(engine, err)
+	}()
+	if !r.reachable() {
+		engine = EngineEmbedded
+		version, concerns, err = Embedded.Validate(path, workload)
 		return
 	}
 	in := &struct {
@@ -94,11 +125,16 @@ func (r *Client) Validate(
 			Errors   []string        `json:"errors"`
 		}
 	}{}
-	err = r.post(path, in, out)
+	status, err := r.post(path, in, out)
 	if err != nil {
-		err = liberr.Wrap(err)
+		if unavailable(status) {
+			r.failed(err)
+			engine = EngineEmbedded
+			version, concerns, err = Embedded.Validate(path, workload)
+		}
 		return
 	}
+	r.succeeded()
 	if len(out.Result.Errors) > 0 {
 		err = liberr.Wrap(
 			&ValidationError{
@@ -108,13 +144,66 @@ func (r *Client) Validate(
 	}
 
 	concerns = out.Result.Concerns
-	version = out.Result.Version
+	version = Custom.Version(out.Result.Version)
 
 	return
 }
 
+// The agent is enabled and has not recently failed.
+func (r *Client) reachable() bool {
+	if !r.Enabled() {
+		return false
+	}
+	availability.Lock()
+	defer availability.Unlock()
+	return availability.failed.IsZero() ||
+		time.Since(availability.failed) > RetryDelay
+}
+
+// The agent is enabled but unreachable and the
+// embedded engine is used.
+func (r *Client) Fallback() bool {
+	if !r.Enabled() {
+		return false
+	}
+	availability.Lock()
+	defer availability.Unlock()
+	return !availability.failed.IsZero()
+}
+
+// The request failed to connect or the agent
+// reported a server error.
+func unavailable(status int) bool {
+	return status == 0 || status >= http.StatusInternalServerError
+}
+
+// Record a failed (agent) request.
+// The embedded engine is used until the agent is retried.
+func (r *Client) failed(err error) {
+	availability.Lock()
+	defer availability.Unlock()
+	if availability.failed.IsZero() {
+		log.Info(
+			"Policy agent unreachable, using the embedded engine.",
+			"reason",
+			err.Error())
+	}
+	availability.failed = time.Now()
+}
+
+// Record a succeeded (agent) request.
+func (r *Client) succeeded() {
+	availability.Lock()
+	defer availability.Unlock()
+	if !availability.failed.IsZero() {
+		log.Info("Policy agent reachable.")
+	}
+	availability.failed = time.Time{}
+}
+
 // Get request.
-func (r *Client) get(path string, out interface{}) (err error) {
+// The status is zero when no response was received.
+func (r *Client) get(path string, out interface{}) (status int, err error) {
 	parsedURL, err := liburl.Parse(Settings.PolicyAgent.URL)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -130,7 +219,7 @@ func (r *Client) get(path string, out interface{}) (err error) {
 		"GET request.",
 		"url",
 		url)
-	status, err := r.LibClient.Get(url, out)
+	status, err = r.LibClient.Get(url, out)
 	if err != nil {
 		return
 	}
@@ -142,7 +231,8 @@ func (r *Client) get(path string, out interface{}) (err error) {
 }
 
 // Post request.
-func (r *Client) post(path string, in interface{}, out interface{}) (err error) {
+// The status is zero when no response was received.
+func (r *Client) post(path string, in interface{}, out interface{}) (status int, err error) {
 	parsedURL, err := liburl.Parse(Settings.PolicyAgent.URL)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -160,7 +250,7 @@ func (r *Client) post(path string, in interface{}, out interface{}) (err error)
 		url,
 		"body",
 		in)
-	status, err := r.LibClient.Post(url, in, out)
+	status, err = r.LibClient.Post(url, in, out)
 	if err != nil {
 		return
 	}
@@ -295,7 +385,7 @@ type Worker struct {
 
 // Main worker run.
 // Process input queue. Validation delegated to the
-// policy agent.
+// policy agent or the embedded engine.
 func (r *Worker) run() {
 	go func() {
 		log.V(1).Info(
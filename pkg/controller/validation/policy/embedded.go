@@ -0,0 +1,299 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Engines.
+const (
+	EngineAgent    = "agent"
+	EngineEmbedded = "embedded"
+)
+
+// Embedded engine (singleton).
+var Embedded = &Engine{}
+
+// Embedded (in-process) policy engine.
+// Evaluates the built-in policies ported from the Rego
+// modules when the policy agent is disabled or cannot be
+// reached. Custom policies are only evaluated by the agent.
+type Engine struct {
+}
+
+// Policy version.
+// The path is the agent endpoint used to select the rule set.
+func (r *Engine) Version(path string) (version int, err error) {
+	rules, err := r.ruleSet(path)
+	if err != nil {
+		return
+	}
+	version = rules.Version
+	return
+}
+
+// Validate the VM.
+// The path is the agent endpoint used to select the rule set.
+func (r *Engine) Validate(
+	path string,
+	workload interface{}) (version int, concerns []model.Concern, err error) {
+	//
+	rules, err := r.ruleSet(path)
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(workload)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	in := Document{}
+	err = json.Unmarshal(b, &in.value)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if !in.IsString("name") {
+		err = liberr.Wrap(
+			&ValidationError{
+				Errors: []string{"No VM name found in input body"},
+			})
+		return
+	}
+	concerns = rules.Evaluate(in)
+	version = rules.Version
+
+	return
+}
+
+// Find the rule set for the agent endpoint.
+// Example: /v1/data/io/konveyor/forklift/vmware/validate.
+func (r *Engine) ruleSet(endpoint string) (rules *RuleSet, err error) {
+	provider := path.Base(path.Dir(endpoint))
+	rules, found := RuleSets[provider]
+	if !found {
+		err = liberr.New(
+			fmt.Sprintf(
+				"Rule set for '%s' not found.",
+				endpoint))
+	}
+	return
+}
+
+// Built-in rule set.
+type RuleSet struct {
+	// Rules version (RULES_VERSION).
+	Version int
+	// Rules.
+	Rules []Rule
+}
+
+// Evaluate the rules.
+// Like the agent, concerns are returned as a (sorted) set.
+func (r *RuleSet) Evaluate(in Document) (concerns []model.Concern) {
+	set := map[model.Concern]bool{}
+	for _, rule := range r.Rules {
+		if !rule.Match(in) {
+			continue
+		}
+		concern := model.Concern{
+			Category:   rule.Category,
+			Label:      rule.Label,
+			Assessment: rule.Assessment,
+		}
+		if rule.Assess != nil {
+			concern.Assessment = rule.Assess(in)
+		}
+		set[concern] = true
+	}
+	concerns = []model.Concern{}
+	for concern := range set {
+		concerns = append(concerns, concern)
+	}
+	sort.Slice(concerns, func(i, j int) bool {
+		a, b := concerns[i], concerns[j]
+		if a.Assessment != b.Assessment {
+			return a.Assessment < b.Assessment
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Label < b.Label
+	})
+
+	return
+}
+
+// Built-in rule.
+// Ported from a Rego module `concerns` rule.
+type Rule struct {
+	Category   string
+	Label      string
+	Assessment string
+	// Assessment built from the input.
+	Assess func(in Document) string
+	// The concern applies.
+	Match func(in Document) bool
+}
+
+// Input document.
+// Navigated using dotted paths. Like Rego, undefined values
+// (not found or the wrong type) never match.
+type Document struct {
+	value interface{}
+}
+
+// Get the value at the path.
+func (r Document) Get(path string) (v interface{}, found bool) {
+	v = r.value
+	if path == "" {
+		found = true
+		return
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, cast := v.(map[string]interface{})
+		if !cast {
+			return
+		}
+		v, found = object[key]
+		if !found {
+			return
+		}
+	}
+	return
+}
+
+// The value is defined and not false.
+func (r Document) Truthy(path string) bool {
+	v, found := r.Get(path)
+	return found && v != false
+}
+
+// The value is defined and equal.
+func (r Document) Eq(path string, want interface{}) bool {
+	v, found := r.Get(path)
+	return found && v == want
+}
+
+// The value is defined and not equal.
+func (r Document) Neq(path string, want interface{}) bool {
+	v, found := r.Get(path)
+	return found && v != want
+}
+
+// The value is a number greater than n.
+func (r Document) Gt(path string, n float64) bool {
+	v, found := r.Get(path)
+	if !found {
+		return false
+	}
+	f, cast := v.(float64)
+	return cast && f > n
+}
+
+// The value is a string.
+func (r Document) IsString(path string) bool {
+	v, _ := r.Get(path)
+	_, cast := v.(string)
+	return cast
+}
+
+// The value is a string matched by the pattern.
+func (r Document) Match(path string, pattern string) bool {
+	v, _ := r.Get(path)
+	s, cast := v.(string)
+	return cast && compiled(pattern).MatchString(s)
+}
+
+// Number of items (array, object) or characters (string).
+func (r Document) Count(path string) (n int, found bool) {
+	v, _ := r.Get(path)
+	found = true
+	switch x := v.(type) {
+	case []interface{}:
+		n = len(x)
+	case map[string]interface{}:
+		n = len(x)
+	case string:
+		n = len([]rune(x))
+	default:
+		found = false
+	}
+	return
+}
+
+// The value is a non-empty array, object or string.
+func (r Document) NotEmpty(path string) bool {
+	n, found := r.Count(path)
+	return found && n != 0
+}
+
+// The value is an object containing the key.
+func (r Document) HasKey(path string, key string) bool {
+	v, _ := r.Get(path)
+	object, cast := v.(map[string]interface{})
+	if !cast {
+		return false
+	}
+	_, found := object[key]
+	return found
+}
+
+// Items (array elements, object values).
+func (r Document) Items(path string) (items []Document) {
+	v, _ := r.Get(path)
+	switch x := v.(type) {
+	case []interface{}:
+		for _, item := range x {
+			items = append(items, Document{value: item})
+		}
+	case map[string]interface{}:
+		for _, item := range x {
+			items = append(items, Document{value: item})
+		}
+	}
+	return
+}
+
+// Any of the items matched.
+func (r Document) Any(path string, match func(Document) bool) bool {
+	return r.CountOf(path, match) > 0
+}
+
+// Number of items matched.
+func (r Document) CountOf(path string, match func(Document) bool) (n int) {
+	for _, item := range r.Items(path) {
+		if match(item) {
+			n++
+		}
+	}
+	return
+}
+
+// Compiled patterns.
+var patterns = struct {
+	sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{
+	compiled: map[string]*regexp.Regexp{},
+}
+
+// Compile (cached) the pattern.
+func compiled(pattern string) (re *regexp.Regexp) {
+	patterns.Lock()
+	defer patterns.Unlock()
+	re, found := patterns.compiled[pattern]
+	if !found {
+		re = regexp.MustCompile(pattern)
+		patterns.compiled[pattern] = re
+	}
+	return
+}
@@ -0,0 +1,216 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestEmbedded(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	labels := func(endpoint string, workload interface{}) (labels []string) {
+		version, concerns, err := Embedded.Validate(endpoint, workload)
+		g.Expect(err).To(gomega.BeNil())
+		expected, _ := Embedded.Version(endpoint)
+		g.Expect(version).To(gomega.Equal(expected))
+		for _, c := range concerns {
+			labels = append(labels, c.Label)
+		}
+		return
+	}
+	vmware := "/v1/data/io/konveyor/forklift/vmware/validate"
+	g.Expect(labels(vmware, map[string]interface{}{"name": "test"})).To(gomega.BeEmpty())
+	g.Expect(labels(
+		vmware,
+		map[string]interface{}{
+			"id":                    "vm-1",
+			"name":                  "Test.VM",
+			"changeTrackingEnabled": false,
+			"firmware":              "efi",
+			"secureBoot":            true,
+			"disks": []interface{}{
+				map[string]interface{}{"shared": false},
+				map[string]interface{}{"rdm": true},
+			},
+			"host": map[string]interface{}{
+				"cluster": map[string]interface{}{
+					"hostAffinityVms": []interface{}{
+						map[string]interface{}{"id": "vm-1"},
+					},
+				},
+			},
+		})).To(gomega.ConsistOf(
+		"Changed Block Tracking (CBT) not enabled",
+		"UEFI secure boot detected",
+		"Raw Device Mapped disk detected",
+		"VM-Host affinity detected",
+		"Invalid VM Name"))
+	// SCSI reservations are reported along with the shared disk.
+	ovirt := "/v1/data/io/konveyor/forklift/ovirt/validate"
+	g.Expect(labels(
+		ovirt,
+		map[string]interface{}{
+			"name":                        "test",
+			"status":                      "up",
+			"storageErrorResumeBehaviour": "auto_resume",
+			"diskAttachments": []interface{}{
+				map[string]interface{}{
+					"id":              "d1",
+					"interface":       "ide",
+					"scsiReservation": true,
+					"disk": map[string]interface{}{
+						"storageType": "image",
+						"shared":      true,
+					},
+				},
+			},
+		})).To(gomega.ConsistOf(
+		"Unsupported disk interface type detected",
+		"SCSI reservation detected",
+		"Shared disk detected"))
+	openstack := "/v1/data/io/konveyor/forklift/openstack/validate"
+	g.Expect(labels(
+		openstack,
+		map[string]interface{}{
+			"name":    "test",
+			"status":  "ACTIVE",
+			"imageID": "",
+			"image": map[string]interface{}{
+				"disk_format": "qcow2",
+				"properties": map[string]interface{}{
+					"hw_disk_bus":  "virtio",
+					"hw_vif_model": "virtio",
+					"os_distro":    "rhel",
+					"os_version":   "9.2",
+				},
+			},
+			"flavor": map[string]interface{}{
+				"extraSpecs": map[string]interface{}{
+					"hw:watchdog_action": "reset",
+				},
+			},
+			"volumes": []interface{}{
+				map[string]interface{}{
+					"status":      "in-use",
+					"attachments": []interface{}{map[string]interface{}{}},
+				},
+			},
+		})).To(gomega.ConsistOf("Watchdog detected"))
+	// Errors.
+	_, _, err := Embedded.Validate(vmware, map[string]interface{}{})
+	g.Expect(err).ToNot(gomega.BeNil())
+	_, err = Embedded.Version("/v1/data/io/konveyor/forklift/unknown/rules_version")
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestEmbeddedFallback(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	endpoint := "/v1/data/io/konveyor/forklift/vmware/validate"
+	workload := map[string]interface{}{"name": "Test"}
+	client := &Client{}
+	// Disabled.
+	version, concerns, err := client.Validate(endpoint, workload)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(RuleSets["vmware"].Version))
+	g.Expect(concerns).To(gomega.HaveLen(1))
+	g.Expect(client.Fallback()).To(gomega.BeFalse())
+	// Client error.
+	status := http.StatusBadRequest
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+	defer server.Close()
+	Settings.PolicyAgent.URL = server.URL
+	defer func() {
+		Settings.PolicyAgent.URL = ""
+		availability.failed = time.Time{}
+	}()
+	_, _, err = client.Validate(endpoint, workload)
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(client.reachable()).To(gomega.BeTrue())
+	g.Expect(client.Fallback()).To(gomega.BeFalse())
+	// Unreachable.
+	status = http.StatusServiceUnavailable
+	version, concerns, err = client.Validate(endpoint, workload)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(RuleSets["vmware"].Version))
+	g.Expect(concerns).To(gomega.HaveLen(1))
+	g.Expect(client.reachable()).To(gomega.BeFalse())
+	g.Expect(client.Fallback()).To(gomega.BeTrue())
+	// Custom policies loaded.
+	// Not evaluated by the embedded engine so the VMs are
+	// (re)validated when the agent is reachable.
+	Custom.digest = 7
+	defer func() {
+		Custom.digest = 0
+	}()
+	version, err = client.Version(endpoint)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(RuleSets["vmware"].Version))
+	g.Expect(version).ToNot(gomega.Equal(Custom.Version(RuleSets["vmware"].Version)))
+}
+
+// Rego test fixtures.
+var (
+	// Test rule.
+	testRe = regexp.MustCompile(`(?ms)^(test_\w+)\s*\{(.*?)^\}`)
+	// Mocked input.
+	mockRe = regexp.MustCompile(`(?s)mock_vm\s*:=\s*(\{.*\})\s*results\s*:?=`)
+	// Expected count of concerns.
+	countRe = regexp.MustCompile(`count\(results\)\s*==\s*(\d+)`)
+	// Trailing comma.
+	commaRe = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// The embedded rules report the same number of concerns
+// as the Rego modules for each of the Rego test fixtures.
+func TestEmbeddedFixtures(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	root := filepath.Join("..", "..", "..", "..", "validation", "policies")
+	tested := 0
+	failed := []string{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(p, "_test.rego") {
+			return err
+		}
+		provider := filepath.Base(filepath.Dir(p))
+		rules, found := RuleSets[provider]
+		g.Expect(found).To(gomega.BeTrue(), provider)
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		for _, test := range testRe.FindAllStringSubmatch(string(b), -1) {
+			name := filepath.Base(p) + ":" + test[1]
+			mock := mockRe.FindStringSubmatch(test[2])
+			g.Expect(mock).ToNot(gomega.BeNil(), name)
+			count := countRe.FindStringSubmatch(test[2])
+			g.Expect(count).ToNot(gomega.BeNil(), name)
+			in := Document{}
+			err = json.Unmarshal([]byte(commaRe.ReplaceAllString(mock[1], "$1")), &in.value)
+			g.Expect(err).To(gomega.BeNil(), name)
+			expected, _ := strconv.Atoi(count[1])
+			concerns := rules.Evaluate(in)
+			if len(concerns) != expected {
+				failed = append(failed, fmt.Sprintf("%s: %d", name, len(concerns)))
+			}
+			tested++
+		}
+		return nil
+	})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(tested).To(gomega.BeNumerically(">", 0))
+	g.Expect(failed).To(gomega.BeEmpty())
+}
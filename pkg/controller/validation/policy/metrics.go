@@ -0,0 +1,26 @@
+package policy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// 'engine' - [ agent, embedded ]
+	// 'result' - [ succeeded, failed ]
+	validationCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_vm_validations_total",
+		Help: "VM validations sorted by policy engine and result",
+	},
+		[]string{"engine", "result"},
+	)
+)
+
+// Record a VM validation.
+func recordValidation(engine string, err error) {
+	result := "succeeded"
+	if err != nil {
+		result = "failed"
+	}
+	validationCounter.WithLabelValues(engine, result).Inc()
+}
@@ -0,0 +1,688 @@
+package policy
+
+import (
+	"fmt"
+)
+
+// Built-in rule sets by provider.
+// Ported from the Rego modules. Must be kept in sync
+// with the modules and the RULES_VERSION they declare.
+var RuleSets = map[string]*RuleSet{
+	"vmware":    &vmwareRules,
+	"ovirt":     &ovirtRules,
+	"openstack": &openstackRules,
+}
+
+// vSphere rules.
+var vmwareRules = RuleSet{
+	Version: 8,
+	Rules: []Rule{
+		{
+			Category:   "Warning",
+			Label:      "Changed Block Tracking (CBT) not enabled",
+			Assessment: "Changed Block Tracking (CBT) has not been enabled on this VM. This feature is a prerequisite for VM warm migration.",
+			Match: func(in Document) bool {
+				return in.Eq("changeTrackingEnabled", false)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "CPU affinity detected",
+			Assessment: "CPU affinity is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("cpuAffinity")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "CPU/Memory hotplug detected",
+			Assessment: "Hot pluggable CPU or memory is not currently supported by OpenShift Virtualization. Review CPU or memory configuration after migration.",
+			Match: func(in Document) bool {
+				return in.Eq("cpuHotAddEnabled", true) ||
+					in.Eq("cpuHotRemoveEnabled", true) ||
+					in.Eq("memoryHotAddEnabled", true)
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "vSphere DPM detected",
+			Assessment: "Distributed Power Management is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Truthy("host.cluster.dpmEnabled")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM running in a DRS-enabled cluster",
+			Assessment: "Distributed resource scheduling is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Truthy("host.cluster.drsEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Fault tolerance",
+			Assessment: "Fault tolerance is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Truthy("faultToleranceEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM running in HA-enabled cluster",
+			Assessment: "Host/Node HA is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Truthy("host.cluster.dasEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM-Host affinity detected",
+			Assessment: "VM-Host affinity is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Any("host.cluster.hostAffinityVms", func(vm Document) bool {
+					id, found := in.Get("id")
+					return found && vm.Eq("id", id)
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Memory ballooning detected",
+			Assessment: "Memory ballooning is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.Gt("balloonedMemory", 0)
+			},
+		},
+		nameRule(),
+		{
+			Category:   "Warning",
+			Label:      "NUMA node affinity detected",
+			Assessment: "NUMA node affinity is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("numaNodeAffinity")
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "Passthrough device detected",
+			Assessment: "SCSI or PCI passthrough devices are not currently supported by OpenShift Virtualization. The VM cannot be migrated unless the passthrough device is removed.",
+			Match: func(in Document) bool {
+				return in.Any("devices", func(d Document) bool {
+					return d.Eq("kind", "VirtualPCIPassthrough")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Raw Device Mapped disk detected",
+			Assessment: "RDM disks are migrated only when the plan copies the LUN contents into block PVCs or maps the LUN to a pre-provisioned block persistent volume. Otherwise, the VM cannot be migrated.",
+			Match: func(in Document) bool {
+				return in.Any("disks", func(d Document) bool {
+					return d.Truthy("rdm")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Shareable disk detected",
+			Assessment: "Shared disks are migrated once and attached to each VM in the plan that shares the disk. The disk is copied to a ReadWriteMany block volume. Ensure that the selected storage class supports it and that all of the VMs sharing the disk are included in the plan.",
+			Match: func(in Document) bool {
+				return in.Any("disks", func(d Document) bool {
+					return d.Truthy("shared")
+				})
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM snapshot detected",
+			Assessment: "Online snapshots are not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Eq("snapshot.kind", "VirtualMachineSnapshot")
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "SR-IOV passthrough adapter configuration detected",
+			Assessment: "SR-IOV passthrough adapter configuration is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("devices", func(d Document) bool {
+					return d.Eq("kind", "VirtualSriovEthernetCard")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "vTPM detected",
+			Assessment: "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration.",
+			Match: func(in Document) bool {
+				return in.Truthy("tpmEnabled")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "UEFI secure boot detected",
+			Assessment: "UEFI secure boot will be enabled on the migrated VM. The EFI variables (NVRAM) are copied to the persistent EFI variable store of the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The secure boot keys are copied unless re-enrolled by the plan.",
+			Match: func(in Document) bool {
+				return in.Eq("firmware", "efi") && in.Truthy("secureBoot")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "USB controller detected",
+			Assessment: "USB controllers are not currently supported by OpenShift Virtualization. The VM can be migrated but the devices attached to the USB controller will not be migrated.",
+			Match: func(in Document) bool {
+				return in.Any("devices", func(d Document) bool {
+					return d.Eq("kind", "VirtualUSBController")
+				})
+			},
+		},
+	},
+}
+
+// oVirt rules.
+var ovirtRules = RuleSet{
+	Version: 9,
+	Rules: []Rule{
+		{
+			Category:   "Information",
+			Label:      "VM has memory ballooning enabled",
+			Assessment: "The VM has memory ballooning enabled. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("balloonedMemory")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM has BIOS boot menu enabled",
+			Assessment: "The VM has a BIOS boot menu enabled. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("bootMenuEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported CPU pinning policy detected",
+			Assessment: "Resize and Pin NUMA and Isolated Threads are not supported by OpenShift Virtualization. Some functionality may be missing after the VM is migrated.",
+			Match: func(in Document) bool {
+				return in.Match("cpuPinningPolicy", `resize_and_pin_numa|isolate_threads`)
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM has CPU Shares Defined",
+			Assessment: "The VM has CPU shares defined. This functionality is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Gt("cpuShares", 0)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "CPU tuning detected",
+			Assessment: "CPU tuning other than 1 vCPU - 1 pCPU is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this feature in the target environment.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("cpuAffinity")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM custom properties detected",
+			Assessment: "The VM is configured with custom properties, which are not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("properties")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported disk interface type detected",
+			Assessment: "The disk interface type is not supported by OpenShift Virtualization (only sata, virtio_scsi and virtio interface types are currently supported). The migrated VM will be given a virtio disk interface type.",
+			Match: func(in Document) bool {
+				return in.CountOf("diskAttachments", func(d Document) bool {
+					return d.Match("interface", `sata|virtio_scsi|virtio`)
+				}) != ovirtDisks(in)
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "VM has an illegal or locked disk status condition",
+			Assessment: "One or more of the VM's disks has an illegal or locked status condition. The VM disk transfer is likely to fail.",
+			Match: func(in Document) bool {
+				return in.Any("diskAttachments", func(d Document) bool {
+					return d.Match("disk.status", `illegal|locked`)
+				})
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "Unsupported disk storage type detected",
+			Assessment: "The VM has a disk with a storage type other than 'image' or 'lun', which is not currently supported by OpenShift Virtualization. The VM disk transfer is likely to fail.",
+			Match: func(in Document) bool {
+				return in.CountOf("diskAttachments", func(d Document) bool {
+					return d.Eq("disk.storageType", "image") || d.Eq("disk.storageType", "lun")
+				}) != ovirtDisks(in)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Direct LUN disk detected",
+			Assessment: "Direct LUN disks are migrated only when the plan copies the LUN contents into block PVCs or maps the LUN to a pre-provisioned block persistent volume. Otherwise, the VM cannot be migrated.",
+			Match: func(in Document) bool {
+				return in.Any("diskAttachments", func(d Document) bool {
+					return d.Eq("disk.storageType", "lun")
+				})
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM Display Type",
+			Assessment: "The VM is using the SPICE protocol for video display. This is not supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Eq("display", "spice")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM configured as HA",
+			Assessment: "The VM is configured to be highly available. High availability is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("haEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Cluster has HA reservation",
+			Assessment: "The cluster running the source VM has a resource reservation to allow highly available VMs to be started. This feature is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("cluster.haReservation")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM has mapped host devices",
+			Assessment: "The VM is configured with hardware devices mapped from the host. This functionality is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("hostDevices")
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "Illegal disk images detected",
+			Assessment: "The VM has one or more snapshots with disks in ILLEGAL state, which is not currently supported by OpenShift Virtualization. The VM disk transfer is likely to fail.",
+			Match: func(in Document) bool {
+				return in.Truthy("hasIllegalImages")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "IO Threads configuration detected",
+			Assessment: "The VM is configured to use I/O threads. This configuration will not be automatically applied to the migrated VM, and must be manually re-applied if required.",
+			Match: func(in Document) bool {
+				return in.Gt("ioThreads", 1)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Cluster has KSM enabled",
+			Assessment: "The host running the source VM has kernel samepage merging enabled for more efficient memory utilization. This feature is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("cluster.ksmEnabled")
+			},
+		},
+		nameRule(),
+		{
+			Category:   "Warning",
+			Label:      "vNIC custom properties detected",
+			Assessment: "The VM's vNIC Profile is configured with custom properties, which are not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.NotEmpty("profile.properties")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported NIC interface type detected",
+			Assessment: "The NIC interface type is not supported by OpenShift Virtualization (only e1000, rtl8139 and virtio interface types are currently supported). The migrated VM will be given a virtio NIC interface type.",
+			Match: func(in Document) bool {
+				return in.CountOf("nics", func(n Document) bool {
+					return n.Match("interface", `e1000|rtl8139|virtio`)
+				}) != in.CountOf("nics", func(n Document) bool {
+					return n.Truthy("id")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "NIC with network filter detected",
+			Assessment: "The VM is using a vNIC Profile configured with a network filter. These are not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.Neq("profile.networkFilter", "")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "NIC with host device passthrough detected",
+			Assessment: "The VM is using a vNIC profile configured for host device passthrough, which is not currently supported by OpenShift Virtualization. The VM will be configured with an SRIOV NIC, but the destination network will need to be set up correctly.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.Match("interface", `pci_passthrough`)
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unplugged NIC detected",
+			Assessment: "The VM has a NIC that is unplugged from a network. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.Eq("plugged", false)
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "NIC with port mirroring detected",
+			Assessment: "The VM is using a vNIC Profile configured with port mirroring. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.Eq("profile.portMirroring", true)
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "NIC with QoS settings detected",
+			Assessment: "The VM has a vNIC Profile that includes Quality of Service settings. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("nics", func(n Document) bool {
+					return n.Neq("profile.qos", "")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "NUMA tuning detected",
+			Assessment: "NUMA tuning is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this NUMA mapping in the target environment.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("numaNodeAffinity")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Online (memory) snapshot detected",
+			Assessment: "The VM has a snapshot that contains a memory copy. Online snapshots such as this are not curently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Any("snapshots", func(s Document) bool {
+					return s.Truthy("persistMemory")
+				})
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Placement policy affinity",
+			Assessment: "The VM has a placement policy affinity setting that requires live migration to be enabled in OpenShift Virtualization for compatibility. The target storage classes must also support RWX access mode.",
+			Match: func(in Document) bool {
+				return in.Match("placementPolicyAffinity", `\bmigratable\b`)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "SCSI reservation detected",
+			Assessment: "The VM has a disk with SCSI reservation enabled. SCSI reservations are not migrated. The shared disk is attached to the target VMs without SCSI reservation support.",
+			Match: func(in Document) bool {
+				return in.Any("diskAttachments", func(d Document) bool {
+					return d.Eq("scsiReservation", true)
+				})
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "UEFI secure boot detected",
+			Assessment: "UEFI secure boot will be enabled on the migrated VM. The EFI variables (NVRAM) are not migrated, so the VM starts with the default secure boot keys enrolled. Custom keys or boot entries must be enrolled again after migration.",
+			Match: func(in Document) bool {
+				return in.Eq("bios", "q35_secure_boot")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Shared disk detected",
+			Assessment: "The VM has a disk that is shared. Shared disks are migrated once and attached to each VM in the plan that shares the disk. The disk is copied to a ReadWriteMany block volume. Ensure that the selected storage class supports it and that all of the VMs sharing the disk are included in the plan.",
+			Match: func(in Document) bool {
+				return in.Any("diskAttachments", func(d Document) bool {
+					return d.Eq("disk.shared", true)
+				})
+			},
+		},
+		{
+			Category: "Information",
+			Label:    "VM storage error resume behavior",
+			Assess: func(in Document) string {
+				v, _ := in.Get("storageErrorResumeBehaviour")
+				return fmt.Sprintf(
+					"The VM has storage error resume behavior set to '%v', which is not currently supported by OpenShift Virtualization",
+					v)
+			},
+			Match: func(in Document) bool {
+				return in.Neq("storageErrorResumeBehaviour", "auto_resume")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "vTPM detected",
+			Assessment: "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration.",
+			Match: func(in Document) bool {
+				return in.Truthy("tpmEnabled")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "USB support enabled",
+			Assessment: "The VM has USB support enabled, but USB device attachment is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Truthy("usbEnabled")
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "VM has a status condition that may prevent successful migration",
+			Assessment: "The VM's status is not 'up' or 'down'. Attempting to migrate this VM may fail.",
+			Match: func(in Document) bool {
+				return in.IsString("status") && !in.Match("status", `up|down`)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Watchdog detected",
+			Assessment: "The VM is configured with a watchdog device, which is not currently supported by OpenShift Virtualization. A watchdog device will not be present in the destination VM.",
+			Match: func(in Document) bool {
+				return in.NotEmpty("watchDogs")
+			},
+		},
+	},
+}
+
+// OpenStack rules.
+var openstackRules = RuleSet{
+	Version: 7,
+	Rules: []Rule{
+		{
+			Category:   "Information",
+			Label:      "VM has BIOS boot menu enabled",
+			Assessment: "The VM has a BIOS boot menu enabled. This is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.Eq("image.properties.hw_boot_menu", "true")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "VM has CPU Shares Defined",
+			Assessment: "The VM has CPU shares defined. This functionality is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.HasKey("flavor.extraSpecs", "quota:cpu_shares")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported disk interface type detected",
+			Assessment: "The disk interface type is not supported by OpenShift Virtualization (only sata, scsi and virtio interface types are currently supported). The migrated VM will be given a virtio disk interface type.",
+			Match: func(in Document) bool {
+				return in.IsString("image.properties.hw_disk_bus") && !in.Match("image.properties.hw_disk_bus", `sata|scsi|virtio`)
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "VM has one or more disks with an unsupported status",
+			Assessment: "One or more of the VM's disks has an unsupported status condition. The VM disk transfer is likely to fail.",
+			Match: func(in Document) bool {
+				return func() bool {
+					n, found := in.Count("volumes")
+					return found && in.CountOf("volumes", func(v Document) bool {
+						return v.Match("status", `available|in-use`)
+					}) != n
+				}()
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "VM has mapped host devices",
+			Assessment: "The VM is configured with hardware devices mapped from the host. This functionality is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.HasKey("flavor.extraSpecs", "pci_passthrough:alias")
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "Unsupported image format detected",
+			Assessment: "The VM image has a format other than 'qcow2' or 'raw', which is not currently supported by OpenShift Virtualization. The VM disk transfer is likely to fail.",
+			Match: func(in Document) bool {
+				return in.IsString("image.disk_format") && !in.Match("image.disk_format", `qcow2|raw`)
+			},
+		},
+		nameRule(),
+		{
+			Category:   "Warning",
+			Label:      "NUMA tuning detected",
+			Assessment: "NUMA tuning is not currently supported by OpenShift Virtualization. The VM can be migrated but it will not have this NUMA mapping in the target environment.",
+			Match: func(in Document) bool {
+				return in.HasKey("flavor.extraSpecs", "hw:pci_numa_affinity_policy") ||
+					in.HasKey("flavor.extraSpecs", "hw:numa_nodes")
+			},
+		},
+		{
+			Category:   "Information",
+			Label:      "UEFI secure boot detected",
+			Assessment: "UEFI secure boot will be enabled on the migrated VM. The EFI variables (NVRAM) are not migrated, so the VM starts with the default secure boot keys enrolled. Custom keys or boot entries must be enrolled again after migration.",
+			Match: func(in Document) bool {
+				return in.Eq("image.properties.os_secure_boot", "required") ||
+					in.Eq("flavor.extraSpecs.os:secure_boot", "required")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Shared disk detected",
+			Assessment: "The VM has a disk that is shared. Shared disks are not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return func() bool {
+					n, found := in.Count("volumes")
+					return found && in.CountOf("volumes", func(v Document) bool {
+						n, _ := v.Count("attachments")
+						return n == 1
+					}) != n
+				}()
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "vTPM detected",
+			Assessment: "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration.",
+			Match: func(in Document) bool {
+				return in.HasKey("flavor.extraSpecs", "hw:tpm_version") ||
+					in.HasKey("image.properties", "hw_tpm_version")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported VIF model detected",
+			Assessment: "The VIF model is not supported by OpenShift Virtualization (only e1000, e1000e, rtl8139, ne2k_pci, pcnet and virtio VIF models are currently supported). The migrated VM will be given a virtio VIF model.",
+			Match: func(in Document) bool {
+				return in.IsString("image.properties.hw_vif_model") && !in.Match("image.properties.hw_vif_model", `e1000|e1000e|rtl8139|virtio|ne2k_pci|pcnet`)
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "VM is 'Image' based",
+			Assessment: "The VM is 'Image' based which is not currently supported. Only the migration of 'Volume' based VMs is supported.",
+			Match: func(in Document) bool {
+				return in.Neq("imageID", "")
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Unsupported operative system detected",
+			Assessment: "The VM is running an operative system that is not currently supported by OpenShift Virtualization.",
+			Match: func(in Document) bool {
+				return in.HasKey("image.properties", "os_distro") &&
+					in.HasKey("image.properties", "os_version") &&
+					!openstackGuestSupported(in)
+			},
+		},
+		{
+			Category:   "Critical",
+			Label:      "VM has a status condition that may prevent successful migration",
+			Assessment: "The VM's status is not 'ACTIVE' or 'SHUTOFF'. Attempting to migrate this VM may fail.",
+			Match: func(in Document) bool {
+				return in.IsString("status") && !in.Match("status", `ACTIVE|SHUTOFF`)
+			},
+		},
+		{
+			Category:   "Warning",
+			Label:      "Watchdog detected",
+			Assessment: "The VM is configured with a watchdog device, which is not currently supported by OpenShift Virtualization. A watchdog device will not be present in the destination VM.",
+			Match: func(in Document) bool {
+				return in.HasKey("flavor.extraSpecs", "hw:watchdog_action") ||
+					in.Truthy("image.properties.hw_watchdog_action")
+			},
+		},
+	},
+}
+
+// VM name rule (all providers).
+func nameRule() Rule {
+	return Rule{
+		Category:   "Warning",
+		Label:      "Invalid VM Name",
+		Assessment: "The VM name must comply with the DNS subdomain name format defined in RFC 1123. The name can contain lowercase letters (a-z), numbers (0-9), and hyphens (-), up to a maximum of 64 characters. The first and last characters must be alphanumeric. The name must not contain uppercase letters, spaces, periods (.), or special characters. The VM will be renamed automatically during the migration to meet the RFC convention.",
+		Match: func(in Document) bool {
+			n, _ := in.Count("name")
+			return in.IsString("name") &&
+				(!in.Match("name", `^[a-z0-9][a-z0-9-]*[a-z0-9]$`) || n >= 64)
+		},
+	}
+}
+
+// Number of oVirt disk attachments.
+func ovirtDisks(in Document) int {
+	return in.CountOf("diskAttachments", func(d Document) bool {
+		return d.Truthy("id")
+	})
+}
+
+// The OpenStack image guest OS is supported.
+func openstackGuestSupported(in Document) bool {
+	distro := "image.properties.os_distro"
+	version := "image.properties.os_version"
+	switch {
+	case in.Match(distro, `rhel|centos`) &&
+		in.Match(version, `^9|^8|^7`):
+		return true
+	case in.Match(distro, `windows`) &&
+		in.Match(version, `2008|2012|2016|2019|2022|2k8|2k12|2k16|2k19|2k22|^7|^8|^10|^11`):
+		return true
+	case in.Match(distro, `fedora`) &&
+		in.Match(version, `^3[678]$`):
+		return true
+	}
+	return false
+}
@@ -10,3 +10,11 @@ import (
 func ServeSecretMutator(resp http.ResponseWriter, req *http.Request) {
 	mutating_webhooks.Serve(resp, req, &mutators.SecretMutator{})
 }
+
+func ServeMigrationMutator(resp http.ResponseWriter, req *http.Request) {
+	mutating_webhooks.Serve(resp, req, &mutators.MigrationMutator{})
+}
+
+func ServePlanMutator(resp http.ResponseWriter, req *http.Request) {
+	mutating_webhooks.Serve(resp, req, &mutators.PlanMutator{})
+}
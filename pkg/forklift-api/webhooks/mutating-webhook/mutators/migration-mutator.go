@@ -0,0 +1,51 @@
+package mutators
+
+import (
+	"encoding/json"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+// Annotates the migration with the user that created it.
+type MigrationMutator struct {
+}
+
+func (mutator *MigrationMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("migration mutator was called")
+	if ar.Request.Operation != admissionv1.Create {
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	mg := &api.Migration{}
+	err := json.Unmarshal(ar.Request.Object.Raw, mg)
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to unmarshal migration")
+		return util.ToAdmissionResponseError(err)
+	}
+	annotations := map[string]string{}
+	for k, v := range mg.Annotations {
+		annotations[k] = v
+	}
+	annotations[api.AnnCreatedBy] = ar.Request.UserInfo.Username
+	patchBytes, err := util.GeneratePatchPayload(
+		util.PatchOperation{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: annotations,
+		},
+	)
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to generate payload for patch request")
+		return util.ToAdmissionResponseError(err)
+	}
+
+	jsonPatchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patchBytes,
+		PatchType: &jsonPatchType,
+	}
+}
@@ -0,0 +1,69 @@
+package mutators
+
+import (
+	"encoding/json"
+	"reflect"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+// Annotates the plan with the user that created or
+// last updated the plan spec. The inventory scope of
+// the user is resolved when the plan is validated.
+type PlanMutator struct {
+}
+
+func (mutator *PlanMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("plan mutator was called")
+	plan := &api.Plan{}
+	err := json.Unmarshal(ar.Request.Object.Raw, plan)
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to unmarshal plan")
+		return util.ToAdmissionResponseError(err)
+	}
+	annotations := map[string]string{}
+	for k, v := range plan.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, api.AnnRequester)
+	specChanged := true
+	if ar.Request.Operation == admissionv1.Update {
+		old := &api.Plan{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, old)
+		if err == nil && reflect.DeepEqual(old.Spec, plan.Spec) {
+			specChanged = false
+			// The requester may only be set by the webhook.
+			if s, found := old.Annotations[api.AnnRequester]; found {
+				annotations[api.AnnRequester] = s
+			}
+		}
+	}
+	if specChanged {
+		b, err := json.Marshal(&ar.Request.UserInfo)
+		if err != nil {
+			log.Error(err, "mutating webhook error, failed to marshal the requester")
+			return util.ToAdmissionResponseError(err)
+		}
+		annotations[api.AnnRequester] = string(b)
+	}
+	patchBytes, err := util.GeneratePatchPayload(
+		util.PatchOperation{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: annotations,
+		},
+	)
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to generate payload for patch request")
+		return util.ToAdmissionResponseError(err)
+	}
+
+	jsonPatchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patchBytes,
+		PatchType: &jsonPatchType,
+	}
+}
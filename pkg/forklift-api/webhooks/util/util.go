@@ -6,9 +6,14 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/konveyor/forklift-controller/pkg/apis"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	admissionv1 "k8s.io/api/admission/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type PatchOperation struct {
@@ -94,3 +99,78 @@ func ToAdmissionResponseAllow() *admissionv1.AdmissionResponse {
 		Allowed: true,
 	}
 }
+
+// ToAdmissionResponseDeny returns a denied response
+// that reports the specified causes.
+func ToAdmissionResponseDeny(causes []v1.StatusCause) *admissionv1.AdmissionResponse {
+	message := ""
+	for _, cause := range causes {
+		if message == "" {
+			message = cause.Message
+		} else {
+			message = fmt.Sprintf("%s, %s", message, cause.Message)
+		}
+	}
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &v1.Status{
+			Message: message,
+			Reason:  v1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+			Details: &v1.StatusDetails{
+				Causes: causes,
+			},
+		},
+	}
+}
+
+// ToAdmissionResponseConditions returns a denied response when
+// the conditions include critical conditions. Conditions with
+// the ignored types (such as those reporting that a referenced
+// resource has not been reconciled yet) do not deny admission.
+func ToAdmissionResponseConditions(conditions *libcnd.Conditions, ignored ...string) *admissionv1.AdmissionResponse {
+	ignoredSet := map[string]bool{}
+	for _, t := range ignored {
+		ignoredSet[t] = true
+	}
+	causes := []v1.StatusCause{}
+	for _, cnd := range conditions.List {
+		if cnd.Category != libcnd.Critical || ignoredSet[cnd.Type] {
+			continue
+		}
+		message := cnd.Message
+		if len(cnd.Items) > 0 {
+			message = fmt.Sprintf("%s %v", message, cnd.Items)
+		}
+		causes = append(
+			causes,
+			v1.StatusCause{
+				Type:    v1.CauseType(cnd.Type),
+				Message: message,
+			})
+	}
+	if len(causes) > 0 {
+		return ToAdmissionResponseDeny(causes)
+	}
+
+	return ToAdmissionResponseAllow()
+}
+
+// Build a cluster client using the in-cluster configuration.
+// The scheme includes the forklift API.
+func GetClient() (cl client.Client, err error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return
+	}
+	err = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+	if err != nil {
+		return
+	}
+	err = apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		return
+	}
+	cl, err = client.New(config, client.Options{Scheme: scheme.Scheme})
+	return
+}
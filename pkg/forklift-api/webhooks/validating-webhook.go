@@ -14,3 +14,19 @@ func ServeSecretCreate(resp http.ResponseWriter, req *http.Request) {
 func ServePlanCreate(resp http.ResponseWriter, req *http.Request) {
 	validating_webhooks.Serve(resp, req, &admitters.PlanAdmitter{})
 }
+
+func ServeNetworkMapCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.NetworkMapAdmitter{})
+}
+
+func ServeStorageMapCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.StorageMapAdmitter{})
+}
+
+func ServeHookCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.HookAdmitter{})
+}
+
+func ServeMigrationCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.MigrationAdmitter{})
+}
@@ -0,0 +1,40 @@
+package admitters
+
+import (
+	"encoding/json"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/hook"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+type HookAdmitter struct {
+}
+
+func (admitter *HookAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("Hook admitter was called")
+	raw := ar.Request.Object.Raw
+
+	hk := &api.Hook{}
+	err := json.Unmarshal(raw, hk)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	// The status is not part of the request.
+	hk.Status = api.HookStatus{}
+	err = hook.Validate(hk)
+	if err != nil {
+		log.Error(err, "Couldn't validate the hook", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+	response := util.ToAdmissionResponseConditions(&hk.Status.Conditions)
+	if !response.Allowed {
+		log.Info("Hook validation failed, failing", "message", response.Result.Message)
+		return response
+	}
+
+	log.Info("Passed hook validation")
+	return response
+}
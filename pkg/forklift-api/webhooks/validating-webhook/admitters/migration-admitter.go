@@ -0,0 +1,75 @@
+package admitters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/migration"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MigrationAdmitter struct {
+}
+
+func (admitter *MigrationAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("Migration admitter was called")
+	raw := ar.Request.Object.Raw
+
+	mg := &api.Migration{}
+	err := json.Unmarshal(raw, mg)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1.Update {
+		old := &api.Migration{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, old)
+		if err != nil {
+			return util.ToAdmissionResponseError(err)
+		}
+		// The creator is set by the mutating webhook on create and is immutable.
+		if old.Annotations[api.AnnCreatedBy] != mg.Annotations[api.AnnCreatedBy] {
+			log.Info("Migration creator changed, failing", "user", ar.Request.UserInfo.Username)
+			return &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Code: http.StatusForbidden,
+					Message: fmt.Sprintf(
+						"The %q annotation cannot be changed.",
+						api.AnnCreatedBy),
+				},
+			}
+		}
+		if reflect.DeepEqual(old.Spec, mg.Spec) {
+			log.Info("Migration spec not changed, passing")
+			return util.ToAdmissionResponseAllow()
+		}
+	}
+
+	cl, err := util.GetClient()
+	if err != nil {
+		log.Error(err, "Couldn't create a cluster client", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+
+	// The status is not part of the request.
+	mg.Status = api.MigrationStatus{}
+	err = migration.Validate(cl, mg)
+	if err != nil {
+		log.Error(err, "Couldn't validate the migration", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+	response := util.ToAdmissionResponseConditions(&mg.Status.Conditions)
+	if !response.Allowed {
+		log.Info("Migration validation failed, failing", "message", response.Result.Message)
+		return response
+	}
+
+	log.Info("Passed migration validation")
+	return response
+}
@@ -0,0 +1,60 @@
+package admitters
+
+import (
+	"encoding/json"
+	"reflect"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/map/network"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+type NetworkMapAdmitter struct {
+}
+
+func (admitter *NetworkMapAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("NetworkMap admitter was called")
+	raw := ar.Request.Object.Raw
+
+	mp := &api.NetworkMap{}
+	err := json.Unmarshal(raw, mp)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1.Update {
+		old := &api.NetworkMap{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, old)
+		if err == nil && reflect.DeepEqual(old.Spec, mp.Spec) {
+			log.Info("NetworkMap spec not changed, passing")
+			return util.ToAdmissionResponseAllow()
+		}
+	}
+
+	cl, err := util.GetClient()
+	if err != nil {
+		log.Error(err, "Couldn't create a cluster client", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+
+	// The status is not part of the request.
+	mp.Status = api.NetworkMapStatus{}
+	err = network.Validate(cl, mp)
+	if err != nil {
+		log.Error(err, "Couldn't validate the network map", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+	response := util.ToAdmissionResponseConditions(
+		&mp.Status.Conditions,
+		validation.SourceProviderNotReady,
+		validation.DestinationProviderNotReady)
+	if !response.Allowed {
+		log.Info("NetworkMap validation failed, failing", "message", response.Result.Message)
+		return response
+	}
+
+	log.Info("Passed network map validation")
+	return response
+}
@@ -2,22 +2,32 @@ package admitters
 
 import (
 	"context"
-	v1 "k8s.io/api/storage/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-
 	"encoding/json"
 	"fmt"
-	admissionv1 "k8s.io/api/admission/v1beta1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/rest"
 	"net/http"
+	"reflect"
 
-	"github.com/konveyor/forklift-controller/pkg/apis"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planctl "github.com/konveyor/forklift-controller/pkg/controller/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	auth "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Plan conditions that do not deny admission.
+// The referenced resources may not have been reconciled yet.
+var planNotReady = []string{
+	validation.SourceProviderNotReady,
+	validation.DestinationProviderNotReady,
+	planctl.NetMapNotReady,
+	planctl.DsMapNotReady,
+	planctl.HookNotReady,
+}
+
 type PlanAdmitter struct {
 }
 
@@ -31,71 +41,135 @@ func (admitter *PlanAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv
 		return util.ToAdmissionResponseError(err)
 	}
 
-	if plan.Spec.Warm {
-		log.Info("Warm migration supports all storages, passing")
-		return util.ToAdmissionResponseAllow()
+	if ar.Request.Operation == admissionv1.Update {
+		old := &api.Plan{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, old)
+		if err == nil && reflect.DeepEqual(old.Spec, plan.Spec) {
+			log.Info("Plan spec not changed, passing")
+			return util.ToAdmissionResponseAllow()
+		}
 	}
 
-	config, err := rest.InClusterConfig()
+	cl, err := util.GetClient()
 	if err != nil {
-		log.Error(err, "Couldn't get the cluster configuration", err.Error())
+		log.Error(err, "Couldn't create a cluster client", err.Error())
 		return util.ToAdmissionResponseError(err)
 	}
 
-	err = api.SchemeBuilder.AddToScheme(scheme.Scheme)
-	if err != nil {
-		log.Error(err, "Couldn't build the scheme", err.Error())
-		return util.ToAdmissionResponseError(err)
+	// The status is not part of the request. The VMs
+	// selected by the selector (recorded in the status
+	// by the controller) are validated with the listed VMs.
+	plan.Status = api.PlanStatus{
+		SelectedVMs: plan.Status.SelectedVMs,
 	}
-	err = apis.AddToScheme(scheme.Scheme)
+	err = planctl.Validate(cl, plan)
 	if err != nil {
-		log.Error(err, "Couldn't add forklift API to the scheme", err.Error())
+		log.Error(err, "Couldn't validate the plan", err.Error())
 		return util.ToAdmissionResponseError(err)
 	}
+	response := util.ToAdmissionResponseConditions(&plan.Status.Conditions, planNotReady...)
+	if !response.Allowed {
+		log.Info("Plan validation failed, failing", "message", response.Result.Message)
+		return response
+	}
 
-	cl, err := client.New(config, client.Options{Scheme: scheme.Scheme})
-	if err != nil {
-		log.Error(err, "Couldn't create a cluster client", err.Error())
-		return util.ToAdmissionResponseError(err)
+	response = admitter.validateTargetNamespace(cl, ar, plan)
+	if !response.Allowed {
+		return response
 	}
 
-	sourceProvider := api.Provider{}
-	err = cl.Get(context.TODO(), client.ObjectKey{Namespace: plan.Spec.Provider.Source.Namespace, Name: plan.Spec.Provider.Source.Name}, &sourceProvider)
+	return admitter.validateStorage(cl, plan)
+}
+
+// Validate that the requesting user may create
+// virtual machines in the target namespace.
+func (admitter *PlanAdmitter) validateTargetNamespace(cl client.Client, ar *admissionv1.AdmissionReview, plan *api.Plan) *admissionv1.AdmissionResponse {
+	destination := plan.Referenced.Provider.Destination
+	if destination == nil || !destination.IsHost() {
+		log.Info("Migration to a remote provider, skipping target namespace validation")
+		return util.ToAdmissionResponseAllow()
+	}
+	user := ar.Request.UserInfo
+	extra := map[string]auth.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			auth.ExtraValue{},
+			v...)
+	}
+	review := &auth.SubjectAccessReview{
+		Spec: auth.SubjectAccessReviewSpec{
+			ResourceAttributes: &auth.ResourceAttributes{
+				Group:     "kubevirt.io",
+				Resource:  "virtualmachines",
+				Namespace: plan.Spec.TargetNamespace,
+				Verb:      "create",
+			},
+			Extra:  extra,
+			Groups: user.Groups,
+			User:   user.Username,
+			UID:    user.UID,
+		},
+	}
+	err := cl.Create(context.TODO(), review)
 	if err != nil {
-		log.Error(err, "Couldn't get the source provider", err.Error())
+		log.Error(err, "Couldn't review the user access to the target namespace", err.Error())
 		return util.ToAdmissionResponseError(err)
 	}
+	if !review.Status.Allowed {
+		log.Info("Target namespace is not permitted, failing", "user", user.Username)
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code: http.StatusForbidden,
+				Message: fmt.Sprintf(
+					"User %q cannot create virtual machines in the target namespace %q.",
+					user.Username,
+					plan.Spec.TargetNamespace),
+			},
+		}
+	}
+
+	return util.ToAdmissionResponseAllow()
+}
+
+// Validate that the destination storage classes support
+// dynamic provisioning when required by the migration.
+func (admitter *PlanAdmitter) validateStorage(cl client.Client, plan *api.Plan) *admissionv1.AdmissionResponse {
+	if plan.Spec.Warm {
+		log.Info("Warm migration supports all storages, passing")
+		return util.ToAdmissionResponseAllow()
+	}
+
+	sourceProvider := plan.Referenced.Provider.Source
+	if sourceProvider == nil {
+		log.Info("Source provider not found, passing")
+		return util.ToAdmissionResponseAllow()
+	}
 
 	if sourceProvider.Type() == api.VSphere {
 		log.Info("Provider supports all storages, passing")
 		return util.ToAdmissionResponseAllow()
 	}
 
-	destinationProvider := api.Provider{}
-	err = cl.Get(context.TODO(), client.ObjectKey{Namespace: plan.Spec.Provider.Destination.Namespace, Name: plan.Spec.Provider.Destination.Name}, &destinationProvider)
-	if err != nil {
-		log.Error(err, "Couldn't get the destination provider", err.Error())
-		return util.ToAdmissionResponseError(err)
+	destinationProvider := plan.Referenced.Provider.Destination
+	if destinationProvider == nil || !destinationProvider.IsHost() {
+		log.Info("Migration to a remote provider supports all storages, passing")
+		return util.ToAdmissionResponseAllow()
 	}
 
-	if !destinationProvider.IsHost() {
-		log.Info("Migration to a remote provider supports all storages, passing")
+	storageMap := plan.Referenced.Map.Storage
+	if storageMap == nil {
+		log.Info("Storage map not found, passing")
 		return util.ToAdmissionResponseAllow()
 	}
 
 	storageClasses := v1.StorageClassList{}
-	err = cl.List(context.TODO(), &storageClasses, &client.ListOptions{})
+	err := cl.List(context.TODO(), &storageClasses, &client.ListOptions{})
 	if err != nil {
 		log.Error(err, "Couldn't get the cluster storage classes", err.Error())
 		return util.ToAdmissionResponseError(err)
 	}
 
-	storageMap := api.StorageMap{}
-	err = cl.Get(context.TODO(), client.ObjectKey{Namespace: plan.Spec.Map.Storage.Namespace, Name: plan.Spec.Map.Storage.Name}, &storageMap)
-	if err != nil {
-		log.Error(err, "Couldn't get the storage map", err.Error())
-		return util.ToAdmissionResponseError(err)
-	}
 	storagePairList := storageMap.Spec.Map
 	var badStorageClasses []string
 	for _, storagePair := range storagePairList {
@@ -0,0 +1,60 @@
+package admitters
+
+import (
+	"encoding/json"
+	"reflect"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/map/storage"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+type StorageMapAdmitter struct {
+}
+
+func (admitter *StorageMapAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("StorageMap admitter was called")
+	raw := ar.Request.Object.Raw
+
+	mp := &api.StorageMap{}
+	err := json.Unmarshal(raw, mp)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	if ar.Request.Operation == admissionv1.Update {
+		old := &api.StorageMap{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, old)
+		if err == nil && reflect.DeepEqual(old.Spec, mp.Spec) {
+			log.Info("StorageMap spec not changed, passing")
+			return util.ToAdmissionResponseAllow()
+		}
+	}
+
+	cl, err := util.GetClient()
+	if err != nil {
+		log.Error(err, "Couldn't create a cluster client", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+
+	// The status is not part of the request.
+	mp.Status = api.MapStatus{}
+	err = storage.Validate(cl, mp)
+	if err != nil {
+		log.Error(err, "Couldn't validate the storage map", err.Error())
+		return util.ToAdmissionResponseError(err)
+	}
+	response := util.ToAdmissionResponseConditions(
+		&mp.Status.Conditions,
+		validation.SourceProviderNotReady,
+		validation.DestinationProviderNotReady)
+	if !response.Allowed {
+		log.Info("StorageMap validation failed, failing", "message", response.Result.Message)
+		return response
+	}
+
+	log.Info("Passed storage map validation")
+	return response
+}
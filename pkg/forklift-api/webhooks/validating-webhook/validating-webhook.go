@@ -2,7 +2,6 @@ package validating_webhook
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1beta1"
@@ -35,25 +34,7 @@ func NewAdmissionResponse(causes []v1.StatusCause) *admissionv1.AdmissionRespons
 		return NewPassingAdmissionResponse()
 	}
 
-	globalMessage := ""
-	for _, cause := range causes {
-		if globalMessage == "" {
-			globalMessage = cause.Message
-		} else {
-			globalMessage = fmt.Sprintf("%s, %s", globalMessage, cause.Message)
-		}
-	}
-
-	return &admissionv1.AdmissionResponse{
-		Result: &v1.Status{
-			Message: globalMessage,
-			Reason:  v1.StatusReasonInvalid,
-			Code:    http.StatusUnprocessableEntity,
-			Details: &v1.StatusDetails{
-				Causes: causes,
-			},
-		},
-	}
+	return util.ToAdmissionResponseDeny(causes)
 }
 
 func Serve(resp http.ResponseWriter, req *http.Request, admitter Admitter) {
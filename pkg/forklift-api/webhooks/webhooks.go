@@ -11,7 +11,13 @@ var log = logging.WithName("webhooks")
 
 const SecretValidatePath = "/secret-validate"
 const SecretMutatorPath = "/secret-mutate"
+const MigrationMutatorPath = "/migration-mutate"
+const PlanMutatorPath = "/plan-mutate"
 const PlanValidatePath = "/plan-validate"
+const NetworkMapValidatePath = "/networkmap-validate"
+const StorageMapValidatePath = "/storagemap-validate"
+const HookValidatePath = "/hook-validate"
+const MigrationValidatePath = "/migration-validate"
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
 var AddToManagerFuncs []func(manager.Manager) error
@@ -34,6 +40,18 @@ func RegisterValidatingWebhooks(mux *http.ServeMux) {
 	mux.HandleFunc(PlanValidatePath, func(w http.ResponseWriter, r *http.Request) {
 		ServePlanCreate(w, r)
 	})
+	mux.HandleFunc(NetworkMapValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeNetworkMapCreate(w, r)
+	})
+	mux.HandleFunc(StorageMapValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeStorageMapCreate(w, r)
+	})
+	mux.HandleFunc(HookValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeHookCreate(w, r)
+	})
+	mux.HandleFunc(MigrationValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeMigrationCreate(w, r)
+	})
 }
 
 func RegisterMutatingWebhooks(mux *http.ServeMux) {
@@ -41,4 +59,10 @@ func RegisterMutatingWebhooks(mux *http.ServeMux) {
 	mux.HandleFunc(SecretMutatorPath, func(w http.ResponseWriter, r *http.Request) {
 		ServeSecretMutator(w, r)
 	})
+	mux.HandleFunc(MigrationMutatorPath, func(w http.ResponseWriter, r *http.Request) {
+		ServeMigrationMutator(w, r)
+	})
+	mux.HandleFunc(PlanMutatorPath, func(w http.ResponseWriter, r *http.Request) {
+		ServePlanMutator(w, r)
+	})
 }
@@ -0,0 +1,5 @@
+/*
+Provides reading and writing of the (edk2) EFI variable
+store found in firmware variable (NVRAM) images.
+*/
+package efi
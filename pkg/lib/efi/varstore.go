@@ -0,0 +1,390 @@
+package efi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Vendor GUIDs.
+var (
+	// EFI global variables (boot entries, PK, KEK).
+	GlobalVariable = MustParseGUID("8be4df61-93ca-11d2-aa0d-00e098032b8c")
+	// Image security databases (db, dbx, dbt, dbr).
+	ImageSecurityDatabase = MustParseGUID("d719b2cb-3d3a-4596-a3bc-dad00e67656f")
+	// Shim (MOK) variables.
+	ShimLock = MustParseGUID("605dab50-e046-4300-abb6-3dd810dd8b23")
+	// Microsoft (Windows boot manager) variables.
+	Microsoft = MustParseGUID("77fa9abd-0359-4d32-bd60-28f4e78f784b")
+)
+
+// Variable store signatures.
+var (
+	// Authenticated variable store (edk2).
+	authenticatedStore = MustParseGUID("aaf32c78-947b-439a-a180-2e144ec37792")
+	// Variable store (edk2).
+	variableStore = MustParseGUID("ddcf3616-3275-4164-98b6-fe85707ffe7d")
+	// Variable store (framework).
+	vssSignature = []byte("$VSS")
+)
+
+// Variable store layout.
+const (
+	// Store header length (GUID signature).
+	StoreHeaderLength = 28
+	// Store header length ($VSS signature).
+	VssHeaderLength = 16
+	// Variable header length.
+	VariableHeaderLength = 32
+	// Authenticated variable header length.
+	AuthVariableHeaderLength = 60
+	// Store formatted.
+	StoreFormatted = 0x5a
+	// Store healthy.
+	StoreHealthy = 0xfe
+	// Variable start ID.
+	StartID = 0x55aa
+	// Variable added.
+	VarAdded = 0x3f
+	// Variable added and in deleted transition.
+	VarInDeletedTransition = 0x3e
+	// Erased byte.
+	erased = 0xff
+)
+
+// GUID as stored in the firmware (mixed-endian).
+type GUID [16]byte
+
+// Parse a GUID.
+// Format: xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func ParseGUID(s string) (g GUID, err error) {
+	fields := strings.Split(s, "-")
+	if len(fields) != 5 ||
+		len(fields[0]) != 8 ||
+		len(fields[1]) != 4 ||
+		len(fields[2]) != 4 ||
+		len(fields[3]) != 4 ||
+		len(fields[4]) != 12 {
+		err = liberr.New("GUID not valid.", "guid", s)
+		return
+	}
+	b, err := hex.DecodeString(strings.Join(fields, ""))
+	if err != nil {
+		err = liberr.Wrap(err, "guid", s)
+		return
+	}
+	binary.LittleEndian.PutUint32(g[0:], binary.BigEndian.Uint32(b[0:]))
+	binary.LittleEndian.PutUint16(g[4:], binary.BigEndian.Uint16(b[4:]))
+	binary.LittleEndian.PutUint16(g[6:], binary.BigEndian.Uint16(b[6:]))
+	copy(g[8:], b[8:])
+	return
+}
+
+// Parse a GUID.
+// Panics when not valid.
+func MustParseGUID(s string) (g GUID) {
+	g, err := ParseGUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// String representation.
+func (g GUID) String() string {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint32(b[0:], binary.LittleEndian.Uint32(g[0:]))
+	binary.BigEndian.PutUint16(b[4:], binary.LittleEndian.Uint16(g[4:]))
+	binary.BigEndian.PutUint16(b[6:], binary.LittleEndian.Uint16(g[6:]))
+	copy(b[8:], g[8:])
+	s := hex.EncodeToString(b)
+	return strings.Join(
+		[]string{s[0:8], s[8:12], s[12:16], s[16:20], s[20:32]},
+		"-")
+}
+
+// EFI variable.
+type Variable struct {
+	// Name.
+	Name string
+	// Vendor GUID.
+	GUID GUID
+	// Attributes.
+	Attributes uint32
+	// Monotonic count (authenticated).
+	MonotonicCount uint64
+	// Time stamp (authenticated).
+	TimeStamp [16]byte
+	// Public key index (authenticated).
+	PubKeyIndex uint32
+	// Data.
+	Data []byte
+}
+
+// Variable store found in a firmware (NVRAM) image.
+type Store struct {
+	// Variables.
+	Variables []Variable
+	// Authenticated variable headers.
+	Authenticated bool
+	// Firmware image.
+	image []byte
+	// Offset of the store in the image.
+	offset int
+	// Store header length.
+	header int
+	// Store size (including the header).
+	size int
+}
+
+// Parse the variable store found in a firmware image.
+// Deleted variables are ignored.
+func Parse(image []byte) (store *Store, err error) {
+	store = &Store{image: image}
+	found := false
+	for _, signature := range [][]byte{authenticatedStore[:], variableStore[:], vssSignature} {
+		found = store.locate(signature)
+		if found {
+			break
+		}
+	}
+	if !found {
+		err = liberr.New("Variable store not found.")
+		return
+	}
+	err = store.parse()
+	if err != nil {
+		store = nil
+	}
+	return
+}
+
+// Find a variable.
+func (r *Store) Find(guid GUID, name string) (v *Variable, found bool) {
+	for i := range r.Variables {
+		v = &r.Variables[i]
+		if v.GUID == guid && v.Name == name {
+			found = true
+			return
+		}
+	}
+	v = nil
+	return
+}
+
+// Set a variable.
+// An existing variable with the same name and GUID is replaced.
+func (r *Store) Set(v Variable) {
+	if existing, found := r.Find(v.GUID, v.Name); found {
+		*existing = v
+		return
+	}
+	r.Variables = append(r.Variables, v)
+}
+
+// Delete a variable.
+func (r *Store) Delete(guid GUID, name string) {
+	kept := []Variable{}
+	for _, v := range r.Variables {
+		if v.GUID == guid && v.Name == name {
+			continue
+		}
+		kept = append(kept, v)
+	}
+	r.Variables = kept
+}
+
+// Build the firmware image.
+// The variables are written (compacted) to the store of
+// the parsed image and the remaining space is erased.
+func (r *Store) Image() (image []byte, err error) {
+	image = make([]byte, len(r.image))
+	copy(image, r.image)
+	begin := r.offset + r.header
+	end := r.offset + r.size
+	for i := begin; i < end; i++ {
+		image[i] = erased
+	}
+	pos := begin
+	for _, v := range r.Variables {
+		record := r.encode(v)
+		if pos+len(record) > end {
+			err = liberr.New(
+				"Variable store full.",
+				"variable",
+				v.Name,
+				"size",
+				r.size)
+			return
+		}
+		copy(image[pos:], record)
+		pos = align(pos + len(record))
+	}
+	return
+}
+
+// Locate the store by signature.
+func (r *Store) locate(signature []byte) (found bool) {
+	header := StoreHeaderLength
+	if bytes.Equal(signature, vssSignature) {
+		header = VssHeaderLength
+	}
+	from := 0
+	for {
+		n := bytes.Index(r.image[from:], signature)
+		if n < 0 {
+			return
+		}
+		offset := from + n
+		from = offset + 1
+		if offset+header > len(r.image) {
+			return
+		}
+		sizeAt := offset + len(signature)
+		size := int(binary.LittleEndian.Uint32(r.image[sizeAt:]))
+		format := r.image[sizeAt+4]
+		state := r.image[sizeAt+5]
+		if format != StoreFormatted ||
+			state != StoreHealthy ||
+			size < header ||
+			offset+size > len(r.image) {
+			continue
+		}
+		r.offset = offset
+		r.header = header
+		r.size = size
+		r.Authenticated = bytes.Equal(signature, authenticatedStore[:])
+		found = true
+		return
+	}
+}
+
+// Parse the variables.
+// A variable added (and in deleted transition) is
+// used only when not also found as added.
+func (r *Store) parse() (err error) {
+	headerLength := VariableHeaderLength
+	if r.Authenticated {
+		headerLength = AuthVariableHeaderLength
+	}
+	transition := map[int]bool{}
+	pos := r.offset + r.header
+	end := r.offset + r.size
+	for pos+headerLength <= end {
+		h := r.image[pos:]
+		if binary.LittleEndian.Uint16(h) != StartID {
+			break
+		}
+		state := h[2]
+		v := Variable{
+			Attributes: binary.LittleEndian.Uint32(h[4:]),
+		}
+		var nameSize, dataSize int
+		if r.Authenticated {
+			v.MonotonicCount = binary.LittleEndian.Uint64(h[8:])
+			copy(v.TimeStamp[:], h[16:32])
+			v.PubKeyIndex = binary.LittleEndian.Uint32(h[32:])
+			nameSize = int(binary.LittleEndian.Uint32(h[36:]))
+			dataSize = int(binary.LittleEndian.Uint32(h[40:]))
+			copy(v.GUID[:], h[44:60])
+		} else {
+			nameSize = int(binary.LittleEndian.Uint32(h[8:]))
+			dataSize = int(binary.LittleEndian.Uint32(h[12:]))
+			copy(v.GUID[:], h[16:32])
+		}
+		next := pos + headerLength + nameSize + dataSize
+		if nameSize < 0 || dataSize < 0 || next > end {
+			err = liberr.New(
+				"Variable not valid.",
+				"offset",
+				pos)
+			return
+		}
+		nameAt := pos + headerLength
+		dataAt := nameAt + nameSize
+		if state == VarAdded || state == VarInDeletedTransition {
+			v.Name = decodeName(r.image[nameAt:dataAt])
+			v.Data = make([]byte, dataSize)
+			copy(v.Data, r.image[dataAt:next])
+			r.add(v, state == VarInDeletedTransition, transition)
+		}
+		pos = align(next)
+	}
+	return
+}
+
+// Add a parsed variable.
+func (r *Store) add(v Variable, inTransition bool, transition map[int]bool) {
+	for i := range r.Variables {
+		existing := &r.Variables[i]
+		if existing.GUID != v.GUID || existing.Name != v.Name {
+			continue
+		}
+		if !inTransition || transition[i] {
+			*existing = v
+			transition[i] = inTransition
+		}
+		return
+	}
+	transition[len(r.Variables)] = inTransition
+	r.Variables = append(r.Variables, v)
+}
+
+// Encode a variable record.
+func (r *Store) encode(v Variable) (record []byte) {
+	name := encodeName(v.Name)
+	headerLength := VariableHeaderLength
+	if r.Authenticated {
+		headerLength = AuthVariableHeaderLength
+	}
+	record = make([]byte, headerLength, headerLength+len(name)+len(v.Data))
+	binary.LittleEndian.PutUint16(record[0:], StartID)
+	record[2] = VarAdded
+	binary.LittleEndian.PutUint32(record[4:], v.Attributes)
+	if r.Authenticated {
+		binary.LittleEndian.PutUint64(record[8:], v.MonotonicCount)
+		copy(record[16:32], v.TimeStamp[:])
+		binary.LittleEndian.PutUint32(record[32:], v.PubKeyIndex)
+		binary.LittleEndian.PutUint32(record[36:], uint32(len(name)))
+		binary.LittleEndian.PutUint32(record[40:], uint32(len(v.Data)))
+		copy(record[44:60], v.GUID[:])
+	} else {
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(name)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(v.Data)))
+		copy(record[16:32], v.GUID[:])
+	}
+	record = append(record, name...)
+	record = append(record, v.Data...)
+	return
+}
+
+// Decode a (NUL terminated) UCS-2 name.
+func decodeName(b []byte) string {
+	u := []uint16{}
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
+
+// Encode a (NUL terminated) UCS-2 name.
+func encodeName(s string) (b []byte) {
+	for _, c := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(c), byte(c>>8))
+	}
+	b = append(b, 0, 0)
+	return
+}
+
+// Align a variable header offset.
+func align(n int) int {
+	return (n + 3) &^ 3
+}
@@ -0,0 +1,160 @@
+package efi
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+// Build a firmware image with a variable store.
+func storeImage(signature []byte, header, size int, records ...[]byte) (image []byte) {
+	image = make([]byte, 0x40+size+0x20)
+	for i := range image {
+		image[i] = erased
+	}
+	offset := 0x40
+	copy(image[offset:], signature)
+	sizeAt := offset + len(signature)
+	binary.LittleEndian.PutUint32(image[sizeAt:], uint32(size))
+	image[sizeAt+4] = StoreFormatted
+	image[sizeAt+5] = StoreHealthy
+	for i := sizeAt + 6; i < offset+header; i++ {
+		image[i] = 0
+	}
+	pos := offset + header
+	for _, record := range records {
+		copy(image[pos:], record)
+		pos = align(pos + len(record))
+	}
+	return
+}
+
+// Build a variable record.
+func record(authenticated bool, state byte, v Variable) (b []byte) {
+	store := Store{Authenticated: authenticated}
+	b = store.encode(v)
+	b[2] = state
+	return
+}
+
+func TestGUID(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	guid, err := ParseGUID("8BE4DF61-93CA-11D2-AA0D-00E098032B8C")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(guid[:4]).To(gomega.Equal([]byte{0x61, 0xdf, 0xe4, 0x8b}))
+	g.Expect(guid[4:6]).To(gomega.Equal([]byte{0xca, 0x93}))
+	g.Expect(guid[8:10]).To(gomega.Equal([]byte{0xaa, 0x0d}))
+	g.Expect(guid.String()).To(gomega.Equal("8be4df61-93ca-11d2-aa0d-00e098032b8c"))
+	g.Expect(guid).To(gomega.Equal(GlobalVariable))
+
+	_, err = ParseGUID("8be4df61-93ca-11d2-aa0d")
+	g.Expect(err).ToNot(gomega.BeNil())
+	_, err = ParseGUID("8be4df61-93ca-11d2-aa0d-00e098032bzz")
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestParse(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	bootOrder := Variable{
+		Name:           "BootOrder",
+		GUID:           GlobalVariable,
+		Attributes:     0x7,
+		MonotonicCount: 1,
+		Data:           []byte{1, 0},
+	}
+	db := Variable{
+		Name:       "db",
+		GUID:       ImageSecurityDatabase,
+		Attributes: 0x27,
+		Data:       []byte{1, 2, 3},
+	}
+	deleted := Variable{
+		Name: "Deleted",
+		GUID: GlobalVariable,
+		Data: []byte{1},
+	}
+	replaced := bootOrder
+	replaced.Data = []byte{2, 0}
+	image := storeImage(
+		authenticatedStore[:], StoreHeaderLength, 0x400,
+		record(true, VarAdded, bootOrder),
+		record(true, 0x3c, deleted),
+		record(true, VarAdded, db),
+		record(true, VarInDeletedTransition, replaced))
+
+	store, err := Parse(image)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(store.Authenticated).To(gomega.BeTrue())
+	g.Expect(store.Variables).To(gomega.Equal([]Variable{bootOrder, db}))
+
+	// Framework ($VSS) store.
+	image = storeImage(
+		vssSignature, VssHeaderLength, 0x400,
+		record(false, VarAdded, db))
+	store, err = Parse(image)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(store.Authenticated).To(gomega.BeFalse())
+	g.Expect(store.Variables).To(gomega.Equal([]Variable{db}))
+
+	// Not formatted.
+	image = storeImage(variableStore[:], StoreHeaderLength, 0x400)
+	image[0x40+20] = 0
+	_, err = Parse(image)
+	g.Expect(err).ToNot(gomega.BeNil())
+
+	// Truncated.
+	image = storeImage(
+		variableStore[:], StoreHeaderLength, 0x400,
+		record(false, VarAdded, db))
+	binary.LittleEndian.PutUint32(image[0x40+StoreHeaderLength+12:], 0x1000)
+	_, err = Parse(image)
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestImage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	pk := Variable{
+		Name:       "PK",
+		GUID:       GlobalVariable,
+		Attributes: 0x27,
+		Data:       []byte{1, 2, 3, 4, 5},
+	}
+	image := storeImage(
+		authenticatedStore[:], StoreHeaderLength, 0x100,
+		record(true, VarAdded, pk))
+	store, err := Parse(image)
+	g.Expect(err).To(gomega.BeNil())
+
+	// Replace, add and delete.
+	pk.Data = []byte{6}
+	store.Set(pk)
+	boot := Variable{
+		Name:       "Boot0001",
+		GUID:       GlobalVariable,
+		Attributes: 0x7,
+		Data:       []byte{7, 8, 9},
+	}
+	store.Set(boot)
+	store.Set(Variable{Name: "Removed", GUID: Microsoft})
+	store.Delete(Microsoft, "Removed")
+	_, found := store.Find(Microsoft, "Removed")
+	g.Expect(found).To(gomega.BeFalse())
+
+	built, err := store.Image()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(built)).To(gomega.Equal(len(image)))
+	g.Expect(built[:0x40]).To(gomega.Equal(image[:0x40]))
+	g.Expect(built[0x40+0x100:]).To(gomega.Equal(image[0x40+0x100:]))
+	reparsed, err := Parse(built)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(reparsed.Variables).To(gomega.Equal([]Variable{pk, boot}))
+
+	// Full.
+	store.Set(Variable{Name: "Large", GUID: Microsoft, Data: make([]byte, 0x100)})
+	_, err = store.Image()
+	g.Expect(err).ToNot(gomega.BeNil())
+}
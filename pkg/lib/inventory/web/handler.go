@@ -91,6 +91,7 @@ func (c *Parity) EnsureParity(r container.Collector, w time.Duration) int {
 }
 
 // Watched resource builder.
+// Events for which nil is returned are not sent.
 type ResourceBuilder func(model.Model) interface{}
 
 // Event
@@ -267,6 +268,10 @@ func (r *WatchWriter) send(e model.Event) {
 	}
 	if e.Model != nil {
 		event.Resource = r.builder(e.Model)
+		if event.Resource == nil {
+			// Filtered by the builder.
+			return
+		}
 	}
 	if e.Updated != nil {
 		event.Updated = r.builder(e.Updated)
@@ -8,12 +8,16 @@ import (
 
 // Environment variables.
 const (
-	MaxVmInFlight         = "MAX_VM_INFLIGHT"
-	HookRetry             = "HOOK_RETRY"
-	ImporterRetry         = "IMPORTER_RETRY"
-	VirtV2vImage          = "VIRT_V2V_IMAGE"
-	PrecopyInterval       = "PRECOPY_INTERVAL"
-	VirtV2vDontRequestKVM = "VIRT_V2V_DONT_REQUEST_KVM"
+	MaxVmInFlight          = "MAX_VM_INFLIGHT"
+	HookRetry              = "HOOK_RETRY"
+	ImporterRetry          = "IMPORTER_RETRY"
+	VirtV2vImage           = "VIRT_V2V_IMAGE"
+	PrecopyInterval        = "PRECOPY_INTERVAL"
+	PrecopyIntervalMin     = "PRECOPY_INTERVAL_MIN"
+	PrecopyIntervalMax     = "PRECOPY_INTERVAL_MAX"
+	PrecopyReadyThreshold  = "PRECOPY_READY_THRESHOLD"
+	VirtV2vDontRequestKVM  = "VIRT_V2V_DONT_REQUEST_KVM"
+	VirtV2vPermittedImages = "VIRT_V2V_PERMITTED_IMAGES"
 )
 
 // Default virt-v2v image.
@@ -31,11 +35,18 @@ type Migration struct {
 	ImporterRetry int
 	// Warm migration precopy interval in minutes
 	PrecopyInterval int
+	// Adaptive precopy interval bounds in minutes
+	PrecopyIntervalMin int
+	PrecopyIntervalMax int
+	// Precopy delta (MB) at or below which a VM is ready for cutover
+	PrecopyReadyThreshold int
 	// Virt-v2v images for guest conversion
 	VirtV2vImageCold string
 	VirtV2vImageWarm string
 	// Virt-v2v require KVM flags for guest conversion
 	VirtV2vDontRequestKVM bool
+	// Virt-v2v images permitted to be specified by plans.
+	VirtV2vPermittedImages map[string]bool
 }
 
 // Load settings.
@@ -56,6 +67,18 @@ func (r *Migration) Load() (err error) {
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
+	r.PrecopyIntervalMin, err = getEnvLimit(PrecopyIntervalMin, 5)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.PrecopyIntervalMax, err = getEnvLimit(PrecopyIntervalMax, 240)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.PrecopyReadyThreshold, err = getEnvLimit(PrecopyReadyThreshold, 1024)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
 	if virtV2vImage, ok := os.LookupEnv(VirtV2vImage); ok {
 		if cold, warm, found := strings.Cut(virtV2vImage, "|"); found {
 			r.VirtV2vImageCold = cold
@@ -69,5 +92,14 @@ func (r *Migration) Load() (err error) {
 		r.VirtV2vImageWarm = DefaultVirtV2vImage
 	}
 	r.VirtV2vDontRequestKVM = getEnvBool(VirtV2vDontRequestKVM, false)
+	r.VirtV2vPermittedImages = map[string]bool{}
+	if s, found := os.LookupEnv(VirtV2vPermittedImages); found {
+		for _, image := range strings.Split(s, ",") {
+			image = strings.TrimSpace(image)
+			if len(image) > 0 {
+				r.VirtV2vPermittedImages[image] = true
+			}
+		}
+	}
 	return
 }
@@ -10,6 +10,7 @@ const (
 	PolicyAgentCA             = "POLICY_AGENT_CA"
 	PolicyAgentWorkerLimit    = "POLICY_AGENT_WORKER_LIMIT"
 	PolicyAgentSearchInterval = "POLICY_AGENT_SEARCH_INTERVAL"
+	PolicyAgentNamespace      = "POD_NAMESPACE"
 )
 
 // Policy agent settings.
@@ -23,6 +24,9 @@ type PolicyAgent struct {
 	}
 	// Search interval (seconds).
 	SearchInterval int
+	// Namespace containing the custom
+	// policy ConfigMaps.
+	Namespace string
 	// Limits.
 	Limit struct {
 		// Number of workers.
@@ -49,6 +53,9 @@ func (r *PolicyAgent) Load() (err error) {
 	if err != nil {
 		return err
 	}
+	if s, found := os.LookupEnv(PolicyAgentNamespace); found {
+		r.Namespace = s
+	}
 
 	return
 }
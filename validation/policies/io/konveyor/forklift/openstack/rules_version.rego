@@ -1,5 +1,5 @@
 package io.konveyor.forklift.openstack
 
-RULES_VERSION := 6
+RULES_VERSION := 7
 
 rules_version = {"rules_version": RULES_VERSION}
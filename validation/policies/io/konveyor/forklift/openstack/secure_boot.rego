@@ -11,8 +11,8 @@ secure_boot_enabled if input.flavor.extraSpecs["os:secure_boot"] == "required"
 concerns[flag] {
 	secure_boot_enabled
 	flag := {
-		"category": "Warning",
+		"category": "Information",
 		"label": "UEFI secure boot detected",
-		"assessment": "UEFI secure boot is currently only partially supported by OpenShift Virtualization. Some functionality may be missing after the VM is migrated.",
+		"assessment": "UEFI secure boot will be enabled on the migrated VM. The EFI variables (NVRAM) are not migrated, so the VM starts with the default secure boot keys enrolled. Custom keys or boot entries must be enrolled again after migration.",
 	}
 }
@@ -0,0 +1,19 @@
+package io.konveyor.forklift.openstack
+
+import future.keywords.if
+import future.keywords.in
+
+default has_tpm_enabled = false
+
+has_tpm_enabled if "hw:tpm_version" in object.keys(input.flavor.extraSpecs)
+
+has_tpm_enabled if "hw_tpm_version" in object.keys(input.image.properties)
+
+concerns[flag] {
+	has_tpm_enabled
+	flag := {
+		"category": "Warning",
+		"label": "vTPM detected",
+		"assessment": "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration.",
+	}
+}
@@ -0,0 +1,25 @@
+package io.konveyor.forklift.openstack
+
+test_with_flavor_tpm {
+	mock_vm := {
+		"name": "test",
+		"flavor": {"extraSpecs": {"hw:tpm_version": "2.0"}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_image_tpm {
+	mock_vm := {
+		"name": "test",
+		"image": {"properties": {"hw_tpm_version": "2.0"}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_without_tpm {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
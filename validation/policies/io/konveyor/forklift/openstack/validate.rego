@@ -3,10 +3,15 @@ package io.konveyor.forklift.openstack
 validate = {
 	"rules_version": RULES_VERSION,
 	"errors": errors,
-	"concerns": concerns,
+	"concerns": concerns | custom_concerns,
 }
 
 errors[message] {
 	not valid_vm_string
 	message := "No VM name found in input body"
 }
+
+# Concerns defined by the custom policies.
+custom_concerns[flag] {
+	flag := data.io.konveyor.forklift.custom.openstack.concerns[_]
+}
@@ -0,0 +1,15 @@
+package io.konveyor.forklift.ovirt
+
+lun_disks [i] {
+    some i
+    input.diskAttachments[i].disk.storageType == "lun"
+}
+
+concerns[flag] {
+    count(lun_disks) > 0
+    flag := {
+        "category": "Warning",
+        "label": "Direct LUN disk detected",
+        "assessment": "Direct LUN disks are migrated only when the plan copies the LUN contents into block PVCs or maps the LUN to a pre-provisioned block persistent volume. Otherwise, the VM cannot be migrated."
+    }
+}
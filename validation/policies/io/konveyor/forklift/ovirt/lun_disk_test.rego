@@ -0,0 +1,45 @@
+package io.konveyor.forklift.ovirt
+
+test_without_lun_disk {
+    mock_vm := {
+        "name": "test",
+        "diskAttachments": [
+            {
+              "id": "b749c132-bb97-4145-b86e-a1751cf75e21",
+              "interface": "virtio_scsi",
+              "disk":
+                { "storageType": "image",
+                  "status": "ok"
+                }
+            }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_with_lun_disk {
+    mock_vm := {
+        "name": "test",
+        "diskAttachments": [
+            {
+              "id": "b749c132-bb97-4145-b86e-a1751cf75e21",
+              "interface": "virtio_scsi",
+              "disk":
+                { "storageType": "image",
+                  "status": "ok"
+                }
+            },
+            {
+              "id": "b749c132-bb97-4145-b86e-a1751cf75e22",
+              "interface": "virtio_scsi",
+              "disk":
+                { "storageType": "lun",
+                  "status": "ok"
+                }
+            }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
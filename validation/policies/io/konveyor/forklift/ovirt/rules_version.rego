@@ -1,6 +1,6 @@
 package io.konveyor.forklift.ovirt
 
-RULES_VERSION := 6
+RULES_VERSION := 9
 
 rules_version = {
     "rules_version": RULES_VERSION
@@ -9,7 +9,7 @@ concerns[flag] {
     count(disks_with_scsi_reservation) > 0
     flag := {
         "category": "Warning",
-        "label": "Shared disk detected",
-        "assessment": "The VM has a disk that is shared. Shared disks are not currently supported by OpenShift Virtualization."
+        "label": "SCSI reservation detected",
+        "assessment": "The VM has a disk with SCSI reservation enabled. SCSI reservations are not migrated. The shared disk is attached to the target VMs without SCSI reservation support."
     }
 }
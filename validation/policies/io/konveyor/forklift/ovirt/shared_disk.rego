@@ -10,6 +10,6 @@ concerns[flag] {
     flag := {
         "category": "Warning",
         "label": "Shared disk detected",
-        "assessment": "The VM has a disk that is shared. Shared disks are not currently supported by OpenShift Virtualization."
+        "assessment": "The VM has a disk that is shared. Shared disks are migrated once and attached to each VM in the plan that shares the disk. The disk is copied to a ReadWriteMany block volume. Ensure that the selected storage class supports it and that all of the VMs sharing the disk are included in the plan."
     }
 }
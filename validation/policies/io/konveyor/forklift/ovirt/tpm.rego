@@ -0,0 +1,16 @@
+package io.konveyor.forklift.ovirt
+
+default has_tpm_enabled = false
+
+has_tpm_enabled = value {
+    value := input.tpmEnabled
+}
+
+concerns[flag] {
+    has_tpm_enabled
+    flag := {
+        "category": "Warning",
+        "label": "vTPM detected",
+        "assessment": "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration."
+    }
+}
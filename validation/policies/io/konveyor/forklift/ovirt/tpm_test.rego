@@ -0,0 +1,17 @@
+package io.konveyor.forklift.ovirt
+ 
+test_without_tpm_enabled {
+    mock_vm := { "name": "test",
+                 "tpmEnabled": false
+                }
+    results = concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_with_tpm_enabled {
+    mock_vm := { "name": "test",
+                 "tpmEnabled": true
+                }
+    results = concerns with input as mock_vm
+    count(results) == 1
+}
@@ -8,8 +8,8 @@ has_rdm_disk {
 concerns[flag] {
     has_rdm_disk
     flag := {
-        "category": "Critical",
+        "category": "Warning",
         "label": "Raw Device Mapped disk detected",
-        "assessment": "RDM disks are not currently supported by OpenShift Virtualization. The VM cannot be migrated"
+        "assessment": "RDM disks are migrated only when the plan copies the LUN contents into block PVCs or maps the LUN to a pre-provisioned block persistent volume. Otherwise, the VM cannot be migrated."
     }
 }
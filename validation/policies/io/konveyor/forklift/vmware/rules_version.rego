@@ -1,6 +1,6 @@
 package io.konveyor.forklift.vmware
 
-RULES_VERSION := 5
+RULES_VERSION := 8
 
 rules_version = {
     "rules_version": RULES_VERSION
@@ -10,6 +10,6 @@ concerns[flag] {
     flag := {
         "category": "Warning",
         "label": "Shareable disk detected",
-        "assessment": "Shared disks are only supported by certain OpenShift Virtualization storage configurations. Ensure that the correct storage is selected for the disk."
+        "assessment": "Shared disks are migrated once and attached to each VM in the plan that shares the disk. The disk is copied to a ReadWriteMany block volume. Ensure that the selected storage class supports it and that all of the VMs sharing the disk are included in the plan."
     }
 }
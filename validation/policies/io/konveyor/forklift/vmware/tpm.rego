@@ -0,0 +1,14 @@
+package io.konveyor.forklift.vmware
+
+has_tpm {
+    input.tpmEnabled
+}
+
+concerns[flag] {
+    has_tpm
+    flag := {
+        "category": "Warning",
+        "label": "vTPM detected",
+        "assessment": "A persistent vTPM will be provisioned on the migrated VM, which requires persistent VM state to be enabled in OpenShift Virtualization. The vTPM state is not migrated, so secrets sealed by the TPM, such as BitLocker keys, must be recovered after migration."
+    }
+}
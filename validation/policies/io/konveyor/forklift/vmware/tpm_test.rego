@@ -0,0 +1,13 @@
+package io.konveyor.forklift.vmware
+ 
+test_without_tpm {
+    mock_vm := { "name": "test", "tpmEnabled": false }
+    results = concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_with_tpm {
+    mock_vm := { "name": "test", "tpmEnabled": true }
+    results = concerns with input as mock_vm
+    count(results) == 1
+}
@@ -1,14 +1,15 @@
 package io.konveyor.forklift.vmware
 
-has_uefi_boot {
+has_secure_boot {
     input.firmware == "efi"
+    input.secureBoot
 }
 
 concerns[flag] {
-    has_uefi_boot
+    has_secure_boot
     flag := {
-        "category": "Warning",
-        "label": "UEFI detected",
-        "assessment": "UEFI secure boot will be disabled on OpenShift Virtualization. If the VM was set with UEFI secure boot, manual steps within the guest would be needed for the guest operating system to boot."
+        "category": "Information",
+        "label": "UEFI secure boot detected",
+        "assessment": "UEFI secure boot will be enabled on the migrated VM. The EFI variables (NVRAM) are not migrated, so the VM starts with the default secure boot keys enrolled. Custom keys or boot entries must be enrolled again after migration."
     }
 }
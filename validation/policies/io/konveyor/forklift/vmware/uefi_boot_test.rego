@@ -7,7 +7,13 @@ test_without_uefi_boot {
 }
 
 test_with_uefi_boot {
-    mock_vm := { "name": "test", "firmware": "efi" }
+    mock_vm := { "name": "test", "firmware": "efi", "secureBoot": false }
+    results = concerns with input as mock_vm
+    count(results) == 0
+}
+
+test_with_uefi_secure_boot {
+    mock_vm := { "name": "test", "firmware": "efi", "secureBoot": true }
     results = concerns with input as mock_vm
     count(results) == 1
 }
@@ -3,10 +3,15 @@ package io.konveyor.forklift.vmware
 validate = {
     "rules_version": RULES_VERSION,
     "errors": errors,
-    "concerns": concerns
+    "concerns": concerns | custom_concerns
 }
 
 errors[message] {
     not valid_vm
     message := "No VM name found in input body"
-}
\ No newline at end of file
+}
+
+# Concerns defined by the custom policies.
+custom_concerns[flag] {
+    flag := data.io.konveyor.forklift.custom.vmware.concerns[_]
+}